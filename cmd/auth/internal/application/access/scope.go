@@ -1,5 +1,9 @@
 package access
 
+import (
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
 type Scope string
 
 const (
@@ -7,3 +11,23 @@ const (
 	ScopeUserRead  Scope = "user_read"
 	ScopeUserWrite Scope = "user_write"
 )
+
+// ScopeRegistry maps scopes to permissions granted to access tokens
+type ScopeRegistry map[Scope]identity.Permissions
+
+// Scopes clients can be granted
+var Scopes = ScopeRegistry{
+	ScopeAll:       identity.NewPermissions(identity.PermissionUserRead, identity.PermissionUserWrite),
+	ScopeUserRead:  identity.NewPermissions(identity.PermissionUserRead),
+	ScopeUserWrite: identity.NewPermissions(identity.PermissionUserWrite),
+}
+
+// Permissions returns permissions granted by scopes, unknown scopes grant nothing
+func (r ScopeRegistry) Permissions(scopes []string) identity.Permissions {
+	var permissions identity.Permissions
+	for _, scope := range scopes {
+		permissions = permissions.Add(r[Scope(scope)]...)
+	}
+
+	return permissions
+}
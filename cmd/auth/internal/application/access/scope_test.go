@@ -0,0 +1,27 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+func TestScopeRegistry_Permissions(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		want   identity.Permissions
+	}{
+		{"none", nil, nil},
+		{"unknown", []string{"admin"}, nil},
+		{"all", []string{"all"}, identity.NewPermissions(identity.PermissionUserRead, identity.PermissionUserWrite)},
+		{"many", []string{"user_read", "user_write"}, identity.NewPermissions(identity.PermissionUserRead, identity.PermissionUserWrite)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Scopes.Permissions(tt.scopes); got.String() != tt.want.String() {
+				t.Errorf("Permissions() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,7 +17,8 @@ type Config struct {
 		AuthorizeURL    string        `env:"AUTH_AUTHORIZE_URL"   envDefault:"http://localhost:3000/authorize"`
 	}
 	OAuth struct {
-		InitTimeout time.Duration `env:"OAUTH_INIT_TIMEOUT" envDefault:"15s"`
+		InitTimeout         time.Duration `env:"OAUTH_INIT_TIMEOUT"            envDefault:"15s"`
+		LoginAccessTokenTTL time.Duration `env:"OAUTH_LOGIN_ACCESS_TOKEN_TTL" envDefault:"15m"` // lifetime of access token issued for login code, refresh token is issued along
 	}
 	Debug struct {
 		Host string `env:"DEBUG_HOST" envDefault:"0.0.0.0"`
@@ -43,6 +44,14 @@ type Config struct {
 		ConnTime      time.Duration `env:"GRPC_CONN_TIME"       envDefault:"10s"` // send pings every 10 seconds if there is no activity
 		ConnTimeout   time.Duration `env:"GRPC_CONN_TIMEOUT"    envDefault:"20s"` // wait 20 second for ping ack before considering the connection dead
 	}
+	User struct {
+		Host string `env:"USER_HOST" envDefault:"0.0.0.0"` // User service host
+	}
+	PubSub struct {
+		Host           string        `env:"PUBSUB_HOST"            envDefault:"0.0.0.0"` // PubSub service host, integration events are exchanged through it
+		Port           int           `env:"PUBSUB_PORT"            envDefault:"3001"`
+		HandlerTimeout time.Duration `env:"PUBSUB_HANDLER_TIMEOUT" envDefault:"120s"`
+	}
 	MongoDB struct {
 		User     string `env:"MONGO_USER"     envDefault:"root"`
 		Pass     string `env:"MONGO_PASS"     envDefault:"password"`
@@ -62,11 +71,22 @@ type Config struct {
 		MaxOpenConns    int           `env:"MYSQL_MAX_OPEN_CONNS"    envDefault:"5"`  // sets the maximum number of connections in the idle
 	}
 	CommandBus struct {
-		QueueSize int `env:"COMMAND_BUS_BUFFER" envDefault:"100"`
+		QueueSize      int           `env:"COMMAND_BUS_BUFFER"          envDefault:"100"`
+		HandlerTimeout time.Duration `env:"COMMAND_BUS_HANDLER_TIMEOUT" envDefault:"30s"`
 	}
 	EventBus struct {
 		QueueSize int `env:"COMMAND_BUS_BUFFER" envDefault:"100"`
 	}
+	Execution struct {
+		TTL time.Duration `env:"COMMAND_EXECUTION_TTL" envDefault:"24h"` // how long status of asynchronously dispatched command is kept
+	}
+	Idempotency struct {
+		TTL time.Duration `env:"IDEMPOTENCY_KEY_TTL" envDefault:"24h"` // how long outcome of request is replayed for the same idempotency key
+	}
+	Scheduler struct {
+		PollInterval time.Duration `env:"COMMAND_SCHEDULER_POLL_INTERVAL" envDefault:"1s"` // how often due commands are looked up
+		Lease        time.Duration `env:"COMMAND_SCHEDULER_LEASE"         envDefault:"1m"` // how long claimed command is locked for other replicas, should exceed COMMAND_BUS_HANDLER_TIMEOUT
+	}
 }
 
 func FromEnv() *Config {
@@ -87,6 +107,12 @@ func FromEnv() *Config {
 	if err := env.Parse(&c.GRPC); err != nil {
 		panic(err)
 	}
+	if err := env.Parse(&c.User); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.PubSub); err != nil {
+		panic(err)
+	}
 	if err := env.Parse(&c.MongoDB); err != nil {
 		panic(err)
 	}
@@ -99,6 +125,15 @@ func FromEnv() *Config {
 	if err := env.Parse(&c.EventBus); err != nil {
 		panic(err)
 	}
+	if err := env.Parse(&c.Execution); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.Idempotency); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.Scheduler); err != nil {
+		panic(err)
+	}
 
 	if c.CommandBus.QueueSize == 0 {
 		c.CommandBus.QueueSize = runtime.NumCPU()
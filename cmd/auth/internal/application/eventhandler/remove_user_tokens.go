@@ -0,0 +1,57 @@
+package eventhandler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/token"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/executioncontext"
+)
+
+// removeUserTokens removes every token issued for user,
+// removed tokens no longer pass bearer token validation
+func removeUserTokens(ctx context.Context, tokens persistence.TokenRepository, tokenRepository token.Repository, userID string) error {
+	// ids are collected before removing anything,
+	// read models are updated asynchronously so paging while removing would skip entries
+	var tokenIDs []uuid.UUID
+	for offset := int64(0); ; offset += userDataBatchSize {
+		page, err := tokens.FindAllByUserID(ctx, userID, userDataBatchSize, offset)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+		for _, t := range page {
+			id, err := uuid.Parse(t.GetID())
+			if err != nil {
+				return apperrors.Wrap(err)
+			}
+			tokenIDs = append(tokenIDs, id)
+		}
+		if len(page) < userDataBatchSize {
+			break
+		}
+	}
+
+	liveCtx := executioncontext.WithFlag(ctx, executioncontext.LIVE)
+
+	for _, id := range tokenIDs {
+		t, err := tokenRepository.Get(ctx, id)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+		if err := t.Remove(ctx); err != nil {
+			return apperrors.Wrap(err)
+		}
+		if err := tokenRepository.Save(liveCtx, t); err != nil {
+			return apperrors.Wrap(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package eventhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/token"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence"
+	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+)
+
+// WhenUserRoleWasRevoked handles integration event published by user service,
+// removes every token of the user as tokens carry roles granted when they were issued
+func WhenUserRoleWasRevoked(tokens persistence.TokenRepository, tokenRepository token.Repository) eventbus.EventHandler {
+	fn := func(parentCtx context.Context, event *domain.Event) error {
+		ctx, cancel := context.WithTimeout(parentCtx, time.Second*120)
+		defer cancel()
+
+		e := event.Payload.(*userproto.UserRoleWasRevoked)
+
+		if err := removeUserTokens(ctx, tokens, tokenRepository, e.UserID.String()); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
@@ -0,0 +1,83 @@
+package eventhandler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/client"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/token"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence"
+	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+	"github.com/vardius/go-api-boilerplate/pkg/executioncontext"
+)
+
+const userDataBatchSize = 100
+
+// WhenUserWasDeleted handles integration event published by user service,
+// removes every client and token owned by deleted user
+func WhenUserWasDeleted(
+	clients persistence.ClientRepository,
+	tokens persistence.TokenRepository,
+	clientRepository client.Repository,
+	tokenRepository token.Repository,
+) eventbus.EventHandler {
+	fn := func(parentCtx context.Context, event *domain.Event) error {
+		ctx, cancel := context.WithTimeout(parentCtx, time.Second*120)
+		defer cancel()
+
+		e := event.Payload.(*userproto.UserWasDeleted)
+		userID := e.UserID.String()
+
+		// ids are collected before removing anything,
+		// read models are updated asynchronously so paging while removing would skip entries
+		var clientIDs []uuid.UUID
+		for offset := int64(0); ; offset += userDataBatchSize {
+			page, err := clients.FindAllByUserID(ctx, userID, userDataBatchSize, offset)
+			if err != nil {
+				return apperrors.Wrap(err)
+			}
+			for _, c := range page {
+				id, err := uuid.Parse(c.GetID())
+				if err != nil {
+					return apperrors.Wrap(err)
+				}
+				clientIDs = append(clientIDs, id)
+			}
+			if len(page) < userDataBatchSize {
+				break
+			}
+		}
+
+		if err := removeUserTokens(ctx, tokens, tokenRepository, userID); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		liveCtx := executioncontext.WithFlag(ctx, executioncontext.LIVE)
+
+		for _, id := range clientIDs {
+			c, err := clientRepository.Get(ctx, id)
+			if errors.Is(err, apperrors.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return apperrors.Wrap(err)
+			}
+			if err := c.Remove(ctx); err != nil {
+				return apperrors.Wrap(err)
+			}
+			if err := clientRepository.Save(liveCtx, c); err != nil {
+				return apperrors.Wrap(err)
+			}
+		}
+
+		return nil
+	}
+
+	return fn
+}
@@ -11,11 +11,20 @@ import (
 	persistence "github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence/memory"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/repository"
 	authproto "github.com/vardius/go-api-boilerplate/cmd/auth/proto"
+	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
 	"github.com/vardius/go-api-boilerplate/pkg/auth"
 	memorycommandbus "github.com/vardius/go-api-boilerplate/pkg/commandbus/memory"
+	commandbusmiddleware "github.com/vardius/go-api-boilerplate/pkg/commandbus/middleware"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
 	memoryeventbus "github.com/vardius/go-api-boilerplate/pkg/eventbus/memory"
+	pubsubeventbus "github.com/vardius/go-api-boilerplate/pkg/eventbus/pubsub"
 	memoryeventstore "github.com/vardius/go-api-boilerplate/pkg/eventstore/memory"
+	memoryexecution "github.com/vardius/go-api-boilerplate/pkg/execution/memory"
 	grpcutils "github.com/vardius/go-api-boilerplate/pkg/grpc"
+	memoryidempotency "github.com/vardius/go-api-boilerplate/pkg/idempotency/memory"
+	"github.com/vardius/go-api-boilerplate/pkg/scheduler"
+	memoryscheduler "github.com/vardius/go-api-boilerplate/pkg/scheduler/memory"
+	pubsubproto "github.com/vardius/pubsub/v2/proto"
 )
 
 func init() {
@@ -23,7 +32,15 @@ func init() {
 }
 
 func newMemoryServiceContainer(ctx context.Context, cfg *config.Config) (*ServiceContainer, error) {
-	commandBus := memorycommandbus.New(cfg.CommandBus.QueueSize)
+	commandBus := memorycommandbus.New(
+		cfg.CommandBus.QueueSize,
+		commandbusmiddleware.Recover(),
+		commandbusmiddleware.Logger(),
+		commandbusmiddleware.Metrics(),
+		commandbusmiddleware.Timeout(cfg.CommandBus.HandlerTimeout),
+		commandbusmiddleware.Validate(),
+	)
+	commandRegistry := registry.New(commandBus)
 	grpcAuthConn := grpcutils.NewConnection(
 		ctx,
 		cfg.GRPC.Host,
@@ -33,8 +50,30 @@ func newMemoryServiceContainer(ctx context.Context, cfg *config.Config) (*Servic
 			ConnTimeout: cfg.GRPC.ConnTimeout,
 		},
 	)
+	grpcUserConn := grpcutils.NewConnection(
+		ctx,
+		cfg.User.Host,
+		cfg.GRPC.Port,
+		grpcutils.ConnectionConfig{
+			ConnTime:    cfg.GRPC.ConnTime,
+			ConnTimeout: cfg.GRPC.ConnTimeout,
+		},
+	)
+	grpcPubSubConn := grpcutils.NewConnection(
+		ctx,
+		cfg.PubSub.Host,
+		cfg.PubSub.Port,
+		grpcutils.ConnectionConfig{
+			ConnTime:    cfg.GRPC.ConnTime,
+			ConnTimeout: cfg.GRPC.ConnTimeout,
+		},
+	)
 	eventStore := memoryeventstore.New()
 	eventBus := memoryeventbus.New(cfg.EventBus.QueueSize)
+	integrationEventBus := pubsubeventbus.New(cfg.PubSub.HandlerTimeout, pubsubproto.NewPubSubClient(grpcPubSubConn))
+	executionStore := memoryexecution.New()
+	idempotencyStore := memoryidempotency.New()
+	commandScheduler := scheduler.New(commandBus, memoryscheduler.New(), cfg.Scheduler.PollInterval, cfg.Scheduler.Lease)
 	tokenRepository := repository.NewTokenRepository(eventStore, eventBus)
 	clientRepository := repository.NewClientRepository(eventStore, eventBus)
 	tokenPersistenceRepository := persistence.NewTokenRepository()
@@ -42,16 +81,25 @@ func newMemoryServiceContainer(ctx context.Context, cfg *config.Config) (*Servic
 	tokenStore := appoauth2.NewTokenStore(tokenPersistenceRepository, tokenRepository)
 	authenticator := auth.NewSecretAuthenticator([]byte(cfg.App.Secret))
 	grpAuthClient := authproto.NewAuthenticationServiceClient(grpcAuthConn)
+	grpcUserClient := userproto.NewUserServiceClient(grpcUserConn)
 	claimsProvider := auth.NewClaimsProvider(authenticator)
 	manager := appoauth2.NewManager(tokenStore, clientPersistenceRepository, authenticator, clientPersistenceRepository)
 	tokenAuthorizer := auth.NewJWTTokenAuthorizer(grpAuthClient, claimsProvider, authenticator)
 
 	return &ServiceContainer{
 		CommandBus:                  commandBus,
+		CommandRegistry:             commandRegistry,
 		EventBus:                    eventBus,
+		IntegrationEventBus:         integrationEventBus,
+		ExecutionStore:              executionStore,
+		IdempotencyStore:            idempotencyStore,
+		CommandScheduler:            commandScheduler,
 		Authenticator:               authenticator,
 		OAuth2Manager:               manager,
 		AuthConn:                    grpcAuthConn,
+		UserConn:                    grpcUserConn,
+		PubSubConn:                  grpcPubSubConn,
+		UserClient:                  grpcUserClient,
 		TokenAuthorizer:             tokenAuthorizer,
 		TokenRepository:             tokenRepository,
 		ClientRepository:            clientRepository,
@@ -12,12 +12,21 @@ import (
 	persistence "github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence/mongo"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/repository"
 	authproto "github.com/vardius/go-api-boilerplate/cmd/auth/proto"
+	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
 	"github.com/vardius/go-api-boilerplate/pkg/auth"
 	memorycommandbus "github.com/vardius/go-api-boilerplate/pkg/commandbus/memory"
+	commandbusmiddleware "github.com/vardius/go-api-boilerplate/pkg/commandbus/middleware"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	memoryeventbus "github.com/vardius/go-api-boilerplate/pkg/eventbus/memory"
+	pubsubeventbus "github.com/vardius/go-api-boilerplate/pkg/eventbus/pubsub"
 	mongoeventstore "github.com/vardius/go-api-boilerplate/pkg/eventstore/mongo"
+	mongoexecution "github.com/vardius/go-api-boilerplate/pkg/execution/mongo"
 	grpcutils "github.com/vardius/go-api-boilerplate/pkg/grpc"
+	mongoidempotency "github.com/vardius/go-api-boilerplate/pkg/idempotency/mongo"
+	"github.com/vardius/go-api-boilerplate/pkg/scheduler"
+	mongoscheduler "github.com/vardius/go-api-boilerplate/pkg/scheduler/mongo"
+	pubsubproto "github.com/vardius/pubsub/v2/proto"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -27,7 +36,15 @@ func init() {
 }
 
 func newMYSQLServiceContainer(ctx context.Context, cfg *config.Config) (*ServiceContainer, error) {
-	commandBus := memorycommandbus.New(cfg.CommandBus.QueueSize)
+	commandBus := memorycommandbus.New(
+		cfg.CommandBus.QueueSize,
+		commandbusmiddleware.Recover(),
+		commandbusmiddleware.Logger(),
+		commandbusmiddleware.Metrics(),
+		commandbusmiddleware.Timeout(cfg.CommandBus.HandlerTimeout),
+		commandbusmiddleware.Validate(),
+	)
+	commandRegistry := registry.New(commandBus)
 	mongoConnection, err := mongo.Connect(ctx, options.Client().ApplyURI(
 		fmt.Sprintf("mongodb://%s:%s@%s:%d", cfg.MongoDB.User, cfg.MongoDB.Pass, cfg.MongoDB.Host, cfg.MongoDB.Port),
 	))
@@ -44,11 +61,43 @@ func newMYSQLServiceContainer(ctx context.Context, cfg *config.Config) (*Service
 			ConnTimeout: cfg.GRPC.ConnTimeout,
 		},
 	)
+	grpcUserConn := grpcutils.NewConnection(
+		ctx,
+		cfg.User.Host,
+		cfg.GRPC.Port,
+		grpcutils.ConnectionConfig{
+			ConnTime:    cfg.GRPC.ConnTime,
+			ConnTimeout: cfg.GRPC.ConnTimeout,
+		},
+	)
+	grpcPubSubConn := grpcutils.NewConnection(
+		ctx,
+		cfg.PubSub.Host,
+		cfg.PubSub.Port,
+		grpcutils.ConnectionConfig{
+			ConnTime:    cfg.GRPC.ConnTime,
+			ConnTimeout: cfg.GRPC.ConnTimeout,
+		},
+	)
 	eventStore, err := mongoeventstore.New(ctx, "events", mongoDB)
 	if err != nil {
 		return nil, apperrors.Wrap(err)
 	}
 	eventBus := memoryeventbus.New(cfg.EventBus.QueueSize)
+	integrationEventBus := pubsubeventbus.New(cfg.PubSub.HandlerTimeout, pubsubproto.NewPubSubClient(grpcPubSubConn))
+	executionStore, err := mongoexecution.New(ctx, "auth_command_executions", mongoDB)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	idempotencyStore, err := mongoidempotency.New(ctx, "auth_idempotency_keys", mongoDB)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	schedulerStore, err := mongoscheduler.New(ctx, "auth_scheduled_commands", mongoDB)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	commandScheduler := scheduler.New(commandBus, schedulerStore, cfg.Scheduler.PollInterval, cfg.Scheduler.Lease)
 	tokenRepository := repository.NewTokenRepository(eventStore, eventBus)
 	clientRepository := repository.NewClientRepository(eventStore, eventBus)
 	tokenPersistenceRepository, err := persistence.NewTokenRepository(ctx, mongoDB)
@@ -62,6 +111,7 @@ func newMYSQLServiceContainer(ctx context.Context, cfg *config.Config) (*Service
 	tokenStore := appoauth2.NewTokenStore(tokenPersistenceRepository, tokenRepository)
 	authenticator := auth.NewSecretAuthenticator([]byte(cfg.App.Secret))
 	grpAuthClient := authproto.NewAuthenticationServiceClient(grpcAuthConn)
+	grpcUserClient := userproto.NewUserServiceClient(grpcUserConn)
 	claimsProvider := auth.NewClaimsProvider(authenticator)
 	manager := appoauth2.NewManager(tokenStore, clientPersistenceRepository, authenticator, clientPersistenceRepository)
 	tokenAuthorizer := auth.NewJWTTokenAuthorizer(grpAuthClient, claimsProvider, authenticator)
@@ -69,10 +119,18 @@ func newMYSQLServiceContainer(ctx context.Context, cfg *config.Config) (*Service
 	return &ServiceContainer{
 		Mongo:                       mongoConnection,
 		CommandBus:                  commandBus,
+		CommandRegistry:             commandRegistry,
 		EventBus:                    eventBus,
+		IntegrationEventBus:         integrationEventBus,
+		ExecutionStore:              executionStore,
+		IdempotencyStore:            idempotencyStore,
+		CommandScheduler:            commandScheduler,
 		Authenticator:               authenticator,
 		OAuth2Manager:               manager,
 		AuthConn:                    grpcAuthConn,
+		UserConn:                    grpcUserConn,
+		PubSubConn:                  grpcPubSubConn,
+		UserClient:                  grpcUserClient,
 		TokenAuthorizer:             tokenAuthorizer,
 		TokenRepository:             tokenRepository,
 		ClientRepository:            clientRepository,
@@ -12,13 +12,22 @@ import (
 	persistence "github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence/mysql"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/repository"
 	authproto "github.com/vardius/go-api-boilerplate/cmd/auth/proto"
+	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
 	"github.com/vardius/go-api-boilerplate/pkg/auth"
 	memorycommandbus "github.com/vardius/go-api-boilerplate/pkg/commandbus/memory"
+	commandbusmiddleware "github.com/vardius/go-api-boilerplate/pkg/commandbus/middleware"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	memoryeventbus "github.com/vardius/go-api-boilerplate/pkg/eventbus/memory"
+	pubsubeventbus "github.com/vardius/go-api-boilerplate/pkg/eventbus/pubsub"
 	mysqleventstore "github.com/vardius/go-api-boilerplate/pkg/eventstore/mysql"
+	mysqlexecution "github.com/vardius/go-api-boilerplate/pkg/execution/mysql"
 	grpcutils "github.com/vardius/go-api-boilerplate/pkg/grpc"
+	mysqlidempotency "github.com/vardius/go-api-boilerplate/pkg/idempotency/mysql"
 	"github.com/vardius/go-api-boilerplate/pkg/mysql"
+	"github.com/vardius/go-api-boilerplate/pkg/scheduler"
+	mysqlscheduler "github.com/vardius/go-api-boilerplate/pkg/scheduler/mysql"
+	pubsubproto "github.com/vardius/pubsub/v2/proto"
 )
 
 func init() {
@@ -26,7 +35,15 @@ func init() {
 }
 
 func newMYSQLServiceContainer(ctx context.Context, cfg *config.Config) (*ServiceContainer, error) {
-	commandBus := memorycommandbus.New(cfg.CommandBus.QueueSize)
+	commandBus := memorycommandbus.New(
+		cfg.CommandBus.QueueSize,
+		commandbusmiddleware.Recover(),
+		commandbusmiddleware.Logger(),
+		commandbusmiddleware.Metrics(),
+		commandbusmiddleware.Timeout(cfg.CommandBus.HandlerTimeout),
+		commandbusmiddleware.Validate(),
+	)
+	commandRegistry := registry.New(commandBus)
 	sqlConn := mysql.NewConnection(
 		ctx,
 		mysql.ConnectionConfig{
@@ -49,11 +66,43 @@ func newMYSQLServiceContainer(ctx context.Context, cfg *config.Config) (*Service
 			ConnTimeout: cfg.GRPC.ConnTimeout,
 		},
 	)
+	grpcUserConn := grpcutils.NewConnection(
+		ctx,
+		cfg.User.Host,
+		cfg.GRPC.Port,
+		grpcutils.ConnectionConfig{
+			ConnTime:    cfg.GRPC.ConnTime,
+			ConnTimeout: cfg.GRPC.ConnTimeout,
+		},
+	)
+	grpcPubSubConn := grpcutils.NewConnection(
+		ctx,
+		cfg.PubSub.Host,
+		cfg.PubSub.Port,
+		grpcutils.ConnectionConfig{
+			ConnTime:    cfg.GRPC.ConnTime,
+			ConnTimeout: cfg.GRPC.ConnTimeout,
+		},
+	)
 	eventStore, err := mysqleventstore.New(ctx, "auth_events", sqlConn)
 	if err != nil {
 		return nil, apperrors.Wrap(err)
 	}
 	eventBus := memoryeventbus.New(cfg.EventBus.QueueSize)
+	integrationEventBus := pubsubeventbus.New(cfg.PubSub.HandlerTimeout, pubsubproto.NewPubSubClient(grpcPubSubConn))
+	executionStore, err := mysqlexecution.New(ctx, "auth_command_executions", sqlConn)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	idempotencyStore, err := mysqlidempotency.New(ctx, "auth_idempotency_keys", sqlConn)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	schedulerStore, err := mysqlscheduler.New(ctx, "auth_scheduled_commands", sqlConn)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	commandScheduler := scheduler.New(commandBus, schedulerStore, cfg.Scheduler.PollInterval, cfg.Scheduler.Lease)
 	tokenRepository := repository.NewTokenRepository(eventStore, eventBus)
 	clientRepository := repository.NewClientRepository(eventStore, eventBus)
 	tokenPersistenceRepository, err := persistence.NewTokenRepository(ctx, sqlConn)
@@ -67,6 +116,7 @@ func newMYSQLServiceContainer(ctx context.Context, cfg *config.Config) (*Service
 	tokenStore := appoauth2.NewTokenStore(tokenPersistenceRepository, tokenRepository)
 	authenticator := auth.NewSecretAuthenticator([]byte(cfg.App.Secret))
 	grpAuthClient := authproto.NewAuthenticationServiceClient(grpcAuthConn)
+	grpcUserClient := userproto.NewUserServiceClient(grpcUserConn)
 	claimsProvider := auth.NewClaimsProvider(authenticator)
 	manager := appoauth2.NewManager(tokenStore, clientPersistenceRepository, authenticator, clientPersistenceRepository)
 	tokenAuthorizer := auth.NewJWTTokenAuthorizer(grpAuthClient, claimsProvider, authenticator)
@@ -74,10 +124,18 @@ func newMYSQLServiceContainer(ctx context.Context, cfg *config.Config) (*Service
 	return &ServiceContainer{
 		SQL:                         sqlConn,
 		CommandBus:                  commandBus,
+		CommandRegistry:             commandRegistry,
 		EventBus:                    eventBus,
+		IntegrationEventBus:         integrationEventBus,
+		ExecutionStore:              executionStore,
+		IdempotencyStore:            idempotencyStore,
+		CommandScheduler:            commandScheduler,
 		Authenticator:               authenticator,
 		OAuth2Manager:               manager,
 		AuthConn:                    grpcAuthConn,
+		UserConn:                    grpcUserConn,
+		PubSubConn:                  grpcPubSubConn,
+		UserClient:                  grpcUserClient,
 		TokenAuthorizer:             tokenAuthorizer,
 		TokenRepository:             tokenRepository,
 		ClientRepository:            clientRepository,
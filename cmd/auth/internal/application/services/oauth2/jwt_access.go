@@ -33,12 +33,21 @@ func (c *JWTAccessClaims) Valid() error {
 	return c.Claims.Valid()
 }
 
+type grantKey struct{}
+
+// ContextWithGrant returns context access tokens are generated with for identity user was granted
+// instead of permissions of client scopes, refreshed tokens keep roles and permissions they were granted
+func ContextWithGrant(ctx context.Context, i identity.Identity) context.Context {
+	return context.WithValue(ctx, grantKey{}, i)
+}
+
 // NewJWTAccess create to generate the jwt access token instance
-func NewJWTAccess(method jwt.SigningMethod, authenticator auth.Authenticator, clientRepository persistence.ClientRepository) *JWTAccess {
+func NewJWTAccess(method jwt.SigningMethod, authenticator auth.Authenticator, clientRepository persistence.ClientRepository, scopes access.ScopeRegistry) *JWTAccess {
 	return &JWTAccess{
 		signedMethod:     method,
 		authenticator:    authenticator,
 		clientRepository: clientRepository,
+		scopes:           scopes,
 	}
 }
 
@@ -47,6 +56,7 @@ type JWTAccess struct {
 	signedMethod     jwt.SigningMethod
 	authenticator    auth.Authenticator
 	clientRepository persistence.ClientRepository
+	scopes           access.ScopeRegistry
 }
 
 // Token based on the UUID generated token
@@ -71,17 +81,17 @@ func (a *JWTAccess) Token(ctx context.Context, data *oauth2.GenerateBasic, isGen
 		expiresAt = data.TokenInfo.GetAccessCreateAt().Add(data.TokenInfo.GetAccessExpiresIn()).Unix()
 	}
 
-	var permissions identity.Permission
-	for _, scope := range c.GetScopes() {
-		switch access.Scope(scope) {
-		case access.ScopeAll:
-			permissions.Add(identity.PermissionUserRead)
-			permissions.Add(identity.PermissionUserWrite)
-			break
-		case access.ScopeUserRead:
-			permissions.Add(identity.PermissionUserRead)
-		case access.ScopeUserWrite:
-			permissions.Add(identity.PermissionUserWrite)
+	permissions, role := a.scopes.Permissions(c.GetScopes()), identity.Role(0)
+	if granted, ok := ctx.Value(grantKey{}).(identity.Identity); ok {
+		permissions, role = granted.Permissions, granted.Role
+	} else if data.TokenInfo != nil && data.TokenInfo.GetAccess() != "" {
+		// refreshing token, previous access token comes from token store
+		previous := &JWTAccessClaims{}
+		if _, _, err := new(jwt.Parser).ParseUnverified(data.TokenInfo.GetAccess(), previous); err != nil {
+			return "", "", apperrors.Wrap(err)
+		}
+		if previous.Identity != nil && previous.Identity.Role != 0 {
+			permissions, role = previous.Identity.Permissions, previous.Identity.Role
 		}
 	}
 
@@ -93,7 +103,8 @@ func (a *JWTAccess) Token(ctx context.Context, data *oauth2.GenerateBasic, isGen
 				ExpiresAt: expiresAt,
 			},
 			Identity: &identity.Identity{
-				Permission:   permissions,
+				Permissions:  permissions,
+				Role:         role,
 				UserID:       userID,
 				ClientID:     clientID,
 				ClientDomain: c.GetDomain(),
@@ -5,6 +5,7 @@ import (
 	"gopkg.in/oauth2.v4"
 	oauth2manage "gopkg.in/oauth2.v4/manage"
 
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/application/access"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence"
 	"github.com/vardius/go-api-boilerplate/pkg/auth"
 )
@@ -19,7 +20,7 @@ func NewManager(tokenStore oauth2.TokenStore, clientStore oauth2.ClientStore, au
 	manager.SetRefreshTokenCfg(oauth2manage.DefaultRefreshTokenCfg)
 	manager.MapTokenStorage(tokenStore)
 	manager.MapClientStorage(clientStore)
-	manager.MapAccessGenerate(NewJWTAccess(jwt.SigningMethodHS512, authenticator, clientRepository))
+	manager.MapAccessGenerate(NewJWTAccess(jwt.SigningMethodHS512, authenticator, clientRepository, access.Scopes))
 
 	return manager
 }
@@ -2,6 +2,7 @@ package oauth2
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -10,7 +11,9 @@ import (
 
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/application/config"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence"
+	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	grpcerrors "github.com/vardius/go-api-boilerplate/pkg/grpc/errors"
 	"github.com/vardius/go-api-boilerplate/pkg/identity"
 	"github.com/vardius/go-api-boilerplate/pkg/logger"
 	"gopkg.in/oauth2.v4"
@@ -28,6 +31,7 @@ func InitServer(
 	cfg *config.Config,
 	manager oauth2.Manager,
 	clientRepository persistence.ClientRepository,
+	userClient userproto.UserServiceClient,
 	timeout time.Duration,
 ) *oauth2server.Server {
 	onceServer.Do(func() {
@@ -39,6 +43,7 @@ func InitServer(
 			// https://github.com/go-oauth2/oauth2/blob/b46cf9f1db6551beb549ad1afe69826b3b2f1abf/example/client/client.go#L112-L128
 			oauth2.AuthorizationCode, // Example usage of authorization code
 			// https://github.com/go-oauth2/oauth2/blob/b46cf9f1db6551beb549ad1afe69826b3b2f1abf/example/client/client.go#L35-L62
+			oauth2.PasswordCredentials, // Credentials are verified by user service
 		)
 		srv.SetClientInfoHandler(oauth2server.ClientBasicHandler)
 
@@ -58,6 +63,26 @@ func InitServer(
 
 			return i.UserID.String(), nil
 		})
+		srv.SetPasswordAuthorizationHandler(func(username, password string) (userID string, err error) {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			u, err := userClient.AuthenticateWithPassword(ctx, &userproto.AuthenticateWithPasswordRequest{
+				Email:    username,
+				Password: password,
+			})
+			if err != nil {
+				appErr := grpcerrors.FromGRPCError(err)
+				// empty user id makes server respond with invalid grant error
+				if errors.Is(appErr, apperrors.ErrUnauthorized) || errors.Is(appErr, apperrors.ErrInvalid) {
+					return "", nil
+				}
+
+				return "", apperrors.Wrap(appErr)
+			}
+
+			return u.GetId(), nil
+		})
 		srv.SetClientScopeHandler(func(clientID, scope string) (allowed bool, err error) {
 			ctx, cancel := context.WithTimeout(context.Background(), timeout)
 			defer cancel()
@@ -75,8 +100,8 @@ func InitServer(
 				return false, nil
 			}
 
-			if len(tokenScopes) == 0 || len(tokenScopes) == 0 {
-				logger.Debug(ctx, fmt.Sprintf("Token not allowed: empty scopes len(tokenScopes) == 0 || len(tokenScopes) == 0 %v %v", tokenScopes, clientScopes))
+			if len(tokenScopes) == 0 || len(clientScopes) == 0 {
+				logger.Debug(ctx, fmt.Sprintf("Token not allowed: empty scopes len(tokenScopes) == 0 || len(clientScopes) == 0 %v %v", tokenScopes, clientScopes))
 				return false, nil
 			}
 
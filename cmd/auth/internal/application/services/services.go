@@ -16,9 +16,14 @@ import (
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/client"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/token"
 	authpersistence "github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence"
+	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
 	"github.com/vardius/go-api-boilerplate/pkg/auth"
 	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
 	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+	"github.com/vardius/go-api-boilerplate/pkg/execution"
+	"github.com/vardius/go-api-boilerplate/pkg/idempotency"
+	"github.com/vardius/go-api-boilerplate/pkg/scheduler"
 )
 
 type containerFactory func(ctx context.Context, cfg *config.Config) (*ServiceContainer, error)
@@ -31,8 +36,16 @@ type ServiceContainer struct {
 	Mongo *mongo.Client
 
 	CommandBus                  commandbus.CommandBus
+	CommandRegistry             *registry.Registry
 	EventBus                    eventbus.EventBus
+	IntegrationEventBus         eventbus.EventBus // distributed event bus shared with other services
+	ExecutionStore              execution.Store
+	IdempotencyStore            idempotency.Store
+	CommandScheduler            *scheduler.Scheduler
 	AuthConn                    *grpc.ClientConn
+	UserConn                    *grpc.ClientConn
+	PubSubConn                  *grpc.ClientConn
+	UserClient                  userproto.UserServiceClient
 	TokenRepository             token.Repository
 	ClientRepository            client.Repository
 	TokenPersistenceRepository  authpersistence.TokenRepository
@@ -47,7 +60,7 @@ func (c *ServiceContainer) Close() error {
 	defer cancel()
 
 	var wg sync.WaitGroup
-	wg.Add(3)
+	wg.Add(5)
 
 	var errs []error
 	go func() {
@@ -75,6 +88,23 @@ func (c *ServiceContainer) Close() error {
 		}
 	}()
 
+	go func() {
+		defer wg.Done()
+		if c.UserConn != nil {
+			if err := c.UserConn.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if c.PubSubConn != nil {
+			if err := c.PubSubConn.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}()
+
 	wg.Wait()
 
 	var closeErr error
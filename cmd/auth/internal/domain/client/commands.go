@@ -2,7 +2,6 @@ package client
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -26,29 +25,9 @@ var (
 	CreateName = (Create{}).GetName()
 )
 
-// NewCommandFromPayload builds command by contract from json payload
-func NewCommandFromPayload(contract string, payload []byte) (domain.Command, error) {
-	switch contract {
-	case CreateClientCredentials:
-		var command Create
-		if err := json.Unmarshal(payload, &command); err != nil {
-			return command, apperrors.Wrap(err)
-		}
-		return command, nil
-	case RemoveClientCredentials:
-		var command Remove
-		if err := json.Unmarshal(payload, &command); err != nil {
-			return command, apperrors.Wrap(err)
-		}
-		return command, nil
-	default:
-		return nil, apperrors.Wrap(fmt.Errorf("invalid command contract: %s", contract))
-	}
-}
-
 // Remove command
 type Remove struct {
-	ID uuid.UUID `json:"id"`
+	ID uuid.UUID `json:"id" validate:"required"`
 }
 
 // GetName returns command name
@@ -93,9 +72,9 @@ func OnRemove(repository Repository) commandbus.CommandHandler {
 
 // Create command
 type Create struct {
-	Domain      string   `json:"domain"`
-	RedirectURL string   `json:"redirect_url"`
-	Scopes      []string `json:"scopes"`
+	Domain      string   `json:"domain" validate:"required,requrl"`
+	RedirectURL string   `json:"redirect_url" validate:"required,requrl"`
+	Scopes      []string `json:"scopes" validate:"required,in(all|user_read|user_write)"`
 }
 
 // GetName returns command name
@@ -0,0 +1,107 @@
+package client_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/application/config"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/application/eventhandler"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/client"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence/memory"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/repository"
+	"github.com/vardius/go-api-boilerplate/pkg/contract"
+	"github.com/vardius/go-api-boilerplate/pkg/domain/domaintest"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+func TestMain(m *testing.M) {
+	if err := contract.RegisterEvent(client.WasCreatedType, client.WasCreated{}); err != nil {
+		panic(err)
+	}
+	if err := contract.RegisterEvent(client.WasRemovedType, client.WasRemoved{}); err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+type fixture struct {
+	*domaintest.Scenario
+	repository client.Repository
+	clients    persistence.ClientRepository
+}
+
+func setUp(t *testing.T) *fixture {
+	s := domaintest.New(t)
+	clients := memory.NewClientRepository(&config.Config{})
+
+	s.Project(client.WasCreatedType, eventhandler.WhenClientWasCreated(clients))
+	s.Project(client.WasRemovedType, eventhandler.WhenClientWasRemoved(clients))
+
+	return &fixture{
+		Scenario:   s,
+		repository: repository.NewClientRepository(s.EventStore(), s.EventBus()),
+		clients:    clients,
+	}
+}
+
+func TestOnCreate(t *testing.T) {
+	userID := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: userID})
+
+	f := setUp(t)
+	result := f.When(ctx, client.OnCreate(f.repository), client.Create{
+		Domain:      "http://localhost",
+		RedirectURL: "http://localhost/callback",
+		Scopes:      []string{"all"},
+	})
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	result.ThenReadModel(func(ctx context.Context) error {
+		clients, err := f.clients.FindAllByUserID(ctx, userID.String(), 10, 0)
+		if err != nil {
+			return err
+		}
+		if err := domaintest.Equal("clients", len(clients), 1); err != nil {
+			return err
+		}
+		return domaintest.Equal("domain", clients[0].GetDomain(), "http://localhost")
+	})
+}
+
+func TestOnCreateUnauthorized(t *testing.T) {
+	f := setUp(t)
+	f.When(context.Background(), client.OnCreate(f.repository), client.Create{}).
+		ThenError(apperrors.ErrUnauthorized)
+}
+
+func TestOnRemove(t *testing.T) {
+	id, userID := uuid.New(), uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: userID})
+
+	f := setUp(t)
+	f.Given(id, client.StreamName, &client.WasCreated{ID: id, UserID: userID, Secret: uuid.New()}).
+		When(ctx, client.OnRemove(f.repository), client.Remove{ID: id}).
+		Then(&client.WasRemoved{ID: id}).
+		ThenReadModel(func(ctx context.Context) error {
+			_, err := f.clients.Get(ctx, id.String())
+			return domaintest.NotFound(err)
+		})
+}
+
+func TestOnRemoveForbidden(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: uuid.New()})
+
+	f := setUp(t)
+	f.Given(id, client.StreamName, &client.WasCreated{ID: id, UserID: uuid.New(), Secret: uuid.New()}).
+		When(ctx, client.OnRemove(f.repository), client.Remove{ID: id}).
+		ThenError(apperrors.ErrForbidden)
+}
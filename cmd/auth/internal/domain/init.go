@@ -2,13 +2,21 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
+
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/application/config"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/application/eventhandler"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/application/services"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/client"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/token"
+	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
+	"github.com/vardius/go-api-boilerplate/pkg/contract"
 	"github.com/vardius/go-api-boilerplate/pkg/domain"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/logger"
 )
 
 func RegisterTokenDomain(ctx context.Context, cfg *config.Config, container *services.ServiceContainer) error {
@@ -19,13 +27,32 @@ func RegisterTokenDomain(ctx context.Context, cfg *config.Config, container *ser
 		return apperrors.Wrap(err)
 	}
 
-	if err := container.CommandBus.Subscribe(ctx, token.CreateName, token.OnCreate(container.TokenRepository)); err != nil {
+	if err := contract.RegisterEvent(token.WasCreatedType, token.WasCreated{}); err != nil {
 		return apperrors.Wrap(err)
 	}
-	if err := container.CommandBus.Subscribe(ctx, token.RemoveName, token.OnRemove(container.TokenRepository)); err != nil {
+	if err := contract.RegisterEvent(token.WasRemovedType, token.WasRemoved{}); err != nil {
 		return apperrors.Wrap(err)
 	}
 
+	for _, d := range []registry.Definition{
+		{
+			Contract: token.CreateAuthToken,
+			Command:  token.Create{},
+			Role:     identity.RoleService, // dispatched by user service storing tokens it signs
+			Handler:  token.OnCreate(container.TokenRepository),
+		},
+		{
+			Contract: token.RemoveAuthToken,
+			Command:  token.Remove{},
+			Role:     identity.RoleService,
+			Handler:  token.OnRemove(container.TokenRepository),
+		},
+	} {
+		if err := container.CommandRegistry.Register(ctx, d); err != nil {
+			return apperrors.Wrap(err)
+		}
+	}
+
 	if err := container.EventBus.Subscribe(ctx, token.WasCreatedType, eventhandler.WhenTokenWasCreated(container.TokenPersistenceRepository)); err != nil {
 		return apperrors.Wrap(err)
 	}
@@ -43,13 +70,32 @@ func RegisterClientDomain(ctx context.Context, cfg *config.Config, container *se
 		return apperrors.Wrap(err)
 	}
 
-	if err := container.CommandBus.Subscribe(ctx, client.CreateName, client.OnCreate(container.ClientRepository)); err != nil {
+	if err := contract.RegisterEvent(client.WasCreatedType, client.WasCreated{}); err != nil {
 		return apperrors.Wrap(err)
 	}
-	if err := container.CommandBus.Subscribe(ctx, client.RemoveName, client.OnRemove(container.ClientRepository)); err != nil {
+	if err := contract.RegisterEvent(client.WasRemovedType, client.WasRemoved{}); err != nil {
 		return apperrors.Wrap(err)
 	}
 
+	for _, d := range []registry.Definition{
+		{
+			Contract:   client.CreateClientCredentials,
+			Command:    client.Create{},
+			Permission: identity.PermissionClientWrite,
+			Handler:    client.OnCreate(container.ClientRepository),
+		},
+		{
+			Contract:   client.RemoveClientCredentials,
+			Command:    client.Remove{},
+			Permission: identity.PermissionClientWrite,
+			Handler:    client.OnRemove(container.ClientRepository),
+		},
+	} {
+		if err := container.CommandRegistry.Register(ctx, d); err != nil {
+			return apperrors.Wrap(err)
+		}
+	}
+
 	if err := container.EventBus.Subscribe(ctx, client.WasCreatedType, eventhandler.WhenClientWasCreated(container.ClientPersistenceRepository)); err != nil {
 		return apperrors.Wrap(err)
 	}
@@ -59,3 +105,37 @@ func RegisterClientDomain(ctx context.Context, cfg *config.Config, container *se
 
 	return nil
 }
+
+// RegisterUserIntegration subscribes to integration events published by user service,
+// subscriptions run in background until context is canceled
+func RegisterUserIntegration(ctx context.Context, cfg *config.Config, container *services.ServiceContainer) error {
+	if err := domain.RegisterEventFactory(userproto.UserWasDeletedEventType, func() interface{} { return &userproto.UserWasDeleted{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(userproto.UserRoleWasRevokedEventType, func() interface{} { return &userproto.UserRoleWasRevoked{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	handler := eventhandler.WhenUserWasDeleted(
+		container.ClientPersistenceRepository,
+		container.TokenPersistenceRepository,
+		container.ClientRepository,
+		container.TokenRepository,
+	)
+
+	go func() {
+		if err := container.IntegrationEventBus.Subscribe(ctx, userproto.UserWasDeletedEventType, handler); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error(ctx, fmt.Sprintf("[Auth] %s subscription failed: %v", userproto.UserWasDeletedEventType, err))
+		}
+	}()
+
+	roleRevokedHandler := eventhandler.WhenUserRoleWasRevoked(container.TokenPersistenceRepository, container.TokenRepository)
+
+	go func() {
+		if err := container.IntegrationEventBus.Subscribe(ctx, userproto.UserRoleWasRevokedEventType, roleRevokedHandler); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error(ctx, fmt.Sprintf("[Auth] %s subscription failed: %v", userproto.UserRoleWasRevokedEventType, err))
+		}
+	}()
+
+	return nil
+}
@@ -2,7 +2,7 @@ package token
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -30,26 +30,15 @@ var (
 	CreateName = (Create{}).GetName()
 )
 
-// NewCommandFromPayload builds command by contract from json payload
-func NewCommandFromPayload(contract string, payload []byte) (domain.Command, error) {
-	switch contract {
-	case CreateAuthToken:
-		var command Create
-		return command, nil
-	case RemoveAuthToken:
-		var command Remove
-		if err := json.Unmarshal(payload, &command); err != nil {
-			return command, apperrors.Wrap(err)
-		}
-		return command, nil
-	default:
-		return nil, apperrors.Wrap(fmt.Errorf("invalid command contract: %s", contract))
-	}
+// Create command, stores access token signed for user so it passes bearer token validation until it expires,
+// ID allows caller to remove created token, it is generated if not provided
+type Create struct {
+	ID        uuid.UUID `json:"id,omitempty"`
+	UserID    uuid.UUID `json:"user_id" validate:"required"`
+	Access    string    `json:"access" validate:"required"`
+	ExpiresAt time.Time `json:"expires_at" validate:"required"`
 }
 
-// Create command, creates access token for user
-type Create struct{}
-
 // GetName returns command name
 func (c Create) GetName() string {
 	return fmt.Sprintf("%T", c)
@@ -58,14 +47,30 @@ func (c Create) GetName() string {
 // OnCreate creates command handler
 func OnCreate(repository Repository) commandbus.CommandHandler {
 	fn := func(ctx context.Context, command domain.Command) error {
-		i, hasIdentity := identity.FromContext(ctx)
-		if !hasIdentity {
+		c, ok := command.(Create)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if _, hasIdentity := identity.FromContext(ctx); !hasIdentity {
 			return apperrors.Wrap(apperrors.ErrUnauthorized)
 		}
 
-		id, err := uuid.NewRandom()
-		if err != nil {
-			return apperrors.Wrap(fmt.Errorf("%w: Could not generate new id: %s", apperrors.ErrInternal, err))
+		expiresIn := time.Until(c.ExpiresAt)
+		if expiresIn <= 0 {
+			return apperrors.Wrap(fmt.Errorf("%w: token already expired", apperrors.ErrInvalid))
+		}
+
+		id := c.ID
+		if id == uuid.Nil {
+			var err error
+			if id, err = uuid.NewRandom(); err != nil {
+				return apperrors.Wrap(fmt.Errorf("%w: Could not generate new id: %s", apperrors.ErrInternal, err))
+			}
+		} else if _, err := repository.Get(ctx, id); err == nil {
+			return apperrors.Wrap(fmt.Errorf("%w: token %s already exists", apperrors.ErrConflict, id))
+		} else if !errors.Is(err, apperrors.ErrNotFound) {
+			return apperrors.Wrap(err)
 		}
 
 		var userAgent string
@@ -74,13 +79,13 @@ func OnCreate(repository Repository) commandbus.CommandHandler {
 		}
 
 		token := New()
-		if err := token.Create(ctx, id, uuid.Nil, i.UserID, &models.Token{
+		if err := token.Create(ctx, id, uuid.Nil, c.UserID, &models.Token{
 			ClientID:        uuid.Nil.String(),
-			UserID:          i.UserID.String(),
+			UserID:          c.UserID.String(),
 			Scope:           string(access.ScopeAll),
-			Access:          i.Token,
+			Access:          c.Access,
 			AccessCreateAt:  time.Now(),
-			AccessExpiresIn: 365 * 24 * time.Hour,
+			AccessExpiresIn: expiresIn,
 		}, userAgent); err != nil {
 			return apperrors.Wrap(fmt.Errorf("%w: Error when creating token: %s", apperrors.ErrInternal, err))
 		}
@@ -95,9 +100,10 @@ func OnCreate(repository Repository) commandbus.CommandHandler {
 	return fn
 }
 
-// Remove command
+// Remove command, removes access token of user
 type Remove struct {
-	ID uuid.UUID `json:"id"`
+	ID     uuid.UUID `json:"id" validate:"required"`
+	UserID uuid.UUID `json:"user_id" validate:"required"`
 }
 
 // GetName returns command name
@@ -113,8 +119,7 @@ func OnRemove(repository Repository) commandbus.CommandHandler {
 			return apperrors.New("invalid command")
 		}
 
-		i, hasIdentity := identity.FromContext(ctx)
-		if !hasIdentity {
+		if _, hasIdentity := identity.FromContext(ctx); !hasIdentity {
 			return apperrors.Wrap(apperrors.ErrUnauthorized)
 		}
 
@@ -122,7 +127,7 @@ func OnRemove(repository Repository) commandbus.CommandHandler {
 		if err != nil {
 			return apperrors.Wrap(err)
 		}
-		if i.UserID.String() != token.userID.String() {
+		if c.UserID.String() != token.userID.String() {
 			return apperrors.Wrap(apperrors.ErrForbidden)
 		}
 
@@ -0,0 +1,132 @@
+package token_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/application/eventhandler"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/token"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence/memory"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/repository"
+	"github.com/vardius/go-api-boilerplate/pkg/contract"
+	"github.com/vardius/go-api-boilerplate/pkg/domain/domaintest"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+func TestMain(m *testing.M) {
+	if err := contract.RegisterEvent(token.WasCreatedType, token.WasCreated{}); err != nil {
+		panic(err)
+	}
+	if err := contract.RegisterEvent(token.WasRemovedType, token.WasRemoved{}); err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+type fixture struct {
+	*domaintest.Scenario
+	repository token.Repository
+	tokens     persistence.TokenRepository
+}
+
+func setUp(t *testing.T) *fixture {
+	s := domaintest.New(t)
+	tokens := memory.NewTokenRepository()
+
+	s.Project(token.WasCreatedType, eventhandler.WhenTokenWasCreated(tokens))
+	s.Project(token.WasRemovedType, eventhandler.WhenTokenWasRemoved(tokens))
+
+	return &fixture{
+		Scenario:   s,
+		repository: repository.NewTokenRepository(s.EventStore(), s.EventBus()),
+		tokens:     tokens,
+	}
+}
+
+func TestOnCreate(t *testing.T) {
+	id, userID := uuid.New(), uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), identity.Service())
+
+	f := setUp(t)
+	result := f.When(ctx, token.OnCreate(f.repository), token.Create{ID: id, UserID: userID, Access: "access", ExpiresAt: time.Now().Add(time.Hour)})
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	result.ThenReadModel(func(ctx context.Context) error {
+		tm, err := f.tokens.GetByAccess(ctx, "access")
+		if err != nil {
+			return err
+		}
+		if err := domaintest.Equal("id", tm.GetID(), id.String()); err != nil {
+			return err
+		}
+		info, err := tm.TokenInfo()
+		if err != nil {
+			return err
+		}
+		if err := domaintest.Equal("user id", info.GetUserID(), userID.String()); err != nil {
+			return err
+		}
+		if expiresIn := info.GetAccessExpiresIn(); expiresIn > time.Hour || expiresIn < 59*time.Minute {
+			return fmt.Errorf("access expires in %s, want token expiry", expiresIn)
+		}
+		return nil
+	})
+}
+
+func TestOnCreateExpired(t *testing.T) {
+	ctx := identity.ContextWithIdentity(context.Background(), identity.Service())
+
+	f := setUp(t)
+	f.When(ctx, token.OnCreate(f.repository), token.Create{UserID: uuid.New(), Access: "access", ExpiresAt: time.Now().Add(-time.Second)}).
+		ThenError(apperrors.ErrInvalid)
+}
+
+func TestOnCreateConflict(t *testing.T) {
+	id, userID := uuid.New(), uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), identity.Service())
+
+	f := setUp(t)
+	f.Given(id, token.StreamName, &token.WasCreated{ID: id, UserID: userID, Data: []byte(`{}`)}).
+		When(ctx, token.OnCreate(f.repository), token.Create{ID: id, UserID: userID, Access: "access", ExpiresAt: time.Now().Add(time.Hour)}).
+		ThenError(apperrors.ErrConflict)
+}
+
+func TestOnCreateUnauthorized(t *testing.T) {
+	f := setUp(t)
+	f.When(context.Background(), token.OnCreate(f.repository), token.Create{}).
+		ThenError(apperrors.ErrUnauthorized)
+}
+
+func TestOnRemove(t *testing.T) {
+	id, userID := uuid.New(), uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), identity.Service())
+
+	f := setUp(t)
+	f.Given(id, token.StreamName, &token.WasCreated{ID: id, UserID: userID, Data: []byte(`{}`)}).
+		When(ctx, token.OnRemove(f.repository), token.Remove{ID: id, UserID: userID}).
+		Then(&token.WasRemoved{ID: id}).
+		ThenReadModel(func(ctx context.Context) error {
+			_, err := f.tokens.Get(ctx, id.String())
+			return domaintest.NotFound(err)
+		})
+}
+
+func TestOnRemoveForbidden(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), identity.Service())
+
+	f := setUp(t)
+	f.Given(id, token.StreamName, &token.WasCreated{ID: id, UserID: uuid.New(), Data: []byte(`{}`)}).
+		When(ctx, token.OnRemove(f.repository), token.Remove{ID: id, UserID: uuid.New()}).
+		ThenError(apperrors.ErrForbidden)
+}
@@ -128,6 +128,36 @@ func (r *tokenRepository) FindAllByClientID(ctx context.Context, clientID string
 	return tokens, nil
 }
 
+func (r *tokenRepository) FindAllByUserID(ctx context.Context, userID string, limit, offset int64) ([]persistence.Token, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	var i int64
+	var tokens []persistence.Token
+	for _, v := range r.tokens {
+		ti, err := v.TokenInfo()
+		if err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+		if ti.GetUserID() != userID {
+			continue
+		}
+
+		i++
+		if i <= offset {
+			continue
+		}
+
+		tokens = append(tokens, v)
+
+		if int64(len(tokens)) == limit {
+			return tokens, nil
+		}
+	}
+
+	return tokens, nil
+}
+
 func (r *tokenRepository) CountByClientID(ctx context.Context, clientID string) (int64, error) {
 	r.RLock()
 	defer r.RUnlock()
@@ -26,6 +26,7 @@ func NewTokenRepository(ctx context.Context, mongoDB *mongo.Database) (persisten
 			{Key: "client_id", Value: 1},
 			{Key: "user_id", Value: 1},
 		}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
 	}); err != nil {
 		return nil, apperrors.Wrap(err)
 	}
@@ -180,6 +181,31 @@ func (r *tokenRepository) FindAllByClientID(ctx context.Context, clientID string
 	return result, nil
 }
 
+func (r *tokenRepository) FindAllByUserID(ctx context.Context, userID string, limit, offset int64) ([]persistence.Token, error) {
+	findOptions := options.Find().SetLimit(limit).SetSkip(offset)
+	filter := bson.M{
+		"user_id": userID,
+	}
+
+	cur, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	defer cur.Close(ctx)
+
+	var result []persistence.Token
+	for cur.Next(ctx) {
+		var item Token
+		if err := cur.Decode(&item); err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+
+		result = append(result, &item)
+	}
+
+	return result, nil
+}
+
 func (r *tokenRepository) CountByClientID(ctx context.Context, clientID string) (int64, error) {
 	filter := bson.M{
 		"client_id": clientID,
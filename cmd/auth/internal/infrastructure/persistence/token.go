@@ -29,4 +29,5 @@ type TokenRepository interface {
 
 	CountByClientID(ctx context.Context, clientID string) (int64, error)
 	FindAllByClientID(ctx context.Context, clientID string, limit, offset int64) ([]Token, error)
+	FindAllByUserID(ctx context.Context, userID string, limit, offset int64) ([]Token, error)
 }
@@ -15,40 +15,29 @@ import (
 )
 
 type clientRepository struct {
-	eventStore eventstore.EventStore
-	eventBus   eventbus.EventBus
+	repository *eventstore.Repository
+}
+
+// NewClientRepository creates new client event sourced repository
+func NewClientRepository(store eventstore.EventStore, bus eventbus.EventBus) client.Repository {
+	return &clientRepository{eventstore.NewRepository(store, bus)}
 }
 
 // Save current client changes to event store and publish each event with an event bus
-func (r *clientRepository) Save(ctx context.Context, u client.Client) error {
-	if err := r.eventStore.Store(ctx, u.Changes()); err != nil {
+func (r *clientRepository) Save(ctx context.Context, c client.Client) error {
+	if err := r.repository.Save(ctx, &c); err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	for _, event := range u.Changes() {
-		if err := r.eventBus.Publish(ctx, event); err != nil {
-			return apperrors.Wrap(err)
-		}
-	}
-
 	return nil
 }
 
 // Get client with current state applied
 func (r *clientRepository) Get(ctx context.Context, id uuid.UUID) (client.Client, error) {
-	events, err := r.eventStore.GetStream(ctx, id, client.StreamName)
-	if err != nil {
+	c := client.New()
+	if err := r.repository.Get(ctx, id, &c); err != nil {
 		return client.Client{}, apperrors.Wrap(err)
 	}
 
-	if len(events) == 0 {
-		return client.Client{}, apperrors.ErrNotFound
-	}
-
-	return client.FromHistory(ctx, events)
-}
-
-// NewClientRepository creates new client event sourced repository
-func NewClientRepository(store eventstore.EventStore, bus eventbus.EventBus) client.Repository {
-	return &clientRepository{store, bus}
+	return c, nil
 }
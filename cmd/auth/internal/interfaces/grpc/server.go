@@ -10,10 +10,9 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	"gopkg.in/oauth2.v4/server"
 
-	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/client"
-	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/token"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/proto"
 	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	grpcerrors "github.com/vardius/go-api-boilerplate/pkg/grpc/errors"
 )
@@ -21,19 +20,21 @@ import (
 type authenticationServer struct {
 	server     *server.Server
 	commandBus commandbus.CommandBus
+	commands   *registry.Registry
 }
 
 // NewServer returns new auth server object
-func NewServer(server *server.Server, commandBus commandbus.CommandBus) proto.AuthenticationServiceServer {
+func NewServer(server *server.Server, commandBus commandbus.CommandBus, commands *registry.Registry) proto.AuthenticationServiceServer {
 	return &authenticationServer{
 		server:     server,
 		commandBus: commandBus,
+		commands:   commands,
 	}
 }
 
 // DispatchTokenCommand dispatches token commands
 func (s *authenticationServer) DispatchTokenCommand(ctx context.Context, r *proto.DispatchAuthCommandRequest) (*empty.Empty, error) {
-	c, err := token.NewCommandFromPayload(r.GetName(), r.GetPayload())
+	c, err := s.commands.NewCommand(ctx, r.GetName(), r.GetPayload())
 	if err != nil {
 		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(err))
 	}
@@ -47,7 +48,7 @@ func (s *authenticationServer) DispatchTokenCommand(ctx context.Context, r *prot
 
 // DispatchClientCommand dispatches client commands
 func (s *authenticationServer) DispatchClientCommand(ctx context.Context, r *proto.DispatchAuthCommandRequest) (*empty.Empty, error) {
-	c, err := client.NewCommandFromPayload(r.GetName(), r.GetPayload())
+	c, err := s.commands.NewCommand(ctx, r.GetName(), r.GetPayload())
 	if err != nil {
 		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(err))
 	}
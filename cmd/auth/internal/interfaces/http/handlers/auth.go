@@ -1,13 +1,23 @@
 package handlers
 
 import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
+	"gopkg.in/oauth2.v4"
+	oauth2errors "gopkg.in/oauth2.v4/errors"
 	"gopkg.in/oauth2.v4/server"
 
+	appoauth2 "github.com/vardius/go-api-boilerplate/cmd/auth/internal/application/services/oauth2"
+	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	grpcerrors "github.com/vardius/go-api-boilerplate/pkg/grpc/errors"
 	httpjson "github.com/vardius/go-api-boilerplate/pkg/http/response/json"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
 )
 
 // BuildAuthorizeHandler provides authorize handler
@@ -37,3 +47,129 @@ func BuildTokenHandler(srv *server.Server) http.Handler {
 
 	return httpjson.HandlerFunc(fn)
 }
+
+// BuildLoginCodeHandler provides handler exchanging single-use login code sent by user service for access and refresh token,
+// client credentials and scope are verified the same way as for password grant before login code is used up,
+// users with multi-factor authentication enabled have to send one-time password or recovery code along,
+// tokens are granted roles and permissions user service grants user instead of permissions of client scopes
+func BuildLoginCodeHandler(srv *server.Server, userClient userproto.UserServiceClient, accessTokenTTL time.Duration) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) error {
+		clientID, clientSecret, err := srv.ClientInfoHandler(r)
+		if err != nil {
+			return tokenError(w, srv, err, nil)
+		}
+
+		userID, code, otp := r.FormValue("id"), r.FormValue("code"), r.FormValue("otp")
+		if userID == "" || code == "" {
+			return tokenError(w, srv, oauth2errors.ErrInvalidRequest, nil)
+		}
+
+		client, err := srv.Manager.GetClient(r.Context(), clientID)
+		if err != nil {
+			return tokenError(w, srv, err, nil)
+		}
+		if subtle.ConstantTimeCompare([]byte(client.GetSecret()), []byte(clientSecret)) != 1 {
+			return tokenError(w, srv, oauth2errors.ErrInvalidClient, nil)
+		}
+
+		tgr := &oauth2.TokenGenerateRequest{
+			ClientID:       clientID,
+			ClientSecret:   clientSecret,
+			Scope:          r.FormValue("scope"),
+			AccessTokenExp: accessTokenTTL,
+			Request:        r,
+		}
+
+		if fn := srv.ClientScopeHandler; fn != nil {
+			allowed, err := fn(tgr.ClientID, tgr.Scope)
+			if err != nil {
+				return tokenError(w, srv, err, nil)
+			} else if !allowed {
+				return tokenError(w, srv, oauth2errors.ErrInvalidScope, nil)
+			}
+		}
+
+		u, err := userClient.AuthenticateWithLoginCode(r.Context(), &userproto.AuthenticateWithLoginCodeRequest{
+			Id:   userID,
+			Code: code,
+			Otp:  otp,
+		})
+		if err != nil {
+			appErr := grpcerrors.FromGRPCError(err)
+			switch {
+			case errors.Is(appErr, apperrors.ErrUnauthorized) && otp == "":
+				// login code is valid but one-time password has to be sent along
+				return tokenError(w, srv, oauth2errors.ErrInvalidGrant, map[string]interface{}{"mfa_required": true})
+			case errors.Is(appErr, apperrors.ErrUnauthorized), errors.Is(appErr, apperrors.ErrInvalid), errors.Is(appErr, apperrors.ErrNotFound):
+				return tokenError(w, srv, oauth2errors.ErrInvalidGrant, nil)
+			}
+
+			return apperrors.Wrap(appErr)
+		}
+
+		granted, err := grantedIdentity(u)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		tgr.UserID = u.GetUser().GetId()
+
+		// grant type decides lifetime of refresh token
+		ti, err := srv.Manager.GenerateAccessToken(appoauth2.ContextWithGrant(r.Context(), granted), oauth2.PasswordCredentials, tgr)
+		if err != nil {
+			return tokenError(w, srv, err, nil)
+		}
+
+		return tokenResponse(w, srv.GetTokenData(ti), nil, http.StatusOK)
+	}
+
+	return httpjson.HandlerFunc(fn)
+}
+
+// grantedIdentity returns roles and permissions user service granted user logging in with login code
+func grantedIdentity(r *userproto.AuthenticateWithLoginCodeResponse) (identity.Identity, error) {
+	role, err := identity.ParseRole(r.GetRole())
+	if err != nil {
+		return identity.Identity{}, apperrors.Wrap(err)
+	}
+
+	var permissions identity.Permissions
+	for _, name := range r.GetPermissions() {
+		permission, err := identity.ParsePermission(name)
+		if err != nil {
+			return identity.Identity{}, apperrors.Wrap(err)
+		}
+		permissions = permissions.Add(permission)
+	}
+
+	return identity.Identity{
+		Permissions: permissions,
+		Role:        role,
+	}, nil
+}
+
+// tokenError responds with error the way oauth2 server does, extra fields are added to response
+func tokenError(w http.ResponseWriter, srv *server.Server, err error, extra map[string]interface{}) error {
+	data, statusCode, header := srv.GetErrorData(err)
+	for k, v := range extra {
+		data[k] = v
+	}
+
+	return tokenResponse(w, data, header, statusCode)
+}
+
+func tokenResponse(w http.ResponseWriter, data map[string]interface{}, header http.Header, statusCode int) error {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	for key := range header {
+		w.Header().Set(key, header.Get(key))
+	}
+
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
@@ -1,58 +1,18 @@
 package handlers
 
 import (
-	"io/ioutil"
 	"math"
 	"net/http"
 	"strconv"
 
 	"github.com/vardius/gorouter/v4/context"
 
-	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/client"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence"
-	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	httpjson "github.com/vardius/go-api-boilerplate/pkg/http/response/json"
 	"github.com/vardius/go-api-boilerplate/pkg/identity"
 )
 
-// BuildClientCommandDispatchHandler dispatches domain command
-func BuildClientCommandDispatchHandler(cb commandbus.CommandBus) http.Handler {
-	fn := func(w http.ResponseWriter, r *http.Request) error {
-		if r.Body == nil {
-			return apperrors.Wrap(apperrors.ErrInvalid)
-		}
-
-		params, ok := context.Parameters(r.Context())
-		if !ok {
-			return apperrors.Wrap(apperrors.ErrInvalid)
-		}
-
-		defer r.Body.Close()
-		body, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			return apperrors.Wrap(err)
-		}
-
-		c, err := client.NewCommandFromPayload(params.Value("command"), body)
-		if err != nil {
-			return apperrors.Wrap(err)
-		}
-
-		if err := cb.Publish(r.Context(), c); err != nil {
-			return apperrors.Wrap(err)
-		}
-
-		if err := httpjson.JSON(r.Context(), w, http.StatusCreated, nil); err != nil {
-			return apperrors.Wrap(err)
-		}
-
-		return nil
-	}
-
-	return httpjson.HandlerFunc(fn)
-}
-
 func BuildGetClientHandler(repository persistence.ClientRepository) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) error {
 		params, ok := context.Parameters(r.Context())
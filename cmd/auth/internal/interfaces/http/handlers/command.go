@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vardius/gorouter/v4/context"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/execution"
+	httpjson "github.com/vardius/go-api-boilerplate/pkg/http/response/json"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+// BuildCommandDispatchHandler decodes command registered for contract from request body and dispatches it,
+// only given contracts can be dispatched so each route accepts commands of its own context
+func BuildCommandDispatchHandler(commands *registry.Registry, cb commandbus.CommandBus, dispatcher *execution.Dispatcher, contracts ...string) http.Handler {
+	allowed := make(map[string]struct{}, len(contracts))
+	for _, name := range contracts {
+		allowed[name] = struct{}{}
+	}
+
+	fn := func(w http.ResponseWriter, r *http.Request) error {
+		if r.Body == nil {
+			return apperrors.Wrap(apperrors.ErrInvalid)
+		}
+
+		params, ok := context.Parameters(r.Context())
+		if !ok {
+			return apperrors.Wrap(apperrors.ErrInvalid)
+		}
+
+		contractName := params.Value("command")
+		if _, ok := allowed[contractName]; !ok {
+			return apperrors.Wrap(fmt.Errorf("%w: command %s", apperrors.ErrNotFound, contractName))
+		}
+
+		defer r.Body.Close()
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		c, err := commands.NewCommand(r.Context(), contractName, body)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return dispatchCommand(w, r, cb, dispatcher, c)
+	}
+
+	return httpjson.HandlerFunc(fn)
+}
+
+// BuildGetCommandHandler returns status of command dispatched asynchronously
+func BuildGetCommandHandler(store execution.Store) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) error {
+		params, ok := context.Parameters(r.Context())
+		if !ok {
+			return apperrors.Wrap(apperrors.ErrInvalid)
+		}
+
+		id, err := uuid.Parse(params.Value("id"))
+		if err != nil {
+			return apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrInvalid, err))
+		}
+
+		e, err := store.Get(r.Context(), id)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		// commands dispatched by authenticated users are visible only to them
+		if e.UserID != uuid.Nil {
+			if i, ok := identity.FromContext(r.Context()); !ok || i.UserID != e.UserID {
+				return apperrors.Wrap(fmt.Errorf("%w: execution %s", apperrors.ErrNotFound, id))
+			}
+		}
+
+		if err := httpjson.JSON(r.Context(), w, http.StatusOK, e); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return httpjson.HandlerFunc(fn)
+}
+
+// dispatchCommand publishes command and responds with 201 Created once it is handled,
+// if request has Prefer: respond-async header command is handled in the background
+// and 202 Accepted is returned with Location of command status
+func dispatchCommand(w http.ResponseWriter, r *http.Request, cb commandbus.CommandBus, dispatcher *execution.Dispatcher, c domain.Command) error {
+	if !prefersAsync(r) {
+		if err := cb.Publish(r.Context(), c); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := httpjson.JSON(r.Context(), w, http.StatusCreated, nil); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	e, err := dispatcher.Dispatch(r.Context(), c)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/v1/commands/%s", e.ID))
+	w.Header().Set("Preference-Applied", "respond-async")
+
+	if err := httpjson.JSON(r.Context(), w, http.StatusAccepted, e); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func prefersAsync(r *http.Request) bool {
+	for _, value := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(preference), "respond-async") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
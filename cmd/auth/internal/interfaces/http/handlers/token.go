@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"io/ioutil"
 	"math"
 	"net/http"
 	"strconv"
@@ -9,51 +8,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/vardius/gorouter/v4/context"
 
-	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/token"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence"
-	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	httpjson "github.com/vardius/go-api-boilerplate/pkg/http/response/json"
 	"github.com/vardius/go-api-boilerplate/pkg/identity"
 )
 
-// BuildTokenCommandDispatchHandler dispatches domain command
-func BuildTokenCommandDispatchHandler(cb commandbus.CommandBus) http.Handler {
-	fn := func(w http.ResponseWriter, r *http.Request) error {
-		if r.Body == nil {
-			return apperrors.Wrap(apperrors.ErrInvalid)
-		}
-
-		params, ok := context.Parameters(r.Context())
-		if !ok {
-			return apperrors.Wrap(apperrors.ErrInvalid)
-		}
-
-		defer r.Body.Close()
-		body, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			return apperrors.Wrap(err)
-		}
-
-		c, err := token.NewCommandFromPayload(params.Value("command"), body)
-		if err != nil {
-			return apperrors.Wrap(err)
-		}
-
-		if err := cb.Publish(r.Context(), c); err != nil {
-			return apperrors.Wrap(err)
-		}
-
-		if err := httpjson.JSON(r.Context(), w, http.StatusCreated, nil); err != nil {
-			return apperrors.Wrap(err)
-		}
-
-		return nil
-	}
-
-	return httpjson.HandlerFunc(fn)
-}
-
 // BuildListTokensHandler lists auth tokens by client and user IDs
 func BuildListTokensHandler(repository persistence.TokenRepository, clientRepository persistence.ClientRepository) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) error {
@@ -6,16 +6,24 @@ import (
 	"time"
 
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/application/config"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/client"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/token"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/infrastructure/persistence"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/interfaces/http/handlers"
+	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
 	"github.com/vardius/go-api-boilerplate/pkg/auth"
 	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+	"github.com/vardius/go-api-boilerplate/pkg/execution"
 	httpmiddleware "github.com/vardius/go-api-boilerplate/pkg/http/middleware"
 	httpauthenticator "github.com/vardius/go-api-boilerplate/pkg/http/middleware/authenticator"
 	"github.com/vardius/go-api-boilerplate/pkg/http/response/json"
+	"github.com/vardius/go-api-boilerplate/pkg/idempotency"
 	"github.com/vardius/go-api-boilerplate/pkg/identity"
 	"github.com/vardius/gorouter/v4"
 	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 	"gopkg.in/oauth2.v4/server"
 )
@@ -26,12 +34,18 @@ func NewRouter(
 	tokenAuthorizer auth.TokenAuthorizer,
 	server *server.Server,
 	commandBus commandbus.CommandBus,
+	commands *registry.Registry,
+	eventBus eventbus.EventBus,
+	executionStore execution.Store,
+	idempotencyStore idempotency.Store,
 	sqlConn *sql.DB, mongoConn *mongo.Client,
 	grpcConnectionMap map[string]*grpc.ClientConn,
 	tokenRepository persistence.TokenRepository,
 	clientRepository persistence.ClientRepository,
+	userClient userproto.UserServiceClient,
 ) http.Handler {
 	authenticator := httpauthenticator.NewToken(tokenAuthorizer.Auth)
+	dispatcher := execution.NewDispatcher(commandBus, executionStore, cfg.Execution.TTL)
 
 	// Global middleware
 	router := gorouter.New(
@@ -58,9 +72,12 @@ func NewRouter(
 	router.GET("/authorize", authorizeHandler)
 	router.POST("/authorize", authorizeHandler)
 	router.POST("/token", handlers.BuildTokenHandler(server))
+	router.POST("/token/login-code", handlers.BuildLoginCodeHandler(server, userClient, cfg.OAuth.LoginAccessTokenTTL))
+	router.GET("/contracts", handlers.BuildContractsHandler())
 
-	router.POST("/dispatch/client/{command}", handlers.BuildClientCommandDispatchHandler(commandBus))
-	router.POST("/dispatch/token/{command}", handlers.BuildTokenCommandDispatchHandler(commandBus))
+	router.POST("/dispatch/client/{command}", handlers.BuildCommandDispatchHandler(commands, commandBus, dispatcher, client.CreateClientCredentials, client.RemoveClientCredentials))
+	router.POST("/dispatch/token/{command}", handlers.BuildCommandDispatchHandler(commands, commandBus, dispatcher, token.CreateAuthToken, token.RemoveAuthToken))
+	router.GET("/commands/{id}", handlers.BuildGetCommandHandler(executionStore))
 
 	router.GET("/clients", handlers.BuildListClientsHandler(clientRepository))
 	router.GET("/clients/{clientID}", handlers.BuildGetClientHandler(clientRepository))
@@ -71,6 +88,8 @@ func NewRouter(
 	router.USE(http.MethodGet, "/users", httpmiddleware.GrantAccessFor(identity.PermissionTokenRead))
 	router.USE(http.MethodGet, "/clients", httpmiddleware.GrantAccessFor(identity.PermissionClientRead))
 	router.USE(http.MethodPost, "/dispatch", httpmiddleware.GrantAccessFor(identity.PermissionClientWrite))
+	router.USE(http.MethodPost, "/dispatch", httpmiddleware.Idempotent(idempotencyStore, cfg.Idempotency.TTL))
+	router.USE(http.MethodPost, "/token/login-code", httpmiddleware.RateLimit(rate.Every(time.Minute/5), 5, 10*time.Minute)) // slows down guessing login codes and one-time passwords
 
 	mainRouter := gorouter.New()
 	mainRouter.NotFound(json.NotFound())
@@ -80,7 +99,7 @@ func NewRouter(
 	// Liveness probes are to indicate that your application is running
 	mainRouter.GET("/health", handlers.BuildLivenessHandler())
 	// Readiness is meant to check if your application is ready to serve traffic
-	mainRouter.GET("/readiness", handlers.BuildReadinessHandler(sqlConn, mongoConn, grpcConnectionMap))
+	mainRouter.GET("/readiness", handlers.BuildReadinessHandler(sqlConn, mongoConn, grpcConnectionMap, eventBus))
 
 	mainRouter.Mount("/v1", router)
 
@@ -16,11 +16,14 @@ import (
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/application/config"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/application/services"
 	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/application/services/oauth2"
+	"github.com/vardius/go-api-boilerplate/cmd/auth/internal/domain/token"
 	authgrpc "github.com/vardius/go-api-boilerplate/cmd/auth/internal/interfaces/grpc"
 	authhttp "github.com/vardius/go-api-boilerplate/cmd/auth/internal/interfaces/http"
 	authproto "github.com/vardius/go-api-boilerplate/cmd/auth/proto"
 	"github.com/vardius/go-api-boilerplate/pkg/application"
 	"github.com/vardius/go-api-boilerplate/pkg/buildinfo"
+	commandbusgrpc "github.com/vardius/go-api-boilerplate/pkg/commandbus/grpc"
+	commandbusproto "github.com/vardius/go-api-boilerplate/pkg/commandbus/grpc/proto"
 	grpcutils "github.com/vardius/go-api-boilerplate/pkg/grpc"
 	httputils "github.com/vardius/go-api-boilerplate/pkg/http"
 )
@@ -52,6 +55,9 @@ func main() {
 	if err := domain.RegisterTokenDomain(ctx, cfg, container); err != nil {
 		panic(err)
 	}
+	if err := domain.RegisterUserIntegration(ctx, cfg, container); err != nil {
+		panic(err)
+	}
 
 	grpcServer := grpcutils.NewServer(
 		grpcutils.ServerConfig{
@@ -62,6 +68,7 @@ func main() {
 		[]grpc.UnaryServerInterceptor{
 			middleware.TransformUnaryOutgoingError(),
 			middleware.CountIncomingUnaryRequests(),
+			middleware.IdempotentUnaryRequest(container.IdempotencyStore, cfg.Idempotency.TTL),
 		},
 		[]grpc.StreamServerInterceptor{
 			middleware.TransformStreamOutgoingError(),
@@ -69,24 +76,31 @@ func main() {
 		},
 	)
 
-	oauth2Server := oauth2.InitServer(cfg, container.OAuth2Manager, container.ClientPersistenceRepository, cfg.OAuth.InitTimeout)
-	grpcAuthServer := authgrpc.NewServer(oauth2Server, container.CommandBus)
+	oauth2Server := oauth2.InitServer(cfg, container.OAuth2Manager, container.ClientPersistenceRepository, container.UserClient, cfg.OAuth.InitTimeout)
+	grpcAuthServer := authgrpc.NewServer(oauth2Server, container.CommandBus, container.CommandRegistry)
 
 	router := authhttp.NewRouter(
 		cfg,
 		container.TokenAuthorizer,
 		oauth2Server,
 		container.CommandBus,
+		container.CommandRegistry,
+		container.EventBus,
+		container.ExecutionStore,
+		container.IdempotencyStore,
 		container.SQL,
 		container.Mongo,
 		map[string]*grpc.ClientConn{
-			"auth": container.AuthConn,
+			"auth":   container.AuthConn,
+			"pubsub": container.PubSubConn,
 		},
 		container.TokenPersistenceRepository,
 		container.ClientPersistenceRepository,
+		container.UserClient,
 	)
 
 	authproto.RegisterAuthenticationServiceServer(grpcServer, grpcAuthServer)
+	commandbusproto.RegisterCommandBusServer(grpcServer, commandbusgrpc.NewServer(container.CommandBus, container.CommandRegistry, token.CreateAuthToken, token.RemoveAuthToken))
 
 	app := application.New()
 	app.AddAdapters(
@@ -104,6 +118,7 @@ func main() {
 			fmt.Sprintf("%s:%d", cfg.GRPC.Host, cfg.GRPC.Port),
 			grpcServer,
 		),
+		container.CommandScheduler,
 	)
 
 	if cfg.App.Environment == "development" {
@@ -0,0 +1,9 @@
+package proto
+
+// Commands owned by auth service, they can be routed to it with pkg/commandbus/grpc
+const (
+	// CreateTokenCommandName is the name of command storing access token signed for user, it has to be dispatched with service identity
+	CreateTokenCommandName = "token.Create"
+	// RemoveTokenCommandName is the name of command removing access token of user, it has to be dispatched with service identity
+	RemoveTokenCommandName = "token.Remove"
+)
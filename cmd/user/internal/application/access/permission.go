@@ -0,0 +1,12 @@
+package access
+
+import (
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+// Permissions defined by user service
+const (
+	// PermissionMFAChallenge is the only permission of tokens issued to users with multi-factor authentication enabled,
+	// it allows to exchange one-time password for access token
+	PermissionMFAChallenge identity.Permission = "mfa:challenge"
+)
@@ -1,16 +1,16 @@
 package access
 
-// Role type
-type Role uint8
+import (
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+// Role type, roles are carried by identity so they can be checked by firewall middlewares
+type Role = identity.Role
 
 // Roles
 const (
-	// @TODO: MANAGE YOUR ROLES HERE
-	RoleUser Role = 1 << iota
-	RoleAdmin
-	RoleSuperAdmin
+	// @TODO: MANAGE YOUR ROLES HERE, see identity.Role
+	RoleUser       = identity.RoleUser
+	RoleAdmin      = identity.RoleAdmin
+	RoleSuperAdmin = identity.RoleSuperAdmin
 )
-
-func (r Role) String() string {
-	return [...]string{"USER", "ADMIN", "SUPER_ADMIN"}[r>>1]
-}
@@ -5,6 +5,10 @@ import (
 	"time"
 
 	"github.com/caarlos0/env/v6"
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/auth/oidc"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
 )
 
 type Config struct {
@@ -68,6 +72,42 @@ type Config struct {
 		Host   string `env:"AUTH_HOST" envDefault:"0.0.0.0"` // Auth service host
 		Secret string `env:"AUTH_SECRET"                envDefault:"secret"`
 	}
+	PubSub struct {
+		Host           string        `env:"PUBSUB_HOST"            envDefault:"0.0.0.0"` // PubSub service host, integration events are exchanged through it
+		Port           int           `env:"PUBSUB_PORT"            envDefault:"3001"`
+		HandlerTimeout time.Duration `env:"PUBSUB_HANDLER_TIMEOUT" envDefault:"120s"`
+	}
+	Password struct {
+		MinLength     int  `env:"PASSWORD_MIN_LENGTH"     envDefault:"10"`
+		MaxLength     int  `env:"PASSWORD_MAX_LENGTH"     envDefault:"128"` // 0 means no limit
+		RequireUpper  bool `env:"PASSWORD_REQUIRE_UPPER"  envDefault:"false"`
+		RequireLower  bool `env:"PASSWORD_REQUIRE_LOWER"  envDefault:"false"`
+		RequireDigit  bool `env:"PASSWORD_REQUIRE_DIGIT"  envDefault:"false"`
+		RequireSymbol bool `env:"PASSWORD_REQUIRE_SYMBOL" envDefault:"false"`
+
+		HashMemory      uint32 `env:"PASSWORD_HASH_MEMORY"      envDefault:"65536"` // argon2id memory in KiB
+		HashIterations  uint32 `env:"PASSWORD_HASH_ITERATIONS"  envDefault:"3"`
+		HashParallelism uint8  `env:"PASSWORD_HASH_PARALLELISM" envDefault:"4"`
+	}
+	Verification struct {
+		TTL            time.Duration `env:"EMAIL_VERIFICATION_TTL"             envDefault:"24h"` // how long email verification link is valid
+		ResendInterval time.Duration `env:"EMAIL_VERIFICATION_RESEND_INTERVAL" envDefault:"1m"`  // how long user has to wait before requesting another link
+	}
+	LoginCode struct {
+		TTL time.Duration `env:"LOGIN_CODE_TTL" envDefault:"10m"` // how long single-use login code sent by email can be exchanged for access token
+	}
+	Deletion struct {
+		GracePeriod time.Duration `env:"ACCOUNT_DELETION_GRACE_PERIOD" envDefault:"720h"` // how long account deletion can be cancelled
+	}
+	Roles struct {
+		// Permissions granted to roles on top of permissions of regular user, format: ROLE:namespace:action,namespace:action|ROLE:namespace:action
+		Permissions identity.RolePermissions `env:"ROLE_PERMISSIONS"  envDefault:"ADMIN:client:read,token:read|SUPER_ADMIN:client:read,client:write,token:read"`
+		SuperAdmins []uuid.UUID              `env:"ROLE_SUPER_ADMINS" envSeparator:","` // users granted super admin role regardless of granted roles, allows to grant first roles
+	}
+	MFA struct {
+		Issuer       string        `env:"MFA_ISSUER"        envDefault:"go-api-boilerplate"` // name shown by authenticator apps
+		ChallengeTTL time.Duration `env:"MFA_CHALLENGE_TTL" envDefault:"15m"`                // how long token allowing to pass multi-factor authentication challenge is valid
+	}
 	Facebook struct {
 		ClientID     string `env:"FACEBOOK_CLIENT_ID"`
 		ClientSecret string `env:"FACEBOOK_CLIENT_SECRET"`
@@ -76,12 +116,27 @@ type Config struct {
 		ClientID     string `env:"GOOGLE_CLIENT_ID"`
 		ClientSecret string `env:"GOOGLE_CLIENT_SECRET"`
 	}
+	OIDC struct {
+		// Social login providers by name, JSON object, e.g. {"gitlab":{"issuer":"https://gitlab.com","client_id":"ID","client_secret":"SECRET"}}
+		Providers oidc.Configs `env:"OIDC_PROVIDERS"`
+	}
 	CommandBus struct {
-		QueueSize int `env:"COMMAND_BUS_BUFFER" envDefault:"100"`
+		QueueSize      int           `env:"COMMAND_BUS_BUFFER"          envDefault:"100"`
+		HandlerTimeout time.Duration `env:"COMMAND_BUS_HANDLER_TIMEOUT" envDefault:"30s"`
 	}
 	EventBus struct {
 		QueueSize int `env:"COMMAND_BUS_BUFFER" envDefault:"100"`
 	}
+	Execution struct {
+		TTL time.Duration `env:"COMMAND_EXECUTION_TTL" envDefault:"24h"` // how long status of asynchronously dispatched command is kept
+	}
+	Idempotency struct {
+		TTL time.Duration `env:"IDEMPOTENCY_KEY_TTL" envDefault:"24h"` // how long outcome of request is replayed for the same idempotency key
+	}
+	Scheduler struct {
+		PollInterval time.Duration `env:"COMMAND_SCHEDULER_POLL_INTERVAL" envDefault:"1s"` // how often due commands are looked up
+		Lease        time.Duration `env:"COMMAND_SCHEDULER_LEASE"         envDefault:"1m"` // how long claimed command is locked for other replicas, should exceed COMMAND_BUS_HANDLER_TIMEOUT
+	}
 }
 
 func FromEnv() *Config {
@@ -111,12 +166,51 @@ func FromEnv() *Config {
 	if err := env.Parse(&c.Auth); err != nil {
 		panic(err)
 	}
+	if err := env.Parse(&c.PubSub); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.Password); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.Verification); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.LoginCode); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.Deletion); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.Roles); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.MFA); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.Facebook); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.Google); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.OIDC); err != nil {
+		panic(err)
+	}
 	if err := env.Parse(&c.CommandBus); err != nil {
 		panic(err)
 	}
 	if err := env.Parse(&c.EventBus); err != nil {
 		panic(err)
 	}
+	if err := env.Parse(&c.Execution); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.Idempotency); err != nil {
+		panic(err)
+	}
+	if err := env.Parse(&c.Scheduler); err != nil {
+		panic(err)
+	}
 
 	if c.CommandBus.QueueSize == 0 {
 		c.CommandBus.QueueSize = runtime.NumCPU()
@@ -127,3 +221,20 @@ func FromEnv() *Config {
 
 	return &c
 }
+
+// IdentityProviders returns social login providers, google and facebook are enabled when their client id is set
+// unless provider with the same name is configured with OIDC_PROVIDERS
+func (c *Config) IdentityProviders() oidc.Configs {
+	configs := make(oidc.Configs, len(c.OIDC.Providers)+2)
+	if c.Google.ClientID != "" {
+		configs["google"] = oidc.Google(c.Google.ClientID, c.Google.ClientSecret)
+	}
+	if c.Facebook.ClientID != "" {
+		configs["facebook"] = oidc.Facebook(c.Facebook.ClientID, c.Facebook.ClientSecret)
+	}
+	for name, provider := range c.OIDC.Providers {
+		configs[name] = provider
+	}
+
+	return configs
+}
@@ -6,7 +6,11 @@ import (
 )
 
 var (
-	Login *template.Template
+	Login        *template.Template
+	Verification *template.Template
+
+	EmailChangeConfirmation *template.Template
+	EmailChangeNotification *template.Template
 )
 
 func init() {
@@ -17,4 +21,22 @@ func init() {
 	if err != nil {
 		panic(fmt.Sprintf("Could not load email login template: %s", err.Error()))
 	}
+
+	verificationTemplate := template.New("verification.html")
+	Verification, err = verificationTemplate.Parse(verificationHTML)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load email verification template: %s", err.Error()))
+	}
+
+	emailChangeConfirmationTemplate := template.New("email_change_confirmation.html")
+	EmailChangeConfirmation, err = emailChangeConfirmationTemplate.Parse(emailChangeConfirmationHTML)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load email change confirmation template: %s", err.Error()))
+	}
+
+	emailChangeNotificationTemplate := template.New("email_change_notification.html")
+	EmailChangeNotification, err = emailChangeNotificationTemplate.Parse(emailChangeNotificationHTML)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load email change notification template: %s", err.Error()))
+	}
 }
@@ -41,6 +41,7 @@ const loginHTML = `
 
   <body>
     <a href={{ .LoginURL }}>Login</a>
+    <p>Link can be used once and expires at {{ .ExpiresAt }}</p>
 
     <!-- prevent Gmail on iOS font size manipulation -->
     <div style="display:none;white-space:nowrap;font:15px courier;line-height:0">&nbsp; &nbsp; &nbsp; &nbsp; &nbsp; &nbsp;
@@ -0,0 +1,36 @@
+package eventhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+	"github.com/vardius/go-api-boilerplate/pkg/executioncontext"
+)
+
+// WhenUserAccountDeletionWasRequested handles event, schedules account deletion once grace period passes,
+// scheduled command is not cancelled when deletion is cancelled as aggregate rejects it anyway
+func WhenUserAccountDeletionWasRequested(scheduler commandbus.Scheduler) eventbus.EventHandler {
+	fn := func(parentCtx context.Context, event *domain.Event) error {
+		ctx, cancel := context.WithTimeout(parentCtx, time.Second*120)
+		defer cancel()
+
+		if !executioncontext.Has(ctx, executioncontext.LIVE) {
+			return nil
+		}
+
+		e := event.Payload.(*user.AccountDeletionWasRequested)
+
+		if _, err := scheduler.Schedule(ctx, user.DeleteAccount{ID: e.ID}, e.DeleteAt); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
@@ -0,0 +1,41 @@
+package eventhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+	"github.com/vardius/go-api-boilerplate/pkg/executioncontext"
+)
+
+// WhenUserConnectedWithProvider handles event
+func WhenUserConnectedWithProvider(repository persistence.UserRepository, cb commandbus.CommandBus) eventbus.EventHandler {
+	fn := func(parentCtx context.Context, event *domain.Event) error {
+		ctx, cancel := context.WithTimeout(parentCtx, time.Second*120)
+		defer cancel()
+
+		e := event.Payload.(*user.ConnectedWithProvider)
+
+		if err := repository.AddProvider(ctx, e.ID.String(), e.Provider, e.Subject); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if executioncontext.Has(ctx, executioncontext.LIVE) {
+			if err := cb.Publish(ctx, user.RequestAccessToken{
+				ID:           e.ID,
+				RedirectPath: e.RedirectPath,
+			}); err != nil {
+				return apperrors.Wrap(err)
+			}
+		}
+
+		return nil
+	}
+
+	return fn
+}
@@ -0,0 +1,30 @@
+package eventhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+)
+
+// WhenUserDisconnectedFromGoogle handles event
+func WhenUserDisconnectedFromGoogle(repository persistence.UserRepository) eventbus.EventHandler {
+	fn := func(parentCtx context.Context, event *domain.Event) error {
+		ctx, cancel := context.WithTimeout(parentCtx, time.Second*120)
+		defer cancel()
+
+		e := event.Payload.(*user.DisconnectedFromGoogle)
+
+		if err := repository.UpdateGoogleID(ctx, e.ID.String(), ""); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
@@ -22,6 +22,9 @@ func WhenUserEmailAddressWasChanged(repository persistence.UserRepository) event
 		if err := repository.UpdateEmail(ctx, e.ID.String(), string(e.Email)); err != nil {
 			return apperrors.Wrap(err)
 		}
+		if err := repository.UpdateVerified(ctx, e.ID.String(), false); err != nil {
+			return apperrors.Wrap(err)
+		}
 
 		return nil
 	}
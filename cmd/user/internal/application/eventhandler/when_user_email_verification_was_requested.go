@@ -0,0 +1,36 @@
+package eventhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/config"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/services/mailer"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+	"github.com/vardius/go-api-boilerplate/pkg/executioncontext"
+)
+
+// WhenUserEmailVerificationWasRequested handles event
+func WhenUserEmailVerificationWasRequested(cfg *config.Config) eventbus.EventHandler {
+	fn := func(parentCtx context.Context, event *domain.Event) error {
+		ctx, cancel := context.WithTimeout(parentCtx, time.Second*120)
+		defer cancel()
+
+		if !executioncontext.Has(ctx, executioncontext.LIVE) {
+			return nil
+		}
+
+		e := event.Payload.(*user.EmailVerificationWasRequested)
+
+		if err := mailer.SendVerificationEmail(ctx, cfg, e.Email.String(), e.ID.String(), e.Token, e.ExpiresAt); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
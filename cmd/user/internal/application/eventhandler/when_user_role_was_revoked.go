@@ -0,0 +1,46 @@
+package eventhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
+	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+	"github.com/vardius/go-api-boilerplate/pkg/executioncontext"
+)
+
+// WhenUserRoleWasRevoked handles event, removes role from user view model
+// and notifies auth service with integration event so it revokes access tokens carrying revoked role
+func WhenUserRoleWasRevoked(repository persistence.UserRepository, integrationEventBus eventbus.EventBus) eventbus.EventHandler {
+	fn := func(parentCtx context.Context, event *domain.Event) error {
+		ctx, cancel := context.WithTimeout(parentCtx, time.Second*120)
+		defer cancel()
+
+		e := event.Payload.(*user.RoleWasRevoked)
+
+		if err := repository.RemoveRole(ctx, e.ID.String(), e.Role); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if !executioncontext.Has(ctx, executioncontext.LIVE) {
+			return nil
+		}
+
+		integrationEvent, err := domain.NewEventFromRawEvent(event.StreamID, event.StreamName, event.StreamVersion, userproto.UserRoleWasRevoked{UserID: e.ID})
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := integrationEventBus.Publish(ctx, integrationEvent); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
@@ -0,0 +1,51 @@
+package eventhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
+	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+	"github.com/vardius/go-api-boilerplate/pkg/eventstore"
+	"github.com/vardius/go-api-boilerplate/pkg/executioncontext"
+)
+
+// WhenUserWasDeleted handles event, removes user view model and user event stream holding personal data
+// and notifies other services with integration event so they can remove user data as well
+func WhenUserWasDeleted(repository persistence.UserRepository, eventStore eventstore.EventStore, integrationEventBus eventbus.EventBus) eventbus.EventHandler {
+	fn := func(parentCtx context.Context, event *domain.Event) error {
+		ctx, cancel := context.WithTimeout(parentCtx, time.Second*120)
+		defer cancel()
+
+		e := event.Payload.(*user.WasDeleted)
+
+		if err := repository.Delete(ctx, e.ID.String()); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := eventStore.DeleteStream(ctx, event.StreamID, event.StreamName); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if !executioncontext.Has(ctx, executioncontext.LIVE) {
+			return nil
+		}
+
+		integrationEvent, err := domain.NewEventFromRawEvent(event.StreamID, event.StreamName, event.StreamVersion, userproto.UserWasDeleted{UserID: e.ID})
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := integrationEventBus.Publish(ctx, integrationEvent); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
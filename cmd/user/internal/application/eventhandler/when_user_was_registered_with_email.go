@@ -21,7 +21,7 @@ func WhenUserWasRegisteredWithEmail(repository persistence.UserRepository, cb co
 
 		e := event.Payload.(*user.WasRegisteredWithEmail)
 
-		if err := repository.Add(ctx, e); err != nil {
+		if err := repository.Add(ctx, e, event.OccurredAt); err != nil {
 			return apperrors.Wrap(err)
 		}
 
@@ -21,7 +21,7 @@ func WhenUserWasRegisteredWithFacebook(repository persistence.UserRepository, cb
 
 		e := event.Payload.(*user.WasRegisteredWithFacebook)
 
-		if err := repository.Add(ctx, e); err != nil {
+		if err := repository.Add(ctx, e, event.OccurredAt); err != nil {
 			return apperrors.Wrap(err)
 		}
 
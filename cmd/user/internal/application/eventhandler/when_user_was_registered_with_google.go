@@ -21,7 +21,7 @@ func WhenUserWasRegisteredWithGoogle(repository persistence.UserRepository, cb c
 
 		e := event.Payload.(*user.WasRegisteredWithGoogle)
 
-		if err := repository.Add(ctx, e); err != nil {
+		if err := repository.Add(ctx, e, event.OccurredAt); err != nil {
 			return apperrors.Wrap(err)
 		}
 
@@ -0,0 +1,4 @@
+/*
+Package saga provides process managers of user service
+*/
+package saga
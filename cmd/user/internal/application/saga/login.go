@@ -0,0 +1,60 @@
+package saga
+
+import (
+	"context"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/config"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/services/mailer"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	basesaga "github.com/vardius/go-api-boilerplate/pkg/saga"
+)
+
+// LoginName identifies login saga
+const LoginName = "login"
+
+const (
+	// loginTimeout is the time login email has to be sent within
+	loginTimeout = 15 * time.Minute
+
+	stepSendEmail = "send-email"
+)
+
+// Login sends single-use login code requested by user by email, every access token request is a separate instance,
+// code is exchanged for access token with auth service so no credential is ever sent by email
+func Login(cfg *config.Config) basesaga.Definition {
+	return basesaga.Definition{
+		Name: LoginName,
+		Correlate: func(event *domain.Event) string {
+			return event.ID.String()
+		},
+		Handlers: map[string]basesaga.Handler{
+			user.AccessTokenWasRequestedType: onAccessTokenWasRequested(cfg),
+		},
+		Timeout: loginTimeout,
+	}
+}
+
+func onAccessTokenWasRequested(cfg *config.Config) basesaga.Handler {
+	return func(parentCtx context.Context, instance *basesaga.Instance, event *domain.Event) error {
+		ctx, cancel := context.WithTimeout(parentCtx, time.Second*120)
+		defer cancel()
+
+		e := event.Payload.(*user.AccessTokenWasRequested)
+
+		// events recorded before login codes were introduced have nothing to send
+		if e.Code != "" {
+			if err := instance.Step(ctx, stepSendEmail, func(ctx context.Context) error {
+				return mailer.SendLoginEmail(ctx, cfg, string(e.Email), e.ID.String(), e.Code, e.ExpiresAt, e.RedirectPath)
+			}); err != nil {
+				return apperrors.Wrap(err)
+			}
+		}
+
+		instance.Complete()
+
+		return nil
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/smtp"
 	"net/url"
+	"time"
 
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/config"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/email"
@@ -30,17 +31,21 @@ func (a unencryptedAuth) Start(server *smtp.ServerInfo) (string, []byte, error)
 	return a.Auth.Start(&s)
 }
 
-func SendLoginEmail(ctx context.Context, cfg *config.Config, to string, authToken, redirectPath string) error {
+// SendLoginEmail sends link carrying single-use login code to be exchanged for access token with auth service
+func SendLoginEmail(ctx context.Context, cfg *config.Config, to, userID, code string, expiresAt time.Time, redirectPath string) error {
 	var template bytes.Buffer
 	if err := email.Login.Execute(&template, struct {
-		Title    string
-		LoginURL string
+		Title     string
+		LoginURL  string
+		ExpiresAt string
 	}{
 		Title: "Login to go-api-boilerplate",
-		LoginURL: fmt.Sprintf("%s?%s", cfg.App.Domain, url.Values{
-			"r":         []string{redirectPath},
-			"authToken": []string{authToken},
+		LoginURL: fmt.Sprintf("%s/login?%s", cfg.App.Domain, url.Values{
+			"r":    []string{redirectPath},
+			"id":   []string{userID},
+			"code": []string{code},
 		}.Encode()),
+		ExpiresAt: expiresAt.UTC().Format(time.RFC1123),
 	}); err != nil {
 		return apperrors.Wrap(err)
 	}
@@ -48,6 +53,68 @@ func SendLoginEmail(ctx context.Context, cfg *config.Config, to string, authToke
 	return sendHTMLEmail(cfg, "Login to go-api-boilerplate", FROM, []string{to}, template.Bytes())
 }
 
+func SendVerificationEmail(ctx context.Context, cfg *config.Config, to, userID, token string, expiresAt time.Time) error {
+	var template bytes.Buffer
+	if err := email.Verification.Execute(&template, struct {
+		Title           string
+		VerificationURL string
+		ExpiresAt       string
+	}{
+		Title: "Verify your email address",
+		VerificationURL: fmt.Sprintf("%s/verify-email?%s", cfg.App.Domain, url.Values{
+			"id":    []string{userID},
+			"token": []string{token},
+		}.Encode()),
+		ExpiresAt: expiresAt.UTC().Format(time.RFC1123),
+	}); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return sendHTMLEmail(cfg, "Verify your email address", FROM, []string{to}, template.Bytes())
+}
+
+// SendEmailChangeConfirmationEmail sends link confirming email address change to new email address
+func SendEmailChangeConfirmationEmail(ctx context.Context, cfg *config.Config, to, userID, token string, expiresAt time.Time) error {
+	var template bytes.Buffer
+	if err := email.EmailChangeConfirmation.Execute(&template, struct {
+		Title           string
+		ConfirmationURL string
+		ExpiresAt       string
+	}{
+		Title: "Confirm your new email address",
+		ConfirmationURL: fmt.Sprintf("%s/confirm-email-change?%s", cfg.App.Domain, url.Values{
+			"id":    []string{userID},
+			"token": []string{token},
+		}.Encode()),
+		ExpiresAt: expiresAt.UTC().Format(time.RFC1123),
+	}); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return sendHTMLEmail(cfg, "Confirm your new email address", FROM, []string{to}, template.Bytes())
+}
+
+// SendEmailChangeNotificationEmail notifies current email address about requested change, it links to cancellation
+func SendEmailChangeNotificationEmail(ctx context.Context, cfg *config.Config, to, newEmail, userID, cancellationToken string) error {
+	var template bytes.Buffer
+	if err := email.EmailChangeNotification.Execute(&template, struct {
+		Title           string
+		NewEmail        string
+		CancellationURL string
+	}{
+		Title:    "Your email address is being changed",
+		NewEmail: newEmail,
+		CancellationURL: fmt.Sprintf("%s/cancel-email-change?%s", cfg.App.Domain, url.Values{
+			"id":    []string{userID},
+			"token": []string{cancellationToken},
+		}.Encode()),
+	}); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return sendHTMLEmail(cfg, "Your email address is being changed", FROM, []string{to}, template.Bytes())
+}
+
 func sendHTMLEmail(cfg *config.Config, subject, from string, to []string, body []byte) error {
 	if from == "" {
 		from = FROM
@@ -6,15 +6,29 @@ package services
 import (
 	"context"
 
+	"google.golang.org/grpc"
+
 	authproto "github.com/vardius/go-api-boilerplate/cmd/auth/proto"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/config"
 	persistence "github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence/memory"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/repository"
 	"github.com/vardius/go-api-boilerplate/pkg/auth"
+	commandbusgrpc "github.com/vardius/go-api-boilerplate/pkg/commandbus/grpc"
 	memorycommandbus "github.com/vardius/go-api-boilerplate/pkg/commandbus/memory"
+	commandbusmiddleware "github.com/vardius/go-api-boilerplate/pkg/commandbus/middleware"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	memoryeventbus "github.com/vardius/go-api-boilerplate/pkg/eventbus/memory"
+	pubsubeventbus "github.com/vardius/go-api-boilerplate/pkg/eventbus/pubsub"
 	memoryeventstore "github.com/vardius/go-api-boilerplate/pkg/eventstore/memory"
+	memoryexecution "github.com/vardius/go-api-boilerplate/pkg/execution/memory"
 	grpcutils "github.com/vardius/go-api-boilerplate/pkg/grpc"
+	memoryidempotency "github.com/vardius/go-api-boilerplate/pkg/idempotency/memory"
+	"github.com/vardius/go-api-boilerplate/pkg/saga"
+	memorysaga "github.com/vardius/go-api-boilerplate/pkg/saga/memory"
+	"github.com/vardius/go-api-boilerplate/pkg/scheduler"
+	memoryscheduler "github.com/vardius/go-api-boilerplate/pkg/scheduler/memory"
+	pubsubproto "github.com/vardius/pubsub/v2/proto"
 )
 
 func init() {
@@ -22,7 +36,14 @@ func init() {
 }
 
 func newMemoryServiceContainer(ctx context.Context, cfg *config.Config) (*ServiceContainer, error) {
-	commandBus := memorycommandbus.New(cfg.CommandBus.QueueSize)
+	localCommandBus := memorycommandbus.New(
+		cfg.CommandBus.QueueSize,
+		commandbusmiddleware.Recover(),
+		commandbusmiddleware.Logger(),
+		commandbusmiddleware.Metrics(),
+		commandbusmiddleware.Timeout(cfg.CommandBus.HandlerTimeout),
+		commandbusmiddleware.Validate(),
+	)
 	grpcUserConn := grpcutils.NewConnection(
 		ctx,
 		cfg.GRPC.Host,
@@ -41,24 +62,56 @@ func newMemoryServiceContainer(ctx context.Context, cfg *config.Config) (*Servic
 			ConnTimeout: cfg.GRPC.ConnTimeout,
 		},
 	)
+	grpcPubSubConn := grpcutils.NewConnection(
+		ctx,
+		cfg.PubSub.Host,
+		cfg.PubSub.Port,
+		grpcutils.ConnectionConfig{
+			ConnTime:    cfg.GRPC.ConnTime,
+			ConnTimeout: cfg.GRPC.ConnTimeout,
+		},
+	)
+	commandBus := commandbusgrpc.New(localCommandBus, map[string]grpc.ClientConnInterface{
+		authproto.CreateTokenCommandName: grpcAuthConn,
+		authproto.RemoveTokenCommandName: grpcAuthConn,
+	})
+	commandRegistry := registry.New(commandBus)
 	eventStore := memoryeventstore.New()
 	eventBus := memoryeventbus.New(cfg.EventBus.QueueSize)
+	integrationEventBus := pubsubeventbus.New(cfg.PubSub.HandlerTimeout, pubsubproto.NewPubSubClient(grpcPubSubConn))
+	executionStore := memoryexecution.New()
+	idempotencyStore := memoryidempotency.New()
+	commandScheduler := scheduler.New(commandBus, memoryscheduler.New(), cfg.Scheduler.PollInterval, cfg.Scheduler.Lease)
+	sagaManager := saga.New(commandBus, eventBus, commandScheduler, memorysaga.New())
 	userPersistenceRepository := persistence.NewUserRepository()
 	userRepository := repository.NewUserRepository(eventStore, eventBus)
 	grpAuthClient := authproto.NewAuthenticationServiceClient(grpcAuthConn)
 	authenticator := auth.NewSecretAuthenticator([]byte(cfg.Auth.Secret))
 	claimsProvider := auth.NewClaimsProvider(authenticator)
 	tokenAuthorizer := auth.NewJWTTokenAuthorizer(grpAuthClient, claimsProvider, authenticator)
+	identityProviders, err := newIdentityProviders(cfg)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
 
 	return &ServiceContainer{
 		CommandBus:                commandBus,
+		CommandRegistry:           commandRegistry,
 		UserConn:                  grpcUserConn,
 		AuthConn:                  grpcAuthConn,
+		PubSubConn:                grpcPubSubConn,
 		EventBus:                  eventBus,
+		IntegrationEventBus:       integrationEventBus,
+		EventStore:                eventStore,
+		ExecutionStore:            executionStore,
+		IdempotencyStore:          idempotencyStore,
+		CommandScheduler:          commandScheduler,
+		SagaManager:               sagaManager,
 		AuthClient:                grpAuthClient,
 		TokenAuthorizer:           tokenAuthorizer,
 		UserRepository:            userRepository,
 		UserPersistenceRepository: userPersistenceRepository,
 		Authenticator:             authenticator,
+		IdentityProviders:         identityProviders,
 	}, nil
 }
@@ -7,16 +7,29 @@ import (
 	"context"
 	"fmt"
 
+	"google.golang.org/grpc"
+
 	authproto "github.com/vardius/go-api-boilerplate/cmd/auth/proto"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/config"
 	persistence "github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence/mongo"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/repository"
 	"github.com/vardius/go-api-boilerplate/pkg/auth"
+	commandbusgrpc "github.com/vardius/go-api-boilerplate/pkg/commandbus/grpc"
 	memorycommandbus "github.com/vardius/go-api-boilerplate/pkg/commandbus/memory"
+	commandbusmiddleware "github.com/vardius/go-api-boilerplate/pkg/commandbus/middleware"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	memoryeventbus "github.com/vardius/go-api-boilerplate/pkg/eventbus/memory"
+	pubsubeventbus "github.com/vardius/go-api-boilerplate/pkg/eventbus/pubsub"
 	mongoeventstore "github.com/vardius/go-api-boilerplate/pkg/eventstore/mongo"
+	mongoexecution "github.com/vardius/go-api-boilerplate/pkg/execution/mongo"
 	grpcutils "github.com/vardius/go-api-boilerplate/pkg/grpc"
+	mongoidempotency "github.com/vardius/go-api-boilerplate/pkg/idempotency/mongo"
+	"github.com/vardius/go-api-boilerplate/pkg/saga"
+	mongosaga "github.com/vardius/go-api-boilerplate/pkg/saga/mongo"
+	"github.com/vardius/go-api-boilerplate/pkg/scheduler"
+	mongoscheduler "github.com/vardius/go-api-boilerplate/pkg/scheduler/mongo"
+	pubsubproto "github.com/vardius/pubsub/v2/proto"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -26,7 +39,14 @@ func init() {
 }
 
 func newMongoServiceContainer(ctx context.Context, cfg *config.Config) (*ServiceContainer, error) {
-	commandBus := memorycommandbus.New(cfg.CommandBus.QueueSize)
+	localCommandBus := memorycommandbus.New(
+		cfg.CommandBus.QueueSize,
+		commandbusmiddleware.Recover(),
+		commandbusmiddleware.Logger(),
+		commandbusmiddleware.Metrics(),
+		commandbusmiddleware.Timeout(cfg.CommandBus.HandlerTimeout),
+		commandbusmiddleware.Validate(),
+	)
 	mongoConnection, err := mongo.Connect(ctx, options.Client().ApplyURI(
 		fmt.Sprintf("mongodb://%s:%s@%s:%d", cfg.MongoDB.User, cfg.MongoDB.Pass, cfg.MongoDB.Host, cfg.MongoDB.Port),
 	))
@@ -52,11 +72,44 @@ func newMongoServiceContainer(ctx context.Context, cfg *config.Config) (*Service
 			ConnTimeout: cfg.GRPC.ConnTimeout,
 		},
 	)
+	grpcPubSubConn := grpcutils.NewConnection(
+		ctx,
+		cfg.PubSub.Host,
+		cfg.PubSub.Port,
+		grpcutils.ConnectionConfig{
+			ConnTime:    cfg.GRPC.ConnTime,
+			ConnTimeout: cfg.GRPC.ConnTimeout,
+		},
+	)
+	commandBus := commandbusgrpc.New(localCommandBus, map[string]grpc.ClientConnInterface{
+		authproto.CreateTokenCommandName: grpcAuthConn,
+		authproto.RemoveTokenCommandName: grpcAuthConn,
+	})
+	commandRegistry := registry.New(commandBus)
 	eventStore, err := mongoeventstore.New(ctx, "events", mongoDB)
 	if err != nil {
 		return nil, apperrors.Wrap(err)
 	}
 	eventBus := memoryeventbus.New(cfg.EventBus.QueueSize)
+	integrationEventBus := pubsubeventbus.New(cfg.PubSub.HandlerTimeout, pubsubproto.NewPubSubClient(grpcPubSubConn))
+	executionStore, err := mongoexecution.New(ctx, "user_command_executions", mongoDB)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	idempotencyStore, err := mongoidempotency.New(ctx, "user_idempotency_keys", mongoDB)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	schedulerStore, err := mongoscheduler.New(ctx, "user_scheduled_commands", mongoDB)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	commandScheduler := scheduler.New(commandBus, schedulerStore, cfg.Scheduler.PollInterval, cfg.Scheduler.Lease)
+	sagaStore, err := mongosaga.New(ctx, "user_sagas", mongoDB)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	sagaManager := saga.New(commandBus, eventBus, commandScheduler, sagaStore)
 	userPersistenceRepository, err := persistence.NewUserRepository(ctx, mongoDB)
 	if err != nil {
 		return nil, apperrors.Wrap(err)
@@ -66,17 +119,30 @@ func newMongoServiceContainer(ctx context.Context, cfg *config.Config) (*Service
 	authenticator := auth.NewSecretAuthenticator([]byte(cfg.Auth.Secret))
 	claimsProvider := auth.NewClaimsProvider(authenticator)
 	tokenAuthorizer := auth.NewJWTTokenAuthorizer(grpAuthClient, claimsProvider, authenticator)
+	identityProviders, err := newIdentityProviders(cfg)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
 
 	return &ServiceContainer{
 		Mongo:                     mongoConnection,
 		CommandBus:                commandBus,
+		CommandRegistry:           commandRegistry,
 		UserConn:                  grpcUserConn,
 		AuthConn:                  grpcAuthConn,
+		PubSubConn:                grpcPubSubConn,
 		EventBus:                  eventBus,
+		IntegrationEventBus:       integrationEventBus,
+		EventStore:                eventStore,
+		ExecutionStore:            executionStore,
+		IdempotencyStore:          idempotencyStore,
+		CommandScheduler:          commandScheduler,
+		SagaManager:               sagaManager,
 		AuthClient:                grpAuthClient,
 		TokenAuthorizer:           tokenAuthorizer,
 		UserRepository:            userRepository,
 		UserPersistenceRepository: userPersistenceRepository,
 		Authenticator:             authenticator,
+		IdentityProviders:         identityProviders,
 	}, nil
 }
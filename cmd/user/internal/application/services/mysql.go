@@ -6,18 +6,31 @@ package services
 import (
 	"context"
 
+	"google.golang.org/grpc"
+
 	_ "github.com/go-sql-driver/mysql"
 	authproto "github.com/vardius/go-api-boilerplate/cmd/auth/proto"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/config"
 	persistence "github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence/mysql"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/repository"
 	"github.com/vardius/go-api-boilerplate/pkg/auth"
+	commandbusgrpc "github.com/vardius/go-api-boilerplate/pkg/commandbus/grpc"
 	memorycommandbus "github.com/vardius/go-api-boilerplate/pkg/commandbus/memory"
+	commandbusmiddleware "github.com/vardius/go-api-boilerplate/pkg/commandbus/middleware"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	memoryeventbus "github.com/vardius/go-api-boilerplate/pkg/eventbus/memory"
+	pubsubeventbus "github.com/vardius/go-api-boilerplate/pkg/eventbus/pubsub"
 	mysqleventstore "github.com/vardius/go-api-boilerplate/pkg/eventstore/mysql"
+	mysqlexecution "github.com/vardius/go-api-boilerplate/pkg/execution/mysql"
 	grpcutils "github.com/vardius/go-api-boilerplate/pkg/grpc"
+	mysqlidempotency "github.com/vardius/go-api-boilerplate/pkg/idempotency/mysql"
 	"github.com/vardius/go-api-boilerplate/pkg/mysql"
+	"github.com/vardius/go-api-boilerplate/pkg/saga"
+	mysqlsaga "github.com/vardius/go-api-boilerplate/pkg/saga/mysql"
+	"github.com/vardius/go-api-boilerplate/pkg/scheduler"
+	mysqlscheduler "github.com/vardius/go-api-boilerplate/pkg/scheduler/mysql"
+	pubsubproto "github.com/vardius/pubsub/v2/proto"
 )
 
 func init() {
@@ -25,7 +38,14 @@ func init() {
 }
 
 func newMYSQLServiceContainer(ctx context.Context, cfg *config.Config) (*ServiceContainer, error) {
-	commandBus := memorycommandbus.New(cfg.CommandBus.QueueSize)
+	localCommandBus := memorycommandbus.New(
+		cfg.CommandBus.QueueSize,
+		commandbusmiddleware.Recover(),
+		commandbusmiddleware.Logger(),
+		commandbusmiddleware.Metrics(),
+		commandbusmiddleware.Timeout(cfg.CommandBus.HandlerTimeout),
+		commandbusmiddleware.Validate(),
+	)
 	sqlConn := mysql.NewConnection(
 		ctx,
 		mysql.ConnectionConfig{
@@ -57,11 +77,44 @@ func newMYSQLServiceContainer(ctx context.Context, cfg *config.Config) (*Service
 			ConnTimeout: cfg.GRPC.ConnTimeout,
 		},
 	)
+	grpcPubSubConn := grpcutils.NewConnection(
+		ctx,
+		cfg.PubSub.Host,
+		cfg.PubSub.Port,
+		grpcutils.ConnectionConfig{
+			ConnTime:    cfg.GRPC.ConnTime,
+			ConnTimeout: cfg.GRPC.ConnTimeout,
+		},
+	)
+	commandBus := commandbusgrpc.New(localCommandBus, map[string]grpc.ClientConnInterface{
+		authproto.CreateTokenCommandName: grpcAuthConn,
+		authproto.RemoveTokenCommandName: grpcAuthConn,
+	})
+	commandRegistry := registry.New(commandBus)
 	eventStore, err := mysqleventstore.New(ctx, "user_events", sqlConn)
 	if err != nil {
 		return nil, apperrors.Wrap(err)
 	}
 	eventBus := memoryeventbus.New(cfg.EventBus.QueueSize)
+	integrationEventBus := pubsubeventbus.New(cfg.PubSub.HandlerTimeout, pubsubproto.NewPubSubClient(grpcPubSubConn))
+	executionStore, err := mysqlexecution.New(ctx, "user_command_executions", sqlConn)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	idempotencyStore, err := mysqlidempotency.New(ctx, "user_idempotency_keys", sqlConn)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	schedulerStore, err := mysqlscheduler.New(ctx, "user_scheduled_commands", sqlConn)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	commandScheduler := scheduler.New(commandBus, schedulerStore, cfg.Scheduler.PollInterval, cfg.Scheduler.Lease)
+	sagaStore, err := mysqlsaga.New(ctx, "user_sagas", sqlConn)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	sagaManager := saga.New(commandBus, eventBus, commandScheduler, sagaStore)
 	userPersistenceRepository, err := persistence.NewUserRepository(ctx, sqlConn)
 	if err != nil {
 		return nil, apperrors.Wrap(err)
@@ -71,17 +124,30 @@ func newMYSQLServiceContainer(ctx context.Context, cfg *config.Config) (*Service
 	authenticator := auth.NewSecretAuthenticator([]byte(cfg.Auth.Secret))
 	claimsProvider := auth.NewClaimsProvider(authenticator)
 	tokenAuthorizer := auth.NewJWTTokenAuthorizer(grpAuthClient, claimsProvider, authenticator)
+	identityProviders, err := newIdentityProviders(cfg)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
 
 	return &ServiceContainer{
 		SQL:                       sqlConn,
 		CommandBus:                commandBus,
+		CommandRegistry:           commandRegistry,
 		UserConn:                  grpcUserConn,
 		AuthConn:                  grpcAuthConn,
+		PubSubConn:                grpcPubSubConn,
 		EventBus:                  eventBus,
+		IntegrationEventBus:       integrationEventBus,
+		EventStore:                eventStore,
+		ExecutionStore:            executionStore,
+		IdempotencyStore:          idempotencyStore,
+		CommandScheduler:          commandScheduler,
+		SagaManager:               sagaManager,
 		AuthClient:                grpAuthClient,
 		TokenAuthorizer:           tokenAuthorizer,
 		UserRepository:            userRepository,
 		UserPersistenceRepository: userPersistenceRepository,
 		Authenticator:             authenticator,
+		IdentityProviders:         identityProviders,
 	}, nil
 }
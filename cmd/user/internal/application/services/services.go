@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 	"go.mongodb.org/mongo-driver/mongo"
+	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -16,8 +18,15 @@ import (
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
 	userpersistence "github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
 	"github.com/vardius/go-api-boilerplate/pkg/auth"
+	"github.com/vardius/go-api-boilerplate/pkg/auth/oidc"
 	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
 	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+	"github.com/vardius/go-api-boilerplate/pkg/eventstore"
+	"github.com/vardius/go-api-boilerplate/pkg/execution"
+	"github.com/vardius/go-api-boilerplate/pkg/idempotency"
+	"github.com/vardius/go-api-boilerplate/pkg/saga"
+	"github.com/vardius/go-api-boilerplate/pkg/scheduler"
 )
 
 type containerFactory func(ctx context.Context, cfg *config.Config) (*ServiceContainer, error)
@@ -30,14 +39,49 @@ type ServiceContainer struct {
 	Mongo *mongo.Client
 
 	CommandBus                commandbus.CommandBus
+	CommandRegistry           *registry.Registry
 	EventBus                  eventbus.EventBus
+	IntegrationEventBus       eventbus.EventBus // distributed event bus shared with other services
+	EventStore                eventstore.EventStore
+	ExecutionStore            execution.Store
+	IdempotencyStore          idempotency.Store
+	CommandScheduler          *scheduler.Scheduler
+	SagaManager               *saga.Manager
 	UserConn                  *grpc.ClientConn
 	AuthConn                  *grpc.ClientConn
+	PubSubConn                *grpc.ClientConn
 	UserRepository            user.Repository
 	UserPersistenceRepository userpersistence.UserRepository
 	AuthClient                authproto.AuthenticationServiceClient
 	TokenAuthorizer           auth.TokenAuthorizer
 	Authenticator             auth.Authenticator
+	IdentityProviders         *oidc.Registry
+}
+
+// identityProviderTimeout limits requests to identity providers
+const identityProviderTimeout = 10 * time.Second
+
+// newIdentityProviders creates registry of configured social login providers
+func newIdentityProviders(cfg *config.Config) (*oidc.Registry, error) {
+	configs := cfg.IdentityProviders()
+
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	client := &http.Client{Timeout: identityProviderTimeout}
+	providers := make([]*oidc.Provider, 0, len(names))
+	for _, name := range names {
+		p, err := oidc.NewProvider(configs[name], client)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+
+	return oidc.NewRegistry(providers...)
 }
 
 func (c *ServiceContainer) Close() error {
@@ -80,6 +124,14 @@ func (c *ServiceContainer) Close() error {
 			}
 		}
 	}()
+	go func() {
+		defer wg.Done()
+		if c.PubSubConn != nil {
+			if err := c.PubSubConn.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}()
 
 	wg.Wait()
 
@@ -0,0 +1,129 @@
+/*
+Package token signs user access tokens and stores them with auth service
+*/
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/cmd/auth/proto"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/access"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/config"
+	"github.com/vardius/go-api-boilerplate/pkg/auth"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	commandbusgrpc "github.com/vardius/go-api-boilerplate/pkg/commandbus/grpc"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+// TTL is the lifetime of user access token
+const TTL = 365 * 24 * time.Hour
+
+// Policy decides roles and permissions user access tokens are granted
+type Policy struct {
+	RolePermissions identity.RolePermissions // permissions granted to roles on top of permissions of regular user
+	SuperAdmins     []uuid.UUID              // users granted super admin role by configuration
+	ChallengeTTL    time.Duration            // lifetime of multi-factor authentication challenge token
+}
+
+// NewPolicy returns policy configured with ROLE_* and MFA_* environment variables
+func NewPolicy(cfg *config.Config) Policy {
+	return Policy{
+		RolePermissions: cfg.Roles.Permissions,
+		SuperAdmins:     cfg.Roles.SuperAdmins,
+		ChallengeTTL:    cfg.MFA.ChallengeTTL,
+	}
+}
+
+// Identity returns identity user access token is granted,
+// users with unverified email address can only manage their own account so their roles are not granted
+func (p Policy) Identity(userID uuid.UUID, verified bool, role identity.Role) identity.Identity {
+	for _, id := range p.SuperAdmins {
+		if id == userID {
+			role = role.Add(identity.RoleSuperAdmin)
+		}
+	}
+
+	permissions := identity.NewPermissions(identity.PermissionUserRead, identity.PermissionUserWrite)
+	if verified {
+		permissions = permissions.Add(identity.PermissionClientRead, identity.PermissionClientWrite, identity.PermissionTokenRead)
+		permissions = permissions.Add(p.RolePermissions.Permissions(role)...)
+	} else {
+		role = role & identity.RoleUser
+	}
+
+	return identity.Identity{
+		Permissions: permissions,
+		Role:        role,
+		UserID:      userID,
+	}
+}
+
+// ChallengeIdentity returns identity of token issued to user with multi-factor authentication enabled,
+// it only allows to pass the challenge in exchange for access token
+func (p Policy) ChallengeIdentity(userID uuid.UUID) identity.Identity {
+	return identity.Identity{
+		Permissions: identity.NewPermissions(access.PermissionMFAChallenge),
+		UserID:      userID,
+	}
+}
+
+// Sign signs access token of identity expiring at given time
+func Sign(signedMethod jwt.SigningMethod, authenticator auth.Authenticator, i identity.Identity, expiresAt time.Time) (string, error) {
+	claims := &auth.Claims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   i.UserID.String(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+		Identity: &i,
+	}
+
+	accessToken, err := authenticator.Sign(jwt.NewWithClaims(signedMethod, claims))
+	if err != nil {
+		return "", apperrors.Wrap(err)
+	}
+
+	return accessToken, nil
+}
+
+// CreateCommand returns command of auth service storing access token of user until it expires
+func CreateCommand(id, userID uuid.UUID, accessToken string, expiresAt time.Time) (commandbusgrpc.RemoteCommand, error) {
+	payload, err := json.Marshal(struct {
+		ID        uuid.UUID `json:"id"`
+		UserID    uuid.UUID `json:"user_id"`
+		Access    string    `json:"access"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}{ID: id, UserID: userID, Access: accessToken, ExpiresAt: expiresAt})
+	if err != nil {
+		return commandbusgrpc.RemoteCommand{}, apperrors.Wrap(err)
+	}
+
+	return commandbusgrpc.RemoteCommand{Name: proto.CreateTokenCommandName, Payload: payload}, nil
+}
+
+// Issue signs access token of identity valid for ttl and stores it with auth service
+func Issue(ctx context.Context, signedMethod jwt.SigningMethod, authenticator auth.Authenticator, commandBus commandbus.CommandBus, i identity.Identity, ttl time.Duration) (accessToken string, expiresAt time.Time, err error) {
+	expiresAt = time.Now().Add(ttl)
+
+	accessToken, err = Sign(signedMethod, authenticator, i, expiresAt)
+	if err != nil {
+		return "", time.Time{}, apperrors.Wrap(err)
+	}
+
+	command, err := CreateCommand(uuid.New(), i.UserID, accessToken, expiresAt)
+	if err != nil {
+		return "", time.Time{}, apperrors.Wrap(err)
+	}
+
+	// auth service stores tokens only on behalf of services
+	if err := commandBus.Publish(identity.ContextWithIdentity(ctx, identity.Service()), command); err != nil {
+		return "", time.Time{}, apperrors.Wrap(err)
+	}
+
+	return accessToken, expiresAt, nil
+}
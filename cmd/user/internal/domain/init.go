@@ -2,13 +2,17 @@ package domain
 
 import (
 	"context"
-	"github.com/dgrijalva/jwt-go"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/config"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/eventhandler"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/saga"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/services"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/pkg/auth/password"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
+	"github.com/vardius/go-api-boilerplate/pkg/contract"
 	"github.com/vardius/go-api-boilerplate/pkg/domain"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
 )
 
 func RegisterUserDomain(ctx context.Context, cfg *config.Config, container *services.ServiceContainer) error {
@@ -34,19 +38,337 @@ func RegisterUserDomain(ctx context.Context, cfg *config.Config, container *serv
 		return apperrors.Wrap(err)
 	}
 
-	if err := container.CommandBus.Subscribe(ctx, user.RegisterWithEmailName, user.OnRegisterWithEmail(container.UserRepository, container.UserPersistenceRepository)); err != nil {
+	if err := domain.RegisterEventFactory(user.WasRegisteredWithPasswordType, func() interface{} { return &user.WasRegisteredWithPassword{} }); err != nil {
 		return apperrors.Wrap(err)
 	}
-	if err := container.CommandBus.Subscribe(ctx, user.RequestAccessTokenName, user.OnRequestAccessToken(container.UserRepository)); err != nil {
+	if err := domain.RegisterEventFactory(user.PasswordWasChangedType, func() interface{} { return &user.PasswordWasChanged{} }); err != nil {
 		return apperrors.Wrap(err)
 	}
-	if err := container.CommandBus.Subscribe(ctx, user.RegisterWithGoogleName, user.OnRegisterWithGoogle(container.UserRepository, container.UserPersistenceRepository)); err != nil {
+	if err := domain.RegisterEventFactory(user.LoggedInWithPasswordType, func() interface{} { return &user.LoggedInWithPassword{} }); err != nil {
 		return apperrors.Wrap(err)
 	}
-	if err := container.CommandBus.Subscribe(ctx, user.RegisterWithFacebookName, user.OnRegisterWithFacebook(container.UserRepository, container.UserPersistenceRepository)); err != nil {
+
+	if err := domain.RegisterEventFactory(user.EmailVerificationWasRequestedType, func() interface{} { return &user.EmailVerificationWasRequested{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.EmailAddressWasVerifiedType, func() interface{} { return &user.EmailAddressWasVerified{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	if err := domain.RegisterEventFactory(user.ProfileWasUpdatedType, func() interface{} { return &user.ProfileWasUpdated{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	if err := domain.RegisterEventFactory(user.AccountDeletionWasRequestedType, func() interface{} { return &user.AccountDeletionWasRequested{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.AccountDeletionWasCancelledType, func() interface{} { return &user.AccountDeletionWasCancelled{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.WasDeletedType, func() interface{} { return &user.WasDeleted{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.RoleWasGrantedType, func() interface{} { return &user.RoleWasGranted{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.RoleWasRevokedType, func() interface{} { return &user.RoleWasRevoked{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.MFAEnrolmentWasStartedType, func() interface{} { return &user.MFAEnrolmentWasStarted{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.MFAWasEnabledType, func() interface{} { return &user.MFAWasEnabled{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.MFAWasDisabledType, func() interface{} { return &user.MFAWasDisabled{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.MFAChallengeWasPassedType, func() interface{} { return &user.MFAChallengeWasPassed{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.DisconnectedFromGoogleType, func() interface{} { return &user.DisconnectedFromGoogle{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.DisconnectedFromFacebookType, func() interface{} { return &user.DisconnectedFromFacebook{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.WasRegisteredWithProviderType, func() interface{} { return &user.WasRegisteredWithProvider{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.ConnectedWithProviderType, func() interface{} { return &user.ConnectedWithProvider{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.EmailAddressChangeWasRequestedType, func() interface{} { return &user.EmailAddressChangeWasRequested{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.EmailAddressChangeWasCancelledType, func() interface{} { return &user.EmailAddressChangeWasCancelled{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.LoginCodeWasUsedType, func() interface{} { return &user.LoginCodeWasUsed{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterEventFactory(user.LoginCodesWereRevokedType, func() interface{} { return &user.LoginCodesWereRevoked{} }); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	if err := contract.RegisterEvent(user.WasRegisteredWithEmailType, user.WasRegisteredWithEmail{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.WasRegisteredWithGoogleType, user.WasRegisteredWithGoogle{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.WasRegisteredWithFacebookType, user.WasRegisteredWithFacebook{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.EmailAddressWasChangedType, user.EmailAddressWasChanged{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.AccessTokenWasRequestedType, user.AccessTokenWasRequested{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.ConnectedWithGoogleType, user.ConnectedWithGoogle{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.ConnectedWithFacebookType, user.ConnectedWithFacebook{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.WasRegisteredWithPasswordType, user.WasRegisteredWithPassword{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.PasswordWasChangedType, user.PasswordWasChanged{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.LoggedInWithPasswordType, user.LoggedInWithPassword{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.EmailVerificationWasRequestedType, user.EmailVerificationWasRequested{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.EmailAddressWasVerifiedType, user.EmailAddressWasVerified{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	if err := contract.RegisterEvent(user.ProfileWasUpdatedType, user.ProfileWasUpdated{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	if err := contract.RegisterEvent(user.AccountDeletionWasRequestedType, user.AccountDeletionWasRequested{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.AccountDeletionWasCancelledType, user.AccountDeletionWasCancelled{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.WasDeletedType, user.WasDeleted{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.RoleWasGrantedType, user.RoleWasGranted{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.RoleWasRevokedType, user.RoleWasRevoked{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.MFAEnrolmentWasStartedType, user.MFAEnrolmentWasStarted{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.MFAWasEnabledType, user.MFAWasEnabled{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.MFAWasDisabledType, user.MFAWasDisabled{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.MFAChallengeWasPassedType, user.MFAChallengeWasPassed{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.DisconnectedFromGoogleType, user.DisconnectedFromGoogle{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.DisconnectedFromFacebookType, user.DisconnectedFromFacebook{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.WasRegisteredWithProviderType, user.WasRegisteredWithProvider{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.ConnectedWithProviderType, user.ConnectedWithProvider{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.EmailAddressChangeWasRequestedType, user.EmailAddressChangeWasRequested{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.EmailAddressChangeWasCancelledType, user.EmailAddressChangeWasCancelled{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := contract.RegisterEvent(user.LoginCodeWasUsedType, user.LoginCodeWasUsed{}); err != nil {
 		return apperrors.Wrap(err)
 	}
-	if err := container.CommandBus.Subscribe(ctx, user.ChangeEmailAddressName, user.OnChangeEmailAddress(container.UserRepository, container.UserPersistenceRepository)); err != nil {
+	if err := contract.RegisterEvent(user.LoginCodesWereRevokedType, user.LoginCodesWereRevoked{}); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	verification := user.VerificationPolicy{
+		TTL:            cfg.Verification.TTL,
+		ResendInterval: cfg.Verification.ResendInterval,
+	}
+	loginCode := user.LoginCodePolicy{
+		TTL: cfg.LoginCode.TTL,
+	}
+	hasher := user.PasswordHasher{
+		Policy: password.Policy{
+			MinLength:     cfg.Password.MinLength,
+			MaxLength:     cfg.Password.MaxLength,
+			RequireUpper:  cfg.Password.RequireUpper,
+			RequireLower:  cfg.Password.RequireLower,
+			RequireDigit:  cfg.Password.RequireDigit,
+			RequireSymbol: cfg.Password.RequireSymbol,
+		},
+		Params: password.Params{
+			Memory:      cfg.Password.HashMemory,
+			Iterations:  cfg.Password.HashIterations,
+			Parallelism: cfg.Password.HashParallelism,
+			SaltLength:  password.DefaultParams.SaltLength,
+			KeyLength:   password.DefaultParams.KeyLength,
+		},
+	}
+
+	for _, d := range []registry.Definition{
+		{
+			Contract: user.RegisterUserWithEmail,
+			Command:  user.RegisterWithEmail{},
+			Handler:  user.OnRegisterWithEmail(container.UserRepository, container.UserPersistenceRepository, verification, loginCode),
+		},
+		{
+			Contract:   user.ChangeUserEmailAddress,
+			Command:    user.ChangeEmailAddress{},
+			Permission: identity.PermissionUserWrite,
+			Handler:    user.OnChangeEmailAddress(container.UserRepository, container.UserPersistenceRepository, verification),
+		},
+		{
+			Contract: user.ConfirmUserEmailAddressChange,
+			Command:  user.ConfirmEmailAddressChange{},
+			Handler:  user.OnConfirmEmailAddressChange(container.UserRepository, container.UserPersistenceRepository),
+		},
+		{
+			Contract: user.CancelUserEmailAddressChange,
+			Command:  user.CancelEmailAddressChange{},
+			Handler:  user.OnCancelEmailAddressChange(container.UserRepository),
+		},
+		{
+			Contract: user.RequestUserAccessToken,
+			Command:  user.RequestAccessToken{},
+			Handler:  user.OnRequestAccessToken(container.UserRepository, loginCode),
+		},
+		{
+			Contract: user.RegisterUserWithPassword,
+			Command:  user.RegisterWithPassword{},
+			Handler:  user.OnRegisterWithPassword(container.UserRepository, container.UserPersistenceRepository, hasher, verification),
+		},
+		{
+			Contract:   user.ChangeUserPassword,
+			Command:    user.ChangePassword{},
+			Permission: identity.PermissionUserWrite,
+			Handler:    user.OnChangePassword(container.UserRepository, hasher),
+		},
+		{
+			Contract:   user.RequestUserEmailVerification,
+			Command:    user.RequestEmailVerification{},
+			Permission: identity.PermissionUserWrite,
+			Handler:    user.OnRequestEmailVerification(container.UserRepository, verification),
+		},
+		{
+			Contract: user.VerifyUserEmailAddress,
+			Command:  user.VerifyEmailAddress{},
+			Handler:  user.OnVerifyEmailAddress(container.UserRepository),
+		},
+		{
+			Contract:   user.UpdateUserProfile,
+			Command:    user.UpdateProfile{},
+			Permission: identity.PermissionUserWrite,
+			Handler:    user.OnUpdateProfile(container.UserRepository),
+		},
+		{
+			Contract:   user.RequestUserAccountDeletion,
+			Command:    user.RequestAccountDeletion{},
+			Permission: identity.PermissionUserWrite,
+			Handler:    user.OnRequestAccountDeletion(container.UserRepository, cfg.Deletion.GracePeriod),
+		},
+		{
+			Contract:   user.CancelUserAccountDeletion,
+			Command:    user.CancelAccountDeletion{},
+			Permission: identity.PermissionUserWrite,
+			Handler:    user.OnCancelAccountDeletion(container.UserRepository),
+		},
+		{
+			Contract: user.DeleteUserAccount,
+			Command:  user.DeleteAccount{},
+			Role:     identity.RoleService, // dispatched only by scheduler once grace period passes
+			Handler:  user.OnDeleteAccount(container.UserRepository),
+		},
+		{
+			Contract: user.GrantUserRole,
+			Command:  user.GrantRole{},
+			Role:     identity.RoleAdmin | identity.RoleSuperAdmin,
+			Handler:  user.OnGrantRole(container.UserRepository),
+		},
+		{
+			Contract: user.RevokeUserRole,
+			Command:  user.RevokeRole{},
+			Role:     identity.RoleAdmin | identity.RoleSuperAdmin,
+			Handler:  user.OnRevokeRole(container.UserRepository),
+		},
+		{
+			Contract:   user.StartUserMFAEnrolment,
+			Command:    user.StartMFAEnrolment{},
+			Permission: identity.PermissionUserWrite,
+			Handler:    user.OnStartMFAEnrolment(container.UserRepository),
+		},
+		{
+			Contract:   user.EnableUserMFA,
+			Command:    user.EnableMFA{},
+			Permission: identity.PermissionUserWrite,
+			Handler:    user.OnEnableMFA(container.UserRepository),
+		},
+		{
+			Contract:   user.DisableUserMFA,
+			Command:    user.DisableMFA{},
+			Permission: identity.PermissionUserWrite,
+			Handler:    user.OnDisableMFA(container.UserRepository),
+		},
+		{
+			Contract:   user.DisconnectUserGoogle,
+			Command:    user.DisconnectGoogle{},
+			Permission: identity.PermissionUserWrite,
+			Handler:    user.OnDisconnectGoogle(container.UserRepository),
+		},
+		{
+			Contract:   user.DisconnectUserFacebook,
+			Command:    user.DisconnectFacebook{},
+			Permission: identity.PermissionUserWrite,
+			Handler:    user.OnDisconnectFacebook(container.UserRepository),
+		},
+	} {
+		if err := container.CommandRegistry.Register(ctx, d); err != nil {
+			return apperrors.Wrap(err)
+		}
+	}
+
+	// social register commands carry identity provider profile, they are dispatched only by auth callback handlers
+	// and are not registered with command registry so they can not be dispatched with caller supplied payload
+	if err := container.CommandBus.Subscribe(ctx, user.RegisterWithGoogleName, user.OnRegisterWithGoogle(container.UserRepository, container.UserPersistenceRepository, loginCode)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.CommandBus.Subscribe(ctx, user.RegisterWithFacebookName, user.OnRegisterWithFacebook(container.UserRepository, container.UserPersistenceRepository, loginCode)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.CommandBus.Subscribe(ctx, user.RegisterWithProviderName, user.OnRegisterWithProvider(container.UserRepository, container.UserPersistenceRepository, verification, loginCode)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	// password login, login code and multi-factor authentication challenge are dispatched only by login endpoints which are rate limited and issue tokens
+	if err := container.CommandBus.Subscribe(ctx, user.LoginWithPasswordName, user.OnLoginWithPassword(container.UserRepository, container.UserPersistenceRepository)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.CommandBus.Subscribe(ctx, user.PassMFAChallengeName, user.OnPassMFAChallenge(container.UserRepository)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.CommandBus.Subscribe(ctx, user.UseLoginCodeName, user.OnUseLoginCode(container.UserRepository)); err != nil {
 		return apperrors.Wrap(err)
 	}
 
@@ -62,15 +384,63 @@ func RegisterUserDomain(ctx context.Context, cfg *config.Config, container *serv
 	if err := container.EventBus.Subscribe(ctx, user.EmailAddressWasChangedType, eventhandler.WhenUserEmailAddressWasChanged(container.UserPersistenceRepository)); err != nil {
 		return apperrors.Wrap(err)
 	}
-	if err := container.EventBus.Subscribe(ctx, user.AccessTokenWasRequestedType, eventhandler.WhenUserAccessTokenWasRequested(cfg, jwt.SigningMethodHS512, container.Authenticator, container.UserPersistenceRepository, container.AuthClient)); err != nil {
-		return apperrors.Wrap(err)
-	}
 	if err := container.EventBus.Subscribe(ctx, user.ConnectedWithGoogleType, eventhandler.WhenUserConnectedWithGoogle(container.UserPersistenceRepository, container.CommandBus)); err != nil {
 		return apperrors.Wrap(err)
 	}
 	if err := container.EventBus.Subscribe(ctx, user.ConnectedWithFacebookType, eventhandler.WhenUserConnectedWithFacebook(container.UserPersistenceRepository, container.CommandBus)); err != nil {
 		return apperrors.Wrap(err)
 	}
+	if err := container.EventBus.Subscribe(ctx, user.WasRegisteredWithPasswordType, eventhandler.WhenUserWasRegisteredWithPassword(container.UserPersistenceRepository)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.EventBus.Subscribe(ctx, user.EmailVerificationWasRequestedType, eventhandler.WhenUserEmailVerificationWasRequested(cfg)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.EventBus.Subscribe(ctx, user.EmailAddressChangeWasRequestedType, eventhandler.WhenUserEmailAddressChangeWasRequested(cfg)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.EventBus.Subscribe(ctx, user.EmailAddressWasVerifiedType, eventhandler.WhenUserEmailAddressWasVerified(container.UserPersistenceRepository)); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	if err := container.EventBus.Subscribe(ctx, user.ProfileWasUpdatedType, eventhandler.WhenUserProfileWasUpdated(container.UserPersistenceRepository)); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	if err := container.EventBus.Subscribe(ctx, user.AccountDeletionWasRequestedType, eventhandler.WhenUserAccountDeletionWasRequested(container.CommandScheduler)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.EventBus.Subscribe(ctx, user.WasDeletedType, eventhandler.WhenUserWasDeleted(container.UserPersistenceRepository, container.EventStore, container.IntegrationEventBus)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.EventBus.Subscribe(ctx, user.RoleWasGrantedType, eventhandler.WhenUserRoleWasGranted(container.UserPersistenceRepository)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.EventBus.Subscribe(ctx, user.RoleWasRevokedType, eventhandler.WhenUserRoleWasRevoked(container.UserPersistenceRepository, container.IntegrationEventBus)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.EventBus.Subscribe(ctx, user.MFAWasEnabledType, eventhandler.WhenUserMFAWasEnabled(container.UserPersistenceRepository)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.EventBus.Subscribe(ctx, user.MFAWasDisabledType, eventhandler.WhenUserMFAWasDisabled(container.UserPersistenceRepository)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.EventBus.Subscribe(ctx, user.DisconnectedFromGoogleType, eventhandler.WhenUserDisconnectedFromGoogle(container.UserPersistenceRepository)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.EventBus.Subscribe(ctx, user.DisconnectedFromFacebookType, eventhandler.WhenUserDisconnectedFromFacebook(container.UserPersistenceRepository)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.EventBus.Subscribe(ctx, user.WasRegisteredWithProviderType, eventhandler.WhenUserWasRegisteredWithProvider(container.UserPersistenceRepository, container.CommandBus)); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := container.EventBus.Subscribe(ctx, user.ConnectedWithProviderType, eventhandler.WhenUserConnectedWithProvider(container.UserPersistenceRepository, container.CommandBus)); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	if err := container.SagaManager.Register(ctx, saga.Login(cfg)); err != nil {
+		return apperrors.Wrap(err)
+	}
 
 	return nil
 }
@@ -2,17 +2,20 @@ package user
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/text/language"
 
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/access"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
 	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
 	"github.com/vardius/go-api-boilerplate/pkg/domain"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	"github.com/vardius/go-api-boilerplate/pkg/executioncontext"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
 )
 
 const (
@@ -22,10 +25,40 @@ const (
 	RequestUserAccessToken = "user-request-access-token"
 	// RegisterUserWithEmail command bus contract
 	RegisterUserWithEmail = "user-register-with-email"
-	// RegisterUserWithFacebook command bus contract
-	RegisterUserWithFacebook = "user-register-with-facebook"
-	// RegisterUserWithGoogle command bus contract
-	RegisterUserWithGoogle = "user-register-with-google"
+	// RegisterUserWithPassword command bus contract
+	RegisterUserWithPassword = "user-register-with-password"
+	// ChangeUserPassword command bus contract
+	ChangeUserPassword = "user-change-password"
+	// RequestUserEmailVerification command bus contract
+	RequestUserEmailVerification = "user-request-email-verification"
+	// VerifyUserEmailAddress command bus contract
+	VerifyUserEmailAddress = "user-verify-email-address"
+	// UpdateUserProfile command bus contract
+	UpdateUserProfile = "user-update-profile"
+	// RequestUserAccountDeletion command bus contract
+	RequestUserAccountDeletion = "user-request-account-deletion"
+	// CancelUserAccountDeletion command bus contract
+	CancelUserAccountDeletion = "user-cancel-account-deletion"
+	// DeleteUserAccount command bus contract
+	DeleteUserAccount = "user-delete-account"
+	// GrantUserRole command bus contract
+	GrantUserRole = "user-grant-role"
+	// RevokeUserRole command bus contract
+	RevokeUserRole = "user-revoke-role"
+	// StartUserMFAEnrolment command bus contract
+	StartUserMFAEnrolment = "user-start-mfa-enrolment"
+	// EnableUserMFA command bus contract
+	EnableUserMFA = "user-enable-mfa"
+	// DisableUserMFA command bus contract
+	DisableUserMFA = "user-disable-mfa"
+	// DisconnectUserGoogle command bus contract
+	DisconnectUserGoogle = "user-disconnect-google"
+	// DisconnectUserFacebook command bus contract
+	DisconnectUserFacebook = "user-disconnect-facebook"
+	// ConfirmUserEmailAddressChange command bus contract
+	ConfirmUserEmailAddressChange = "user-confirm-email-address-change"
+	// CancelUserEmailAddressChange command bus contract
+	CancelUserEmailAddressChange = "user-cancel-email-address-change"
 )
 
 var (
@@ -34,74 +67,151 @@ var (
 	RegisterWithGoogleName   = (RegisterWithGoogle{}).GetName()
 	RegisterWithFacebookName = (RegisterWithFacebook{}).GetName()
 	ChangeEmailAddressName   = (ChangeEmailAddress{}).GetName()
+	RegisterWithPasswordName = (RegisterWithPassword{}).GetName()
+	ChangePasswordName       = (ChangePassword{}).GetName()
+	LoginWithPasswordName    = (LoginWithPassword{}).GetName()
+
+	RequestEmailVerificationName = (RequestEmailVerification{}).GetName()
+	VerifyEmailAddressName       = (VerifyEmailAddress{}).GetName()
+	UpdateProfileName            = (UpdateProfile{}).GetName()
+
+	RequestAccountDeletionName = (RequestAccountDeletion{}).GetName()
+	CancelAccountDeletionName  = (CancelAccountDeletion{}).GetName()
+	DeleteAccountName          = (DeleteAccount{}).GetName()
+
+	GrantRoleName  = (GrantRole{}).GetName()
+	RevokeRoleName = (RevokeRole{}).GetName()
+
+	StartMFAEnrolmentName = (StartMFAEnrolment{}).GetName()
+	EnableMFAName         = (EnableMFA{}).GetName()
+	DisableMFAName        = (DisableMFA{}).GetName()
+	PassMFAChallengeName  = (PassMFAChallenge{}).GetName()
+
+	DisconnectGoogleName   = (DisconnectGoogle{}).GetName()
+	DisconnectFacebookName = (DisconnectFacebook{}).GetName()
+
+	RegisterWithProviderName = (RegisterWithProvider{}).GetName()
+
+	ConfirmEmailAddressChangeName = (ConfirmEmailAddressChange{}).GetName()
+	CancelEmailAddressChangeName  = (CancelEmailAddressChange{}).GetName()
+
+	UseLoginCodeName = (UseLoginCode{}).GetName()
 )
 
-// NewCommandFromPayload builds command by contract from json payload
-func NewCommandFromPayload(contract string, payload []byte) (domain.Command, error) {
-	switch contract {
-	case RegisterUserWithEmail:
-		var command RegisterWithEmail
-		if err := json.Unmarshal(payload, &command); err != nil {
-			return command, apperrors.Wrap(err)
+// ChangeEmailAddress command requests email address change, it takes effect once confirmed with token sent to new email address
+type ChangeEmailAddress struct {
+	ID    uuid.UUID    `json:"id" validate:"required"`
+	Email EmailAddress `json:"email" validate:"required,email"`
+}
+
+// GetName returns command name
+func (c ChangeEmailAddress) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnChangeEmailAddress creates command handler
+func OnChangeEmailAddress(repository Repository, userRepository persistence.UserRepository, verification VerificationPolicy) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(ChangeEmailAddress)
+		if !ok {
+			return apperrors.New("invalid command")
 		}
 
-		return command, nil
-	case RegisterUserWithGoogle:
-		var command RegisterWithGoogle
-		if err := json.Unmarshal(payload, &command); err != nil {
-			return command, apperrors.Wrap(err)
+		if err := authorizeAccountOwner(ctx, c.ID); err != nil {
+			return apperrors.Wrap(err)
+		}
+		if err := checkEmailAddressAvailable(ctx, userRepository, c.Email); err != nil {
+			return apperrors.Wrap(err)
 		}
 
-		return command, nil
-	case RegisterUserWithFacebook:
-		var command RegisterWithFacebook
-		if err := json.Unmarshal(payload, &command); err != nil {
-			return command, apperrors.Wrap(err)
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
 		}
 
-		return command, nil
-	case ChangeUserEmailAddress:
-		var command ChangeEmailAddress
-		if err := json.Unmarshal(payload, &command); err != nil {
-			return command, apperrors.Wrap(err)
+		if err := u.RequestEmailAddressChange(ctx, c.Email, verification, time.Now()); err != nil {
+			return apperrors.Wrap(err)
 		}
 
-		return command, nil
-	case RequestUserAccessToken:
-		var command RequestAccessToken
-		if err := json.Unmarshal(payload, &command); err != nil {
-			return command, apperrors.Wrap(err)
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
 		}
 
-		return command, nil
-	default:
-		return nil, apperrors.Wrap(fmt.Errorf("invalid command contract: %s", contract))
+		return nil
 	}
+
+	return fn
 }
 
-// ChangeEmailAddress command
-type ChangeEmailAddress struct {
-	ID    uuid.UUID    `json:"id"`
-	Email EmailAddress `json:"email"`
+// ConfirmEmailAddressChange command
+type ConfirmEmailAddressChange struct {
+	ID    uuid.UUID `json:"id" validate:"required"`
+	Token string    `json:"token" validate:"required"`
 }
 
 // GetName returns command name
-func (c ChangeEmailAddress) GetName() string {
+func (c ConfirmEmailAddressChange) GetName() string {
 	return fmt.Sprintf("%T", c)
 }
 
-// OnChangeEmailAddress creates command handler
-func OnChangeEmailAddress(repository Repository, userRepository persistence.UserRepository) commandbus.CommandHandler {
+// OnConfirmEmailAddressChange creates command handler
+func OnConfirmEmailAddressChange(repository Repository, userRepository persistence.UserRepository) commandbus.CommandHandler {
 	fn := func(ctx context.Context, command domain.Command) error {
-		c, ok := command.(ChangeEmailAddress)
+		c, ok := command.(ConfirmEmailAddressChange)
 		if !ok {
 			return apperrors.New("invalid command")
 		}
 
-		if _, err := userRepository.GetByEmail(ctx, c.Email.String()); err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
 			return apperrors.Wrap(err)
-		} else if err == nil {
-			return apperrors.Wrap(fmt.Errorf("%w: user with this email address is already registered", apperrors.ErrInvalid))
+		}
+
+		// email address could have been registered by someone else since change was requested
+		if u.emailChange.email != "" {
+			if err := checkEmailAddressAvailable(ctx, userRepository, u.emailChange.email); err != nil {
+				return apperrors.Wrap(err)
+			}
+		}
+
+		if err := u.ConfirmEmailAddressChange(ctx, c.Token, time.Now()); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// CancelEmailAddressChange command, token is the cancellation token sent to current email address
+// and is required unless command is dispatched by account owner
+type CancelEmailAddressChange struct {
+	ID    uuid.UUID `json:"id" validate:"required"`
+	Token string    `json:"token,omitempty"`
+}
+
+// GetName returns command name
+func (c CancelEmailAddressChange) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnCancelEmailAddressChange creates command handler
+func OnCancelEmailAddressChange(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(CancelEmailAddressChange)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if c.Token == "" {
+			if err := authorizeAccountOwner(ctx, c.ID); err != nil {
+				return apperrors.Wrap(err)
+			}
 		}
 
 		u, err := repository.Get(ctx, c.ID)
@@ -109,7 +219,7 @@ func OnChangeEmailAddress(repository Repository, userRepository persistence.User
 			return apperrors.Wrap(err)
 		}
 
-		if err := u.ChangeEmailAddress(ctx, c.Email); err != nil {
+		if err := u.CancelEmailAddressChange(ctx, c.Token); err != nil {
 			return apperrors.Wrap(err)
 		}
 
@@ -125,8 +235,8 @@ func OnChangeEmailAddress(repository Repository, userRepository persistence.User
 
 // RequestAccessToken command
 type RequestAccessToken struct {
-	ID           uuid.UUID `json:"id"`
-	RedirectPath string    `json:"redirect_path,omitempty"`
+	ID           uuid.UUID `json:"id" validate:"required"`
+	RedirectPath string    `json:"redirect_path,omitempty" validate:"requri"`
 }
 
 // GetName returns command name
@@ -135,7 +245,7 @@ func (c RequestAccessToken) GetName() string {
 }
 
 // OnRequestAccessToken creates command handler
-func OnRequestAccessToken(repository Repository) commandbus.CommandHandler {
+func OnRequestAccessToken(repository Repository, loginCode LoginCodePolicy) commandbus.CommandHandler {
 	fn := func(ctx context.Context, command domain.Command) error {
 		c, ok := command.(RequestAccessToken)
 		if !ok {
@@ -147,13 +257,58 @@ func OnRequestAccessToken(repository Repository) commandbus.CommandHandler {
 			return apperrors.Wrap(err)
 		}
 
-		if err := u.RequestAccessToken(ctx, c.RedirectPath); err != nil {
+		if err := u.RequestAccessToken(ctx, c.RedirectPath, loginCode, time.Now()); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// UseLoginCode command uses up login code sent by email, OTP is one-time password or recovery code
+// required from users with multi-factor authentication enabled
+type UseLoginCode struct {
+	ID   uuid.UUID `json:"id" validate:"required"`
+	Code string    `json:"code" validate:"required"`
+	OTP  string    `json:"otp,omitempty"`
+}
+
+// GetName returns command name
+func (c UseLoginCode) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnUseLoginCode creates command handler
+func OnUseLoginCode(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(UseLoginCode)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
 			return apperrors.Wrap(err)
 		}
 
+		useErr := u.UseLoginCode(ctx, c.Code, c.OTP, time.Now())
+		if useErr != nil && !errors.Is(useErr, ErrLoginCodeReused) {
+			return apperrors.Wrap(useErr)
+		}
+
+		// revocation of outstanding login codes is saved before reuse is reported
 		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
 			return apperrors.Wrap(err)
 		}
+		if useErr != nil {
+			return apperrors.Wrap(useErr)
+		}
 
 		return nil
 	}
@@ -163,8 +318,8 @@ func OnRequestAccessToken(repository Repository) commandbus.CommandHandler {
 
 // RegisterWithEmail command
 type RegisterWithEmail struct {
-	Email        EmailAddress `json:"email"`
-	RedirectPath string       `json:"redirect_path,omitempty"`
+	Email        EmailAddress `json:"email" validate:"required,email"`
+	RedirectPath string       `json:"redirect_path,omitempty" validate:"requri"`
 }
 
 // GetName returns command name
@@ -173,7 +328,7 @@ func (c RegisterWithEmail) GetName() string {
 }
 
 // OnRegisterWithEmail creates command handler
-func OnRegisterWithEmail(repository Repository, userRepository persistence.UserRepository) commandbus.CommandHandler {
+func OnRegisterWithEmail(repository Repository, userRepository persistence.UserRepository, verification VerificationPolicy, loginCode LoginCodePolicy) commandbus.CommandHandler {
 	fn := func(ctx context.Context, command domain.Command) error {
 		c, ok := command.(RegisterWithEmail)
 		if !ok {
@@ -197,7 +352,7 @@ func OnRegisterWithEmail(repository Repository, userRepository persistence.UserR
 				return apperrors.Wrap(err)
 			}
 
-			if err := u.RequestAccessToken(ctx, c.RedirectPath); err != nil {
+			if err := u.RequestAccessToken(ctx, c.RedirectPath, loginCode, time.Now()); err != nil {
 				return apperrors.Wrap(err)
 			}
 		} else {
@@ -210,6 +365,9 @@ func OnRegisterWithEmail(repository Repository, userRepository persistence.UserR
 			if err := u.RegisterWithEmail(ctx, id, c.Email); err != nil {
 				return apperrors.Wrap(err)
 			}
+			if err := u.RequestEmailVerification(ctx, verification, time.Now()); err != nil {
+				return apperrors.Wrap(err)
+			}
 		}
 
 		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
@@ -224,10 +382,13 @@ func OnRegisterWithEmail(repository Repository, userRepository persistence.UserR
 
 // RegisterWithFacebook command
 type RegisterWithFacebook struct {
-	Email        EmailAddress `json:"email"`
-	FacebookID   string       `json:"facebook_id"`
-	AccessToken  string       `json:"access_token"`
-	RedirectPath string       `json:"redirect_path,omitempty"`
+	Email        EmailAddress `json:"email" validate:"required,email"`
+	FacebookID   string       `json:"facebook_id" validate:"required"`
+	AccessToken  string       `json:"access_token" validate:"required"`
+	RedirectPath string       `json:"redirect_path,omitempty" validate:"requri"`
+	Name         string       `json:"name,omitempty"`
+	AvatarURL    string       `json:"avatar_url,omitempty" validate:"requrl"`
+	Locale       string       `json:"locale,omitempty"`
 }
 
 // GetName returns command name
@@ -236,7 +397,7 @@ func (c RegisterWithFacebook) GetName() string {
 }
 
 // OnRegisterWithFacebook creates command handler
-func OnRegisterWithFacebook(repository Repository, userRepository persistence.UserRepository) commandbus.CommandHandler {
+func OnRegisterWithFacebook(repository Repository, userRepository persistence.UserRepository, loginCode LoginCodePolicy) commandbus.CommandHandler {
 	fn := func(ctx context.Context, command domain.Command) error {
 		c, ok := command.(RegisterWithFacebook)
 		if !ok {
@@ -261,7 +422,7 @@ func OnRegisterWithFacebook(repository Repository, userRepository persistence.Us
 				return apperrors.Wrap(err)
 			}
 
-			if err := user.RequestAccessToken(ctx, c.RedirectPath); err != nil {
+			if err := user.RequestAccessToken(ctx, c.RedirectPath, loginCode, time.Now()); err != nil {
 				return apperrors.Wrap(err)
 			}
 		} else {
@@ -292,7 +453,7 @@ func OnRegisterWithFacebook(repository Repository, userRepository persistence.Us
 				}
 
 				user = New()
-				if err := user.RegisterWithFacebook(ctx, id, c.Email, c.FacebookID, c.AccessToken, c.RedirectPath); err != nil {
+				if err := user.RegisterWithFacebook(ctx, id, c.Email, c.FacebookID, c.AccessToken, c.RedirectPath, socialProfile(c.Name, c.AvatarURL, c.Locale)); err != nil {
 					return apperrors.Wrap(err)
 				}
 			}
@@ -310,10 +471,13 @@ func OnRegisterWithFacebook(repository Repository, userRepository persistence.Us
 
 // RegisterWithGoogle command
 type RegisterWithGoogle struct {
-	Email        EmailAddress `json:"email"`
-	GoogleID     string       `json:"google_id"`
-	AccessToken  string       `json:"access_token"`
-	RedirectPath string       `json:"redirect_path,omitempty"`
+	Email        EmailAddress `json:"email" validate:"required,email"`
+	GoogleID     string       `json:"google_id" validate:"required"`
+	AccessToken  string       `json:"access_token" validate:"required"`
+	RedirectPath string       `json:"redirect_path,omitempty" validate:"requri"`
+	Name         string       `json:"name,omitempty"`
+	AvatarURL    string       `json:"avatar_url,omitempty" validate:"requrl"`
+	Locale       string       `json:"locale,omitempty"`
 }
 
 // GetName returns command name
@@ -322,7 +486,7 @@ func (c RegisterWithGoogle) GetName() string {
 }
 
 // OnRegisterWithGoogle creates command handler
-func OnRegisterWithGoogle(repository Repository, userRepository persistence.UserRepository) commandbus.CommandHandler {
+func OnRegisterWithGoogle(repository Repository, userRepository persistence.UserRepository, loginCode LoginCodePolicy) commandbus.CommandHandler {
 	fn := func(ctx context.Context, command domain.Command) error {
 		c, ok := command.(RegisterWithGoogle)
 		if !ok {
@@ -347,7 +511,7 @@ func OnRegisterWithGoogle(repository Repository, userRepository persistence.User
 				return apperrors.Wrap(err)
 			}
 
-			if err := user.RequestAccessToken(ctx, c.RedirectPath); err != nil {
+			if err := user.RequestAccessToken(ctx, c.RedirectPath, loginCode, time.Now()); err != nil {
 				return apperrors.Wrap(err)
 			}
 		} else {
@@ -378,9 +542,103 @@ func OnRegisterWithGoogle(repository Repository, userRepository persistence.User
 				}
 
 				user = New()
-				if err := user.RegisterWithGoogle(ctx, id, c.Email, c.GoogleID, c.AccessToken, c.RedirectPath); err != nil {
+				if err := user.RegisterWithGoogle(ctx, id, c.Email, c.GoogleID, c.AccessToken, c.RedirectPath, socialProfile(c.Name, c.AvatarURL, c.Locale)); err != nil {
+					return apperrors.Wrap(err)
+				}
+			}
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), user); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// RegisterWithProvider command, subject identifies user within identity provider
+type RegisterWithProvider struct {
+	Provider      string       `json:"provider" validate:"required"`
+	Subject       string       `json:"subject" validate:"required"`
+	Email         EmailAddress `json:"email" validate:"required,email"`
+	EmailVerified bool         `json:"email_verified,omitempty"`
+	AccessToken   string       `json:"access_token" validate:"required"`
+	RedirectPath  string       `json:"redirect_path,omitempty" validate:"requri"`
+	Name          string       `json:"name,omitempty"`
+	AvatarURL     string       `json:"avatar_url,omitempty" validate:"requrl"`
+	Locale        string       `json:"locale,omitempty"`
+}
+
+// GetName returns command name
+func (c RegisterWithProvider) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnRegisterWithProvider creates command handler,
+// users connected with provider account request access token, provider account is connected to user registered with the same email address
+// only if provider verified it, otherwise new user is registered
+func OnRegisterWithProvider(repository Repository, userRepository persistence.UserRepository, verification VerificationPolicy, loginCode LoginCodePolicy) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(RegisterWithProvider)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		var user User
+		if u, err := userRepository.GetByProvider(ctx, c.Provider, c.Subject); err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			return apperrors.Wrap(err)
+		} else if err == nil {
+			id, err := uuid.Parse(u.GetID())
+			if err != nil {
+				return apperrors.Wrap(err)
+			}
+
+			user, err = repository.Get(ctx, id)
+			if err != nil {
+				return apperrors.Wrap(err)
+			}
+
+			if err := user.RequestAccessToken(ctx, c.RedirectPath, loginCode, time.Now()); err != nil {
+				return apperrors.Wrap(err)
+			}
+		} else {
+			if u, err := userRepository.GetByEmail(ctx, c.Email.String()); err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+				return apperrors.Wrap(err)
+			} else if err == nil {
+				if !c.EmailVerified {
+					return apperrors.Wrap(fmt.Errorf("%w: email address was not verified by %s", apperrors.ErrInvalid, c.Provider))
+				}
+
+				userID, err := uuid.Parse(u.GetID())
+				if err != nil {
+					return apperrors.Wrap(err)
+				}
+
+				user, err = repository.Get(ctx, userID)
+				if err != nil {
+					return apperrors.Wrap(err)
+				}
+
+				if err := user.ConnectWithProvider(ctx, c.Provider, c.Subject, c.AccessToken, c.RedirectPath); err != nil {
+					return apperrors.Wrap(err)
+				}
+			} else {
+				id, err := uuid.NewRandom()
+				if err != nil {
+					return apperrors.Wrap(err)
+				}
+
+				user = New()
+				if err := user.RegisterWithProvider(ctx, id, c.Email, c.EmailVerified, c.Provider, c.Subject, c.AccessToken, c.RedirectPath, socialProfile(c.Name, c.AvatarURL, c.Locale)); err != nil {
 					return apperrors.Wrap(err)
 				}
+				if !c.EmailVerified {
+					if err := user.RequestEmailVerification(ctx, verification, time.Now()); err != nil {
+						return apperrors.Wrap(err)
+					}
+				}
 			}
 		}
 
@@ -393,3 +651,806 @@ func OnRegisterWithGoogle(repository Repository, userRepository persistence.User
 
 	return fn
 }
+
+// RegisterWithPassword command
+type RegisterWithPassword struct {
+	Email    EmailAddress `json:"email" validate:"required,email"`
+	Password Password     `json:"password" validate:"required"`
+}
+
+// GetName returns command name
+func (c RegisterWithPassword) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnRegisterWithPassword creates command handler
+func OnRegisterWithPassword(repository Repository, userRepository persistence.UserRepository, hasher PasswordHasher, verification VerificationPolicy) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(RegisterWithPassword)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if _, err := userRepository.GetByEmail(ctx, c.Email.String()); err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			return apperrors.Wrap(err)
+		} else if err == nil {
+			return apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrConflict, ErrAlreadyRegistered))
+		}
+
+		hash, err := hasher.Hash(c.Password)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u := New()
+		if err := u.RegisterWithPassword(ctx, id, c.Email, hash); err != nil {
+			return apperrors.Wrap(err)
+		}
+		if err := u.RequestEmailVerification(ctx, verification, time.Now()); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// ChangePassword command
+type ChangePassword struct {
+	ID              uuid.UUID `json:"id" validate:"required"`
+	CurrentPassword Password  `json:"current_password,omitempty"`
+	NewPassword     Password  `json:"new_password" validate:"required"`
+}
+
+// GetName returns command name
+func (c ChangePassword) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnChangePassword creates command handler
+func OnChangePassword(repository Repository, hasher PasswordHasher) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(ChangePassword)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if err := authorizeAccountOwner(ctx, c.ID); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		hash, err := hasher.Hash(c.NewPassword)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.ChangePassword(ctx, c.CurrentPassword, hash); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// LoginWithPassword command
+type LoginWithPassword struct {
+	Email    EmailAddress `json:"email" validate:"required,email"`
+	Password Password     `json:"password" validate:"required"`
+}
+
+// GetName returns command name
+func (c LoginWithPassword) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnLoginWithPassword creates command handler,
+// it fails with apperrors.ErrUnauthorized when email is unknown or password does not match
+// and with ErrMFARequired when password matches but multi-factor authentication is enabled
+func OnLoginWithPassword(repository Repository, userRepository persistence.UserRepository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(LoginWithPassword)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		user, err := userRepository.GetByEmail(ctx, c.Email.String())
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrUnauthorized, ErrInvalidCredentials))
+		} else if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		id, err := uuid.Parse(user.GetID())
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.Get(ctx, id)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.LoginWithPassword(ctx, c.Password); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// RequestEmailVerification command
+type RequestEmailVerification struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+// GetName returns command name
+func (c RequestEmailVerification) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnRequestEmailVerification creates command handler
+func OnRequestEmailVerification(repository Repository, verification VerificationPolicy) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(RequestEmailVerification)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if i, hasIdentity := identity.FromContext(ctx); hasIdentity && i.UserID != c.ID {
+			return apperrors.Wrap(fmt.Errorf("%w: verification can be requested only by account owner", apperrors.ErrForbidden))
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.RequestEmailVerification(ctx, verification, time.Now()); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// VerifyEmailAddress command
+type VerifyEmailAddress struct {
+	ID    uuid.UUID `json:"id" validate:"required"`
+	Token string    `json:"token" validate:"required"`
+}
+
+// GetName returns command name
+func (c VerifyEmailAddress) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnVerifyEmailAddress creates command handler
+func OnVerifyEmailAddress(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(VerifyEmailAddress)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.VerifyEmailAddress(ctx, c.Token, time.Now()); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// UpdateProfile command, replaces whole profile so omitted fields are cleared
+type UpdateProfile struct {
+	ID        uuid.UUID `json:"id" validate:"required"`
+	Name      string    `json:"name,omitempty" validate:"length(1|255)"`
+	AvatarURL string    `json:"avatar_url,omitempty" validate:"requrl,length(1|2048)"`
+	Locale    string    `json:"locale,omitempty" validate:"locale"`
+	Timezone  string    `json:"timezone,omitempty" validate:"timezone"`
+}
+
+// GetName returns command name
+func (c UpdateProfile) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnUpdateProfile creates command handler
+func OnUpdateProfile(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(UpdateProfile)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if err := authorizeAccountOwner(ctx, c.ID); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		profile := Profile{
+			Name:      c.Name,
+			AvatarURL: c.AvatarURL,
+			Timezone:  c.Timezone,
+		}
+		if c.Locale != "" {
+			profile.Locale = language.Make(c.Locale).String()
+		}
+
+		if err := u.UpdateProfile(ctx, profile); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// RequestAccountDeletion command
+type RequestAccountDeletion struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+// GetName returns command name
+func (c RequestAccountDeletion) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnRequestAccountDeletion creates command handler,
+// account is deleted by DeleteAccount command scheduled once grace period passes
+func OnRequestAccountDeletion(repository Repository, gracePeriod time.Duration) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(RequestAccountDeletion)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if err := authorizeAccountOwner(ctx, c.ID); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.RequestDeletion(ctx, gracePeriod, time.Now()); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// CancelAccountDeletion command
+type CancelAccountDeletion struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+// GetName returns command name
+func (c CancelAccountDeletion) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnCancelAccountDeletion creates command handler
+func OnCancelAccountDeletion(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(CancelAccountDeletion)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if err := authorizeAccountOwner(ctx, c.ID); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.CancelDeletion(ctx); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// DeleteAccount command
+type DeleteAccount struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+// GetName returns command name
+func (c DeleteAccount) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnDeleteAccount creates command handler,
+// it is dispatched by scheduler and fails with apperrors.ErrConflict until grace period passes
+func OnDeleteAccount(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(DeleteAccount)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.Delete(ctx, time.Now()); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// GrantRole command
+type GrantRole struct {
+	ID   uuid.UUID `json:"id" validate:"required"`
+	Role string    `json:"role" validate:"required,in(ADMIN|SUPER_ADMIN)"`
+}
+
+// GetName returns command name
+func (c GrantRole) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnGrantRole creates command handler
+func OnGrantRole(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(GrantRole)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		role, err := authorizeRoleChange(ctx, c.ID, c.Role)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.GrantRole(ctx, role); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// RevokeRole command
+type RevokeRole struct {
+	ID   uuid.UUID `json:"id" validate:"required"`
+	Role string    `json:"role" validate:"required,in(ADMIN|SUPER_ADMIN)"`
+}
+
+// GetName returns command name
+func (c RevokeRole) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnRevokeRole creates command handler
+func OnRevokeRole(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(RevokeRole)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		role, err := authorizeRoleChange(ctx, c.ID, c.Role)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.RevokeRole(ctx, role); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// StartMFAEnrolment command, secret is generated by caller so it can be shown to user
+type StartMFAEnrolment struct {
+	ID     uuid.UUID `json:"id" validate:"required"`
+	Secret string    `json:"secret" validate:"required,length(16|128)"`
+}
+
+// GetName returns command name
+func (c StartMFAEnrolment) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnStartMFAEnrolment creates command handler
+func OnStartMFAEnrolment(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(StartMFAEnrolment)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if err := authorizeAccountOwner(ctx, c.ID); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.StartMFAEnrolment(ctx, c.Secret); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// EnableMFA command, recovery codes are generated by caller so they can be shown to user
+type EnableMFA struct {
+	ID            uuid.UUID `json:"id" validate:"required"`
+	Code          string    `json:"code" validate:"required,length(6|6)"`
+	RecoveryCodes []string  `json:"recovery_codes" validate:"required,length(10|11)"`
+}
+
+// GetName returns command name
+func (c EnableMFA) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnEnableMFA creates command handler
+func OnEnableMFA(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(EnableMFA)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if err := authorizeAccountOwner(ctx, c.ID); err != nil {
+			return apperrors.Wrap(err)
+		}
+		if len(c.RecoveryCodes) != RecoveryCodesCount {
+			return apperrors.Wrap(fmt.Errorf("%w: expected %d recovery codes", apperrors.ErrInvalid, RecoveryCodesCount))
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.EnableMFA(ctx, c.Code, c.RecoveryCodes, time.Now()); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// DisableMFA command, code is either one-time password or recovery code
+type DisableMFA struct {
+	ID   uuid.UUID `json:"id" validate:"required"`
+	Code string    `json:"code" validate:"required,length(6|12)"`
+}
+
+// GetName returns command name
+func (c DisableMFA) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnDisableMFA creates command handler
+func OnDisableMFA(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(DisableMFA)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if err := authorizeAccountOwner(ctx, c.ID); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.DisableMFA(ctx, c.Code, time.Now()); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// PassMFAChallenge command, code is either one-time password or recovery code
+type PassMFAChallenge struct {
+	ID   uuid.UUID `json:"id" validate:"required"`
+	Code string    `json:"code" validate:"required,length(6|12)"`
+}
+
+// GetName returns command name
+func (c PassMFAChallenge) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnPassMFAChallenge creates command handler,
+// it is dispatched with challenge token issued to users with multi-factor authentication enabled
+func OnPassMFAChallenge(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(PassMFAChallenge)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if err := authorizeAccountOwner(ctx, c.ID); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.PassMFAChallenge(ctx, c.Code, time.Now()); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// DisconnectGoogle command
+type DisconnectGoogle struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+// GetName returns command name
+func (c DisconnectGoogle) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnDisconnectGoogle creates command handler
+func OnDisconnectGoogle(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(DisconnectGoogle)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if err := authorizeAccountOwner(ctx, c.ID); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.DisconnectGoogle(ctx); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// DisconnectFacebook command
+type DisconnectFacebook struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+// GetName returns command name
+func (c DisconnectFacebook) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// OnDisconnectFacebook creates command handler
+func OnDisconnectFacebook(repository Repository) commandbus.CommandHandler {
+	fn := func(ctx context.Context, command domain.Command) error {
+		c, ok := command.(DisconnectFacebook)
+		if !ok {
+			return apperrors.New("invalid command")
+		}
+
+		if err := authorizeAccountOwner(ctx, c.ID); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.Get(ctx, c.ID)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := u.DisconnectFacebook(ctx); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := repository.Save(executioncontext.WithFlag(ctx, executioncontext.LIVE), u); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return fn
+}
+
+// checkEmailAddressAvailable checks email address is not used by any account
+func checkEmailAddressAvailable(ctx context.Context, userRepository persistence.UserRepository, email EmailAddress) error {
+	if _, err := userRepository.GetByEmail(ctx, email.String()); err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		return apperrors.Wrap(err)
+	} else if err == nil {
+		return apperrors.Wrap(fmt.Errorf("%w: user with this email address is already registered", apperrors.ErrInvalid))
+	}
+
+	return nil
+}
+
+// authorizeAccountOwner checks command is dispatched by user it is about
+func authorizeAccountOwner(ctx context.Context, userID uuid.UUID) error {
+	i, hasIdentity := identity.FromContext(ctx)
+	if !hasIdentity {
+		return apperrors.Wrap(apperrors.ErrUnauthorized)
+	}
+	if i.UserID != userID {
+		return apperrors.Wrap(fmt.Errorf("%w: command can be dispatched only by account owner", apperrors.ErrForbidden))
+	}
+
+	return nil
+}
+
+// authorizeRoleChange parses role and checks identity is allowed to grant or revoke it,
+// admins manage admins, only super admins manage super admins, nobody can change own roles
+// and service role is never granted to users
+func authorizeRoleChange(ctx context.Context, userID uuid.UUID, name string) (access.Role, error) {
+	i, hasIdentity := identity.FromContext(ctx)
+	if !hasIdentity {
+		return 0, apperrors.Wrap(apperrors.ErrUnauthorized)
+	}
+	if i.UserID == userID {
+		return 0, apperrors.Wrap(fmt.Errorf("%w: users can not change their own roles", apperrors.ErrForbidden))
+	}
+
+	role, err := identity.ParseRole(name)
+	if err != nil {
+		return 0, apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrInvalid, err))
+	}
+	if role.Has(identity.RoleService) {
+		return 0, apperrors.Wrap(fmt.Errorf("%w: role %s can not be granted to users", apperrors.ErrForbidden, identity.RoleService))
+	}
+
+	required := access.RoleAdmin.Add(access.RoleSuperAdmin)
+	if role.Has(access.RoleSuperAdmin) {
+		required = access.RoleSuperAdmin
+	}
+	if !i.Role.Has(required) {
+		return 0, apperrors.Wrap(fmt.Errorf("%w: (%s) can not change role %s", apperrors.ErrForbidden, i.Role, role))
+	}
+
+	return role, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/asaskevich/govalidator"
 
+	"github.com/vardius/go-api-boilerplate/pkg/contract"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 )
 
@@ -50,3 +51,8 @@ func (e EmailAddress) IsValid() error {
 func (e EmailAddress) String() string {
 	return string(e)
 }
+
+// JSONSchema implements contract.Describer interface
+func (e EmailAddress) JSONSchema() *contract.Schema {
+	return &contract.Schema{Type: "string", Format: "email"}
+}
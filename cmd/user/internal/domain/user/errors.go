@@ -2,7 +2,21 @@ package user
 
 import (
 	"fmt"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 )
 
 // ErrAlreadyRegistered is when user with given email already exist.
 var ErrAlreadyRegistered = fmt.Errorf("user is already registered")
+
+// ErrInvalidCredentials is when email and password do not match any user.
+var ErrInvalidCredentials = fmt.Errorf("invalid email or password")
+
+// ErrLastLoginMethod is when user would be left without any way to log in.
+var ErrLastLoginMethod = fmt.Errorf("user has no other way to log in, set password or verify email address first")
+
+// ErrMFARequired is when password matches but user with multi-factor authentication enabled has to pass challenge first.
+var ErrMFARequired = fmt.Errorf("%w: multi-factor authentication is required", apperrors.ErrUnauthorized)
+
+// ErrLoginCodeReused is when login code is exchanged again, outstanding login codes are revoked.
+var ErrLoginCodeReused = fmt.Errorf("%w: login code was already used", apperrors.ErrInvalid)
@@ -2,6 +2,7 @@ package user
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -16,13 +17,48 @@ var (
 	ConnectedWithFacebookType     = (ConnectedWithFacebook{}).GetType()
 	WasRegisteredWithGoogleType   = (WasRegisteredWithGoogle{}).GetType()
 	ConnectedWithGoogleType       = (ConnectedWithGoogle{}).GetType()
+	WasRegisteredWithPasswordType = (WasRegisteredWithPassword{}).GetType()
+	PasswordWasChangedType        = (PasswordWasChanged{}).GetType()
+	LoggedInWithPasswordType      = (LoggedInWithPassword{}).GetType()
+
+	EmailVerificationWasRequestedType = (EmailVerificationWasRequested{}).GetType()
+	EmailAddressWasVerifiedType       = (EmailAddressWasVerified{}).GetType()
+	ProfileWasUpdatedType             = (ProfileWasUpdated{}).GetType()
+
+	AccountDeletionWasRequestedType = (AccountDeletionWasRequested{}).GetType()
+	AccountDeletionWasCancelledType = (AccountDeletionWasCancelled{}).GetType()
+	WasDeletedType                  = (WasDeleted{}).GetType()
+
+	RoleWasGrantedType = (RoleWasGranted{}).GetType()
+	RoleWasRevokedType = (RoleWasRevoked{}).GetType()
+
+	MFAEnrolmentWasStartedType = (MFAEnrolmentWasStarted{}).GetType()
+	MFAWasEnabledType          = (MFAWasEnabled{}).GetType()
+	MFAWasDisabledType         = (MFAWasDisabled{}).GetType()
+	MFAChallengeWasPassedType  = (MFAChallengeWasPassed{}).GetType()
+
+	DisconnectedFromGoogleType   = (DisconnectedFromGoogle{}).GetType()
+	DisconnectedFromFacebookType = (DisconnectedFromFacebook{}).GetType()
+
+	WasRegisteredWithProviderType = (WasRegisteredWithProvider{}).GetType()
+	ConnectedWithProviderType     = (ConnectedWithProvider{}).GetType()
+
+	EmailAddressChangeWasRequestedType = (EmailAddressChangeWasRequested{}).GetType()
+	EmailAddressChangeWasCancelledType = (EmailAddressChangeWasCancelled{}).GetType()
+
+	LoginCodeWasUsedType      = (LoginCodeWasUsed{}).GetType()
+	LoginCodesWereRevokedType = (LoginCodesWereRevoked{}).GetType()
 )
 
-// AccessTokenWasRequested event
+// AccessTokenWasRequested event, login code is sent by email to be exchanged for access token,
+// events recorded before login codes were introduced carry no code
 type AccessTokenWasRequested struct {
 	ID           uuid.UUID    `json:"id" bson:"id"`
 	Email        EmailAddress `json:"email" bson:"email"`
 	RedirectPath string       `json:"redirect_path,omitempty" bson:"redirect_path,omitempty"`
+	Code         string       `json:"code,omitempty" bson:"code,omitempty"`
+	RequestedAt  time.Time    `json:"requested_at" bson:"requested_at"`
+	ExpiresAt    time.Time    `json:"expires_at" bson:"expires_at"`
 }
 
 // GetType returns event type
@@ -46,6 +82,7 @@ type WasRegisteredWithEmail struct {
 	ID           uuid.UUID    `json:"id" bson:"id"`
 	Email        EmailAddress `json:"email" bson:"email"`
 	RedirectPath string       `json:"redirect_path,omitempty" bson:"redirect_path,omitempty"`
+	Profile      `bson:",inline"`
 }
 
 // GetType returns event type
@@ -78,6 +115,16 @@ func (e *WasRegisteredWithEmail) GetRole() access.Role {
 	return access.RoleUser
 }
 
+// IsVerified returns true if email address is verified on registration
+func (e *WasRegisteredWithEmail) IsVerified() bool {
+	return false
+}
+
+// IsMFAEnabled returns false, multi-factor authentication is enabled after registration
+func (e *WasRegisteredWithEmail) IsMFAEnabled() bool {
+	return false
+}
+
 // WasRegisteredWithFacebook event
 type WasRegisteredWithFacebook struct {
 	ID           uuid.UUID    `json:"id" bson:"id"`
@@ -85,6 +132,7 @@ type WasRegisteredWithFacebook struct {
 	FacebookID   string       `json:"facebook_id" bson:"facebook_id"`
 	AccessToken  string       `json:"access_token" bson:"access_token"`
 	RedirectPath string       `json:"redirect_path,omitempty" bson:"redirect_path,omitempty"`
+	Profile      `bson:",inline"`
 }
 
 // GetType returns event type
@@ -117,6 +165,16 @@ func (e *WasRegisteredWithFacebook) GetRole() access.Role {
 	return access.RoleUser
 }
 
+// IsVerified returns true if email address is verified on registration, facebook verifies email addresses
+func (e *WasRegisteredWithFacebook) IsVerified() bool {
+	return true
+}
+
+// IsMFAEnabled returns false, multi-factor authentication is enabled after registration
+func (e *WasRegisteredWithFacebook) IsMFAEnabled() bool {
+	return false
+}
+
 // ConnectedWithFacebook event
 type ConnectedWithFacebook struct {
 	ID           uuid.UUID `json:"id" bson:"id"`
@@ -137,6 +195,7 @@ type WasRegisteredWithGoogle struct {
 	GoogleID     string       `json:"google_id" bson:"google_id"`
 	AccessToken  string       `json:"access_token" bson:"access_token"`
 	RedirectPath string       `json:"redirect_path,omitempty" bson:"redirect_path,omitempty"`
+	Profile      `bson:",inline"`
 }
 
 // GetType returns event type
@@ -169,6 +228,16 @@ func (e *WasRegisteredWithGoogle) GetRole() access.Role {
 	return access.RoleUser
 }
 
+// IsVerified returns true if email address is verified on registration, google verifies email addresses
+func (e *WasRegisteredWithGoogle) IsVerified() bool {
+	return true
+}
+
+// IsMFAEnabled returns false, multi-factor authentication is enabled after registration
+func (e *WasRegisteredWithGoogle) IsMFAEnabled() bool {
+	return false
+}
+
 // ConnectedWithGoogle event
 type ConnectedWithGoogle struct {
 	ID           uuid.UUID `json:"id" bson:"id"`
@@ -181,3 +250,346 @@ type ConnectedWithGoogle struct {
 func (e ConnectedWithGoogle) GetType() string {
 	return fmt.Sprintf("%T", e)
 }
+
+// WasRegisteredWithPassword event
+type WasRegisteredWithPassword struct {
+	ID           uuid.UUID    `json:"id" bson:"id"`
+	Email        EmailAddress `json:"email" bson:"email"`
+	PasswordHash string       `json:"password_hash" bson:"password_hash"`
+	Profile      `bson:",inline"`
+}
+
+// GetType returns event type
+func (e WasRegisteredWithPassword) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// GetID the id
+func (e *WasRegisteredWithPassword) GetID() string {
+	return e.ID.String()
+}
+
+// GetEmail the email
+func (e *WasRegisteredWithPassword) GetEmail() string {
+	return e.Email.String()
+}
+
+// GetFacebookID facebook id
+func (e *WasRegisteredWithPassword) GetFacebookID() string {
+	return ""
+}
+
+// GetGoogleID google id
+func (e *WasRegisteredWithPassword) GetGoogleID() string {
+	return ""
+}
+
+// GetRole
+func (e *WasRegisteredWithPassword) GetRole() access.Role {
+	return access.RoleUser
+}
+
+// IsVerified returns true if email address is verified on registration
+func (e *WasRegisteredWithPassword) IsVerified() bool {
+	return false
+}
+
+// IsMFAEnabled returns false, multi-factor authentication is enabled after registration
+func (e *WasRegisteredWithPassword) IsMFAEnabled() bool {
+	return false
+}
+
+// PasswordWasChanged event
+type PasswordWasChanged struct {
+	ID           uuid.UUID `json:"id" bson:"id"`
+	PasswordHash string    `json:"password_hash" bson:"password_hash"`
+}
+
+// GetType returns event type
+func (e PasswordWasChanged) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// LoggedInWithPassword event
+type LoggedInWithPassword struct {
+	ID    uuid.UUID    `json:"id" bson:"id"`
+	Email EmailAddress `json:"email" bson:"email"`
+}
+
+// GetType returns event type
+func (e LoggedInWithPassword) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// EmailVerificationWasRequested event
+type EmailVerificationWasRequested struct {
+	ID          uuid.UUID    `json:"id" bson:"id"`
+	Email       EmailAddress `json:"email" bson:"email"`
+	Token       string       `json:"token" bson:"token"`
+	RequestedAt time.Time    `json:"requested_at" bson:"requested_at"`
+	ExpiresAt   time.Time    `json:"expires_at" bson:"expires_at"`
+}
+
+// GetType returns event type
+func (e EmailVerificationWasRequested) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// EmailAddressWasVerified event
+type EmailAddressWasVerified struct {
+	ID    uuid.UUID    `json:"id" bson:"id"`
+	Email EmailAddress `json:"email" bson:"email"`
+}
+
+// GetType returns event type
+func (e EmailAddressWasVerified) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// ProfileWasUpdated event
+type ProfileWasUpdated struct {
+	ID      uuid.UUID `json:"id" bson:"id"`
+	Profile `bson:",inline"`
+}
+
+// GetType returns event type
+func (e ProfileWasUpdated) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// AccountDeletionWasRequested event
+type AccountDeletionWasRequested struct {
+	ID          uuid.UUID    `json:"id" bson:"id"`
+	Email       EmailAddress `json:"email" bson:"email"`
+	RequestedAt time.Time    `json:"requested_at" bson:"requested_at"`
+	DeleteAt    time.Time    `json:"delete_at" bson:"delete_at"`
+}
+
+// GetType returns event type
+func (e AccountDeletionWasRequested) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// AccountDeletionWasCancelled event
+type AccountDeletionWasCancelled struct {
+	ID uuid.UUID `json:"id" bson:"id"`
+}
+
+// GetType returns event type
+func (e AccountDeletionWasCancelled) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// WasDeleted event
+type WasDeleted struct {
+	ID uuid.UUID `json:"id" bson:"id"`
+}
+
+// GetType returns event type
+func (e WasDeleted) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// RoleWasGranted event
+type RoleWasGranted struct {
+	ID   uuid.UUID   `json:"id" bson:"id"`
+	Role access.Role `json:"role" bson:"role"`
+}
+
+// GetType returns event type
+func (e RoleWasGranted) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// RoleWasRevoked event
+type RoleWasRevoked struct {
+	ID   uuid.UUID   `json:"id" bson:"id"`
+	Role access.Role `json:"role" bson:"role"`
+}
+
+// GetType returns event type
+func (e RoleWasRevoked) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// MFAEnrolmentWasStarted event
+type MFAEnrolmentWasStarted struct {
+	ID     uuid.UUID `json:"id" bson:"id"`
+	Secret string    `json:"secret" bson:"secret"`
+}
+
+// GetType returns event type
+func (e MFAEnrolmentWasStarted) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// MFAWasEnabled event, recovery codes are stored as hashes
+type MFAWasEnabled struct {
+	ID            uuid.UUID `json:"id" bson:"id"`
+	Step          int64     `json:"step" bson:"step"`
+	RecoveryCodes []string  `json:"recovery_codes" bson:"recovery_codes"`
+}
+
+// GetType returns event type
+func (e MFAWasEnabled) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// MFAWasDisabled event
+type MFAWasDisabled struct {
+	ID uuid.UUID `json:"id" bson:"id"`
+}
+
+// GetType returns event type
+func (e MFAWasDisabled) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// MFAChallengeWasPassed event, either time step of one-time password or hash of used recovery code is set
+type MFAChallengeWasPassed struct {
+	ID           uuid.UUID `json:"id" bson:"id"`
+	Step         int64     `json:"step,omitempty" bson:"step,omitempty"`
+	RecoveryCode string    `json:"recovery_code,omitempty" bson:"recovery_code,omitempty"`
+}
+
+// GetType returns event type
+func (e MFAChallengeWasPassed) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// DisconnectedFromGoogle event
+type DisconnectedFromGoogle struct {
+	ID       uuid.UUID `json:"id" bson:"id"`
+	GoogleID string    `json:"google_id" bson:"google_id"`
+}
+
+// GetType returns event type
+func (e DisconnectedFromGoogle) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// DisconnectedFromFacebook event
+type DisconnectedFromFacebook struct {
+	ID         uuid.UUID `json:"id" bson:"id"`
+	FacebookID string    `json:"facebook_id" bson:"facebook_id"`
+}
+
+// GetType returns event type
+func (e DisconnectedFromFacebook) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// WasRegisteredWithProvider event, subject identifies user within identity provider
+type WasRegisteredWithProvider struct {
+	ID            uuid.UUID    `json:"id" bson:"id"`
+	Email         EmailAddress `json:"email" bson:"email"`
+	EmailVerified bool         `json:"email_verified" bson:"email_verified"`
+	Provider      string       `json:"provider" bson:"provider"`
+	Subject       string       `json:"subject" bson:"subject"`
+	AccessToken   string       `json:"access_token" bson:"access_token"`
+	RedirectPath  string       `json:"redirect_path,omitempty" bson:"redirect_path,omitempty"`
+	Profile       `bson:",inline"`
+}
+
+// GetType returns event type
+func (e WasRegisteredWithProvider) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// GetID the id
+func (e *WasRegisteredWithProvider) GetID() string {
+	return e.ID.String()
+}
+
+// GetEmail the email
+func (e *WasRegisteredWithProvider) GetEmail() string {
+	return e.Email.String()
+}
+
+// GetFacebookID facebook id
+func (e *WasRegisteredWithProvider) GetFacebookID() string {
+	return ""
+}
+
+// GetGoogleID google id
+func (e *WasRegisteredWithProvider) GetGoogleID() string {
+	return ""
+}
+
+// GetRole
+func (e *WasRegisteredWithProvider) GetRole() access.Role {
+	return access.RoleUser
+}
+
+// IsVerified returns true if provider verified email address
+func (e *WasRegisteredWithProvider) IsVerified() bool {
+	return e.EmailVerified
+}
+
+// IsMFAEnabled returns false, multi-factor authentication is enabled after registration
+func (e *WasRegisteredWithProvider) IsMFAEnabled() bool {
+	return false
+}
+
+// ConnectedWithProvider event
+type ConnectedWithProvider struct {
+	ID           uuid.UUID `json:"id" bson:"id"`
+	Provider     string    `json:"provider" bson:"provider"`
+	Subject      string    `json:"subject" bson:"subject"`
+	AccessToken  string    `json:"access_token" bson:"access_token"`
+	RedirectPath string    `json:"redirect_path,omitempty" bson:"redirect_path,omitempty"`
+}
+
+// GetType returns event type
+func (e ConnectedWithProvider) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// EmailAddressChangeWasRequested event, change is confirmed with token sent to new email address
+// and can be cancelled with cancellation token sent to current one
+type EmailAddressChangeWasRequested struct {
+	ID                uuid.UUID    `json:"id" bson:"id"`
+	Email             EmailAddress `json:"email" bson:"email"`
+	NewEmail          EmailAddress `json:"new_email" bson:"new_email"`
+	Token             string       `json:"token" bson:"token"`
+	CancellationToken string       `json:"cancellation_token" bson:"cancellation_token"`
+	RequestedAt       time.Time    `json:"requested_at" bson:"requested_at"`
+	ExpiresAt         time.Time    `json:"expires_at" bson:"expires_at"`
+}
+
+// GetType returns event type
+func (e EmailAddressChangeWasRequested) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// EmailAddressChangeWasCancelled event
+type EmailAddressChangeWasCancelled struct {
+	ID       uuid.UUID    `json:"id" bson:"id"`
+	NewEmail EmailAddress `json:"new_email" bson:"new_email"`
+}
+
+// GetType returns event type
+func (e EmailAddressChangeWasCancelled) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// LoginCodeWasUsed event
+type LoginCodeWasUsed struct {
+	ID   uuid.UUID `json:"id" bson:"id"`
+	Code string    `json:"code" bson:"code"`
+}
+
+// GetType returns event type
+func (e LoginCodeWasUsed) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
+
+// LoginCodesWereRevoked event, unused login codes can no longer be exchanged for access token
+type LoginCodesWereRevoked struct {
+	ID uuid.UUID `json:"id" bson:"id"`
+}
+
+// GetType returns event type
+func (e LoginCodesWereRevoked) GetType() string {
+	return fmt.Sprintf("%T", e)
+}
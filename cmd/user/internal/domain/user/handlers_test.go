@@ -0,0 +1,1232 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/eventhandler"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence/memory"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/repository"
+	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
+	"github.com/vardius/go-api-boilerplate/pkg/auth/password"
+	"github.com/vardius/go-api-boilerplate/pkg/auth/totp"
+	"github.com/vardius/go-api-boilerplate/pkg/contract"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	"github.com/vardius/go-api-boilerplate/pkg/domain/domaintest"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+	memoryeventbus "github.com/vardius/go-api-boilerplate/pkg/eventbus/memory"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+func TestMain(m *testing.M) {
+	for _, e := range []domain.RawEvent{
+		user.WasRegisteredWithEmail{},
+		user.WasRegisteredWithGoogle{},
+		user.WasRegisteredWithFacebook{},
+		user.EmailAddressWasChanged{},
+		user.AccessTokenWasRequested{},
+		user.ConnectedWithGoogle{},
+		user.ConnectedWithFacebook{},
+		user.WasRegisteredWithPassword{},
+		user.PasswordWasChanged{},
+		user.LoggedInWithPassword{},
+		user.EmailVerificationWasRequested{},
+		user.EmailAddressWasVerified{},
+		user.ProfileWasUpdated{},
+		user.AccountDeletionWasRequested{},
+		user.AccountDeletionWasCancelled{},
+		user.WasDeleted{},
+		user.RoleWasGranted{},
+		user.RoleWasRevoked{},
+		user.MFAEnrolmentWasStarted{},
+		user.MFAWasEnabled{},
+		user.MFAWasDisabled{},
+		user.MFAChallengeWasPassed{},
+		user.DisconnectedFromGoogle{},
+		user.DisconnectedFromFacebook{},
+		user.WasRegisteredWithProvider{},
+		user.ConnectedWithProvider{},
+		user.EmailAddressChangeWasRequested{},
+		user.EmailAddressChangeWasCancelled{},
+		user.LoginCodeWasUsed{},
+		user.LoginCodesWereRevoked{},
+	} {
+		if err := contract.RegisterEvent(e.GetType(), e); err != nil {
+			panic(err)
+		}
+	}
+
+	os.Exit(m.Run())
+}
+
+type fixture struct {
+	*domaintest.Scenario
+	repository  user.Repository
+	users       persistence.UserRepository
+	integration eventbus.EventBus
+}
+
+var (
+	verification = user.VerificationPolicy{TTL: time.Hour, ResendInterval: time.Minute}
+	loginCode    = user.LoginCodePolicy{TTL: 10 * time.Minute}
+)
+
+func eventTypes(r *domaintest.Result) []string {
+	types := make([]string, len(r.Events()))
+	for i, e := range r.Events() {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func setUp(t *testing.T) *fixture {
+	s := domaintest.New(t)
+	users := memory.NewUserRepository()
+
+	s.Project(user.WasRegisteredWithEmailType, eventhandler.WhenUserWasRegisteredWithEmail(users, s.CommandBus()))
+	s.Project(user.EmailAddressWasChangedType, eventhandler.WhenUserEmailAddressWasChanged(users))
+	s.Project(user.WasRegisteredWithPasswordType, eventhandler.WhenUserWasRegisteredWithPassword(users))
+	s.Project(user.WasRegisteredWithGoogleType, eventhandler.WhenUserWasRegisteredWithGoogle(users, s.CommandBus()))
+	s.Project(user.EmailAddressWasVerifiedType, eventhandler.WhenUserEmailAddressWasVerified(users))
+	s.Project(user.ProfileWasUpdatedType, eventhandler.WhenUserProfileWasUpdated(users))
+
+	integration := memoryeventbus.New(1)
+
+	s.Project(user.RoleWasGrantedType, eventhandler.WhenUserRoleWasGranted(users))
+	s.Project(user.RoleWasRevokedType, eventhandler.WhenUserRoleWasRevoked(users, integration))
+	s.Project(user.MFAWasEnabledType, eventhandler.WhenUserMFAWasEnabled(users))
+	s.Project(user.MFAWasDisabledType, eventhandler.WhenUserMFAWasDisabled(users))
+	s.Project(user.DisconnectedFromGoogleType, eventhandler.WhenUserDisconnectedFromGoogle(users))
+	s.Project(user.WasRegisteredWithProviderType, eventhandler.WhenUserWasRegisteredWithProvider(users, s.CommandBus()))
+	s.Project(user.ConnectedWithProviderType, eventhandler.WhenUserConnectedWithProvider(users, s.CommandBus()))
+
+	s.Project(user.WasDeletedType, eventhandler.WhenUserWasDeleted(users, s.EventStore(), integration))
+
+	return &fixture{
+		Scenario:    s,
+		repository:  repository.NewUserRepository(s.EventStore(), s.EventBus()),
+		users:       users,
+		integration: integration,
+	}
+}
+
+func TestOnRegisterWithEmail(t *testing.T) {
+	f := setUp(t)
+
+	result := f.When(context.Background(), user.OnRegisterWithEmail(f.repository, f.users, verification, loginCode), user.RegisterWithEmail{
+		Email:        "test@test.com",
+		RedirectPath: "/welcome",
+	})
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := domaintest.Equal("events", eventTypes(result), []string{user.WasRegisteredWithEmailType, user.EmailVerificationWasRequestedType}); err != nil {
+		t.Fatal(err)
+	}
+
+	e, ok := result.Events()[0].Payload.(*user.WasRegisteredWithEmail)
+	if !ok {
+		t.Fatalf("emitted %T, want %T", result.Events()[0].Payload, &user.WasRegisteredWithEmail{})
+	}
+
+	result.
+		ThenCommands(user.RequestAccessToken{ID: e.ID}).
+		ThenReadModel(func(ctx context.Context) error {
+			u, err := f.users.GetByEmail(ctx, "test@test.com")
+			if err != nil {
+				return err
+			}
+			return domaintest.Equal("id", u.GetID(), e.ID.String())
+		})
+}
+
+func TestOnRegisterWithEmailRequestsAccessTokenOfRegisteredUser(t *testing.T) {
+	id := uuid.New()
+
+	f := setUp(t)
+	result := f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}).
+		When(context.Background(), user.OnRegisterWithEmail(f.repository, f.users, verification, loginCode), user.RegisterWithEmail{
+			Email:        "test@test.com",
+			RedirectPath: "/welcome",
+		}).
+		ThenCommands()
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	e := result.Events()[0].Payload.(*user.AccessTokenWasRequested)
+	if err := domaintest.Equal("email", e.Email, user.EmailAddress("test@test.com")); err != nil {
+		t.Error(err)
+	}
+	if err := domaintest.Equal("redirect path", e.RedirectPath, "/welcome"); err != nil {
+		t.Error(err)
+	}
+	if e.Code == "" {
+		t.Error("login code is empty")
+	}
+	if err := domaintest.Equal("lifetime", e.ExpiresAt.Sub(e.RequestedAt), loginCode.TTL); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOnChangeEmailAddress(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := setUp(t)
+	result := f.Given(id, user.StreamName, &user.WasRegisteredWithGoogle{ID: id, Email: "test@test.com", GoogleID: "1"}).
+		When(ctx, user.OnChangeEmailAddress(f.repository, f.users, verification), user.ChangeEmailAddress{
+			ID:    id,
+			Email: "changed@test.com",
+		})
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := domaintest.Equal("events", eventTypes(result), []string{user.EmailAddressChangeWasRequestedType}); err != nil {
+		t.Fatal(err)
+	}
+
+	e := result.Events()[0].Payload.(*user.EmailAddressChangeWasRequested)
+	if err := domaintest.Equal("emails", []user.EmailAddress{e.Email, e.NewEmail}, []user.EmailAddress{"test@test.com", "changed@test.com"}); err != nil {
+		t.Error(err)
+	}
+	if e.Token == "" || e.CancellationToken == "" || e.Token == e.CancellationToken {
+		t.Errorf("tokens = %q, %q, want two different tokens", e.Token, e.CancellationToken)
+	}
+
+	// email address is not changed until confirmed
+	result.ThenReadModel(func(ctx context.Context) error {
+		u, err := f.users.Get(ctx, id.String())
+		if err != nil {
+			return err
+		}
+		return domaintest.Equal("email", u.GetEmail(), "test@test.com")
+	})
+}
+
+func TestOnChangeEmailAddressAlreadyRegistered(t *testing.T) {
+	id, otherID := uuid.New(), uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}).
+		Given(otherID, user.StreamName, &user.WasRegisteredWithEmail{ID: otherID, Email: "other@test.com"}).
+		When(ctx, user.OnChangeEmailAddress(f.repository, f.users, verification), user.ChangeEmailAddress{
+			ID:    id,
+			Email: "other@test.com",
+		}).
+		ThenError(apperrors.ErrInvalid)
+}
+
+func TestOnChangeEmailAddressOfAnotherUser(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: uuid.New()})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}).
+		When(ctx, user.OnChangeEmailAddress(f.repository, f.users, verification), user.ChangeEmailAddress{
+			ID:    id,
+			Email: "changed@test.com",
+		}).
+		ThenError(apperrors.ErrForbidden)
+}
+
+func TestOnChangeEmailAddressTooSoon(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+	now := time.Now()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.EmailAddressChangeWasRequested{ID: id, Email: "test@test.com", NewEmail: "changed@test.com", Token: "token", CancellationToken: "cancel", RequestedAt: now, ExpiresAt: now.Add(time.Hour)},
+		&user.EmailAddressChangeWasCancelled{ID: id, NewEmail: "changed@test.com"},
+	).
+		When(ctx, user.OnChangeEmailAddress(f.repository, f.users, verification), user.ChangeEmailAddress{
+			ID:    id,
+			Email: "other@test.com",
+		}).
+		ThenError(apperrors.ErrTooManyRequests)
+}
+
+func TestOnConfirmEmailAddressChange(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.EmailAddressChangeWasRequested{ID: id, Email: "test@test.com", NewEmail: "changed@test.com", Token: "token", CancellationToken: "cancel", RequestedAt: now, ExpiresAt: now.Add(time.Hour)},
+	).
+		When(context.Background(), user.OnConfirmEmailAddressChange(f.repository, f.users), user.ConfirmEmailAddressChange{ID: id, Token: "token"}).
+		Then(
+			&user.EmailAddressWasChanged{ID: id, Email: "changed@test.com"},
+			&user.EmailAddressWasVerified{ID: id, Email: "changed@test.com"},
+		).
+		ThenReadModel(func(ctx context.Context) error {
+			u, err := f.users.Get(ctx, id.String())
+			if err != nil {
+				return err
+			}
+			return domaintest.Equal("user", []interface{}{u.GetEmail(), u.IsVerified()}, []interface{}{"changed@test.com", true})
+		})
+}
+
+func TestOnConfirmEmailAddressChangeInvalidToken(t *testing.T) {
+	now := time.Now()
+
+	for name, requested := range map[string]*user.EmailAddressChangeWasRequested{
+		"wrong token":        {Token: "other", CancellationToken: "cancel", RequestedAt: now, ExpiresAt: now.Add(time.Hour)},
+		"cancellation token": {Token: "other", CancellationToken: "token", RequestedAt: now, ExpiresAt: now.Add(time.Hour)},
+		"expired":            {Token: "token", CancellationToken: "cancel", RequestedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			id := uuid.New()
+			requested.ID, requested.Email, requested.NewEmail = id, "test@test.com", "changed@test.com"
+
+			f := setUp(t)
+			f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}, requested).
+				When(context.Background(), user.OnConfirmEmailAddressChange(f.repository, f.users), user.ConfirmEmailAddressChange{ID: id, Token: "token"}).
+				ThenError(apperrors.ErrInvalid)
+		})
+	}
+}
+
+func TestOnConfirmEmailAddressChangeCancelled(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.EmailAddressChangeWasRequested{ID: id, Email: "test@test.com", NewEmail: "changed@test.com", Token: "token", CancellationToken: "cancel", RequestedAt: now, ExpiresAt: now.Add(time.Hour)},
+		&user.EmailAddressChangeWasCancelled{ID: id, NewEmail: "changed@test.com"},
+	).
+		When(context.Background(), user.OnConfirmEmailAddressChange(f.repository, f.users), user.ConfirmEmailAddressChange{ID: id, Token: "token"}).
+		ThenError(apperrors.ErrInvalid)
+}
+
+func TestOnConfirmEmailAddressChangeAlreadyRegistered(t *testing.T) {
+	id, otherID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.EmailAddressChangeWasRequested{ID: id, Email: "test@test.com", NewEmail: "changed@test.com", Token: "token", CancellationToken: "cancel", RequestedAt: now, ExpiresAt: now.Add(time.Hour)},
+	).
+		Given(otherID, user.StreamName, &user.WasRegisteredWithEmail{ID: otherID, Email: "changed@test.com"}).
+		When(context.Background(), user.OnConfirmEmailAddressChange(f.repository, f.users), user.ConfirmEmailAddressChange{ID: id, Token: "token"}).
+		ThenError(apperrors.ErrInvalid)
+}
+
+func TestOnCancelEmailAddressChange(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+	owner := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	for name, tt := range map[string]struct {
+		ctx   context.Context
+		token string
+		err   error
+	}{
+		"with token":      {context.Background(), "cancel", nil},
+		"by owner":        {owner, "", nil},
+		"wrong token":     {owner, "token", apperrors.ErrInvalid},
+		"without token":   {context.Background(), "", apperrors.ErrUnauthorized},
+		"by another user": {identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: uuid.New()}), "", apperrors.ErrForbidden},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := setUp(t)
+			result := f.Given(id, user.StreamName,
+				&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+				&user.EmailAddressChangeWasRequested{ID: id, Email: "test@test.com", NewEmail: "changed@test.com", Token: "token", CancellationToken: "cancel", RequestedAt: now, ExpiresAt: now.Add(time.Hour)},
+			).
+				When(tt.ctx, user.OnCancelEmailAddressChange(f.repository), user.CancelEmailAddressChange{ID: id, Token: tt.token})
+
+			if tt.err != nil {
+				result.ThenError(tt.err)
+				return
+			}
+			result.Then(&user.EmailAddressChangeWasCancelled{ID: id, NewEmail: "changed@test.com"})
+		})
+	}
+}
+
+func TestOnCancelEmailAddressChangeNotRequested(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}).
+		When(ctx, user.OnCancelEmailAddressChange(f.repository), user.CancelEmailAddressChange{ID: id}).
+		ThenError(apperrors.ErrConflict)
+}
+
+func TestOnRequestAccessTokenNotFound(t *testing.T) {
+	f := setUp(t)
+	f.When(context.Background(), user.OnRequestAccessToken(f.repository, loginCode), user.RequestAccessToken{ID: uuid.New()}).
+		ThenError(apperrors.ErrNotFound).
+		ThenReadModel(func(ctx context.Context) error {
+			_, err := f.users.GetByEmail(ctx, "test@test.com")
+			return domaintest.NotFound(err)
+		})
+}
+
+// hasher uses cheap key derivation to keep tests fast
+var hasher = user.PasswordHasher{
+	Policy: password.Policy{MinLength: 8},
+	Params: password.Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+}
+
+func givenPasswordUser(t *testing.T, f *fixture, id uuid.UUID, plain user.Password) *fixture {
+	hash, err := hasher.Hash(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.Given(id, user.StreamName, &user.WasRegisteredWithPassword{ID: id, Email: "test@test.com", PasswordHash: hash})
+
+	return f
+}
+
+func TestOnRegisterWithPassword(t *testing.T) {
+	f := setUp(t)
+
+	result := f.When(context.Background(), user.OnRegisterWithPassword(f.repository, f.users, hasher, verification), user.RegisterWithPassword{
+		Email:    "test@test.com",
+		Password: "correct horse",
+	})
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	e, ok := result.Events()[0].Payload.(*user.WasRegisteredWithPassword)
+	if !ok {
+		t.Fatalf("emitted %T, want %T", result.Events()[0].Payload, &user.WasRegisteredWithPassword{})
+	}
+	if err := password.Compare(e.PasswordHash, "correct horse"); err != nil {
+		t.Errorf("password hash does not match: %v", err)
+	}
+
+	result.ThenReadModel(func(ctx context.Context) error {
+		u, err := f.users.GetByEmail(ctx, "test@test.com")
+		if err != nil {
+			return err
+		}
+		return domaintest.Equal("id", u.GetID(), e.ID.String())
+	})
+}
+
+func TestOnRegisterWithPasswordPolicy(t *testing.T) {
+	f := setUp(t)
+	f.When(context.Background(), user.OnRegisterWithPassword(f.repository, f.users, hasher, verification), user.RegisterWithPassword{
+		Email:    "test@test.com",
+		Password: "short",
+	}).ThenError(apperrors.ErrInvalid)
+}
+
+func TestOnRegisterWithPasswordAlreadyRegistered(t *testing.T) {
+	id := uuid.New()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}).
+		When(context.Background(), user.OnRegisterWithPassword(f.repository, f.users, hasher, verification), user.RegisterWithPassword{
+			Email:    "test@test.com",
+			Password: "correct horse",
+		}).
+		ThenError(apperrors.ErrConflict)
+}
+
+func TestOnLoginWithPassword(t *testing.T) {
+	id := uuid.New()
+
+	f := givenPasswordUser(t, setUp(t), id, "correct horse")
+	f.When(context.Background(), user.OnLoginWithPassword(f.repository, f.users), user.LoginWithPassword{
+		Email:    "test@test.com",
+		Password: "correct horse",
+	}).Then(&user.LoggedInWithPassword{ID: id, Email: "test@test.com"})
+}
+
+func TestOnLoginWithPasswordMFARequired(t *testing.T) {
+	id := uuid.New()
+
+	f := givenPasswordUser(t, setUp(t), id, "correct horse")
+	f.Given(id, user.StreamName, &user.MFAEnrolmentWasStarted{ID: id, Secret: mfaSecret}, &user.MFAWasEnabled{ID: id}).
+		When(context.Background(), user.OnLoginWithPassword(f.repository, f.users), user.LoginWithPassword{
+			Email:    "test@test.com",
+			Password: "correct horse",
+		}).
+		ThenError(user.ErrMFARequired)
+}
+
+func TestOnLoginWithPasswordInvalidCredentials(t *testing.T) {
+	id := uuid.New()
+
+	for name, c := range map[string]user.LoginWithPassword{
+		"wrong password": {Email: "test@test.com", Password: "battery staple"},
+		"unknown email":  {Email: "other@test.com", Password: "correct horse"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := givenPasswordUser(t, setUp(t), id, "correct horse")
+			f.When(context.Background(), user.OnLoginWithPassword(f.repository, f.users), c).
+				ThenError(apperrors.ErrUnauthorized)
+		})
+	}
+}
+
+func TestOnChangePassword(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := givenPasswordUser(t, setUp(t), id, "correct horse")
+	result := f.When(ctx, user.OnChangePassword(f.repository, hasher), user.ChangePassword{
+		ID:              id,
+		CurrentPassword: "correct horse",
+		NewPassword:     "battery staple",
+	})
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	e := result.Events()[0].Payload.(*user.PasswordWasChanged)
+	if err := password.Compare(e.PasswordHash, "battery staple"); err != nil {
+		t.Errorf("password hash does not match: %v", err)
+	}
+}
+
+func TestOnChangePasswordWrongCurrentPassword(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := givenPasswordUser(t, setUp(t), id, "correct horse")
+	f.When(ctx, user.OnChangePassword(f.repository, hasher), user.ChangePassword{
+		ID:              id,
+		CurrentPassword: "wrong horse",
+		NewPassword:     "battery staple",
+	}).ThenError(apperrors.ErrForbidden)
+}
+
+func TestOnChangePasswordOfAnotherUser(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: uuid.New()})
+
+	f := givenPasswordUser(t, setUp(t), id, "correct horse")
+	f.When(ctx, user.OnChangePassword(f.repository, hasher), user.ChangePassword{
+		ID:              id,
+		CurrentPassword: "correct horse",
+		NewPassword:     "battery staple",
+	}).ThenError(apperrors.ErrForbidden)
+}
+
+func TestPasswordIsNotPrinted(t *testing.T) {
+	c := user.LoginWithPassword{Email: "test@test.com", Password: "correct horse"}
+	if s := fmt.Sprintf("%+v %#v", c, c); strings.Contains(s, "correct horse") {
+		t.Errorf("password printed: %s", s)
+	}
+}
+
+func TestOnVerifyEmailAddress(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.EmailVerificationWasRequested{ID: id, Email: "test@test.com", Token: "token", RequestedAt: now, ExpiresAt: now.Add(time.Hour)},
+	).
+		When(context.Background(), user.OnVerifyEmailAddress(f.repository), user.VerifyEmailAddress{ID: id, Token: "token"}).
+		Then(&user.EmailAddressWasVerified{ID: id, Email: "test@test.com"}).
+		ThenReadModel(func(ctx context.Context) error {
+			u, err := f.users.Get(ctx, id.String())
+			if err != nil {
+				return err
+			}
+			return domaintest.Equal("verified", u.IsVerified(), true)
+		})
+}
+
+func TestOnVerifyEmailAddressInvalidToken(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	for name, requested := range map[string]*user.EmailVerificationWasRequested{
+		"wrong token":   {ID: id, Email: "test@test.com", Token: "other", RequestedAt: now, ExpiresAt: now.Add(time.Hour)},
+		"expired token": {ID: id, Email: "test@test.com", Token: "token", RequestedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := setUp(t)
+			f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}, requested).
+				When(context.Background(), user.OnVerifyEmailAddress(f.repository), user.VerifyEmailAddress{ID: id, Token: "token"}).
+				ThenError(apperrors.ErrInvalid)
+		})
+	}
+}
+
+func TestOnVerifyEmailAddressTokenOfPreviousEmail(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.EmailVerificationWasRequested{ID: id, Email: "test@test.com", Token: "token", RequestedAt: now, ExpiresAt: now.Add(time.Hour)},
+		&user.EmailAddressWasChanged{ID: id, Email: "changed@test.com"},
+	).
+		When(context.Background(), user.OnVerifyEmailAddress(f.repository), user.VerifyEmailAddress{ID: id, Token: "token"}).
+		ThenError(apperrors.ErrInvalid)
+}
+
+func TestOnRequestEmailVerificationTooSoon(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.EmailVerificationWasRequested{ID: id, Email: "test@test.com", Token: "token", RequestedAt: now, ExpiresAt: now.Add(time.Hour)},
+	).
+		When(context.Background(), user.OnRequestEmailVerification(f.repository, verification), user.RequestEmailVerification{ID: id}).
+		ThenError(apperrors.ErrTooManyRequests)
+}
+
+func TestOnRequestEmailVerificationAlreadyVerified(t *testing.T) {
+	id := uuid.New()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithGoogle{ID: id, Email: "test@test.com", GoogleID: "1"}).
+		When(context.Background(), user.OnRequestEmailVerification(f.repository, verification), user.RequestEmailVerification{ID: id}).
+		ThenError(apperrors.ErrConflict)
+}
+
+func TestOnRegisterWithGoogleProfile(t *testing.T) {
+	f := setUp(t)
+
+	result := f.When(context.Background(), user.OnRegisterWithGoogle(f.repository, f.users, loginCode), user.RegisterWithGoogle{
+		Email:       "test@test.com",
+		GoogleID:    "1",
+		AccessToken: "token",
+		Name:        "Jane Doe",
+		AvatarURL:   "https://example.com/avatar.png",
+		Locale:      "en_us",
+	})
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	result.ThenReadModel(func(ctx context.Context) error {
+		u, err := f.users.GetByGoogleID(ctx, "1")
+		if err != nil {
+			return err
+		}
+		return domaintest.Equal("profile", []string{u.GetName(), u.GetAvatarURL(), u.GetLocale()}, []string{"Jane Doe", "https://example.com/avatar.png", "en-US"})
+	})
+}
+
+func TestOnUpdateProfile(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithGoogle{ID: id, Email: "test@test.com", GoogleID: "1", Profile: user.Profile{Name: "Jane"}}).
+		When(ctx, user.OnUpdateProfile(f.repository), user.UpdateProfile{
+			ID:       id,
+			Name:     "Jane Doe",
+			Locale:   "pl_pl",
+			Timezone: "Europe/Warsaw",
+		}).
+		Then(&user.ProfileWasUpdated{ID: id, Profile: user.Profile{Name: "Jane Doe", Locale: "pl-PL", Timezone: "Europe/Warsaw"}}).
+		ThenReadModel(func(ctx context.Context) error {
+			u, err := f.users.Get(ctx, id.String())
+			if err != nil {
+				return err
+			}
+			return domaintest.Equal("profile", []string{u.GetName(), u.GetLocale(), u.GetTimezone()}, []string{"Jane Doe", "pl-PL", "Europe/Warsaw"})
+		})
+}
+
+func TestOnUpdateProfileUnchanged(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithGoogle{ID: id, Email: "test@test.com", GoogleID: "1", Profile: user.Profile{Name: "Jane"}}).
+		When(ctx, user.OnUpdateProfile(f.repository), user.UpdateProfile{ID: id, Name: "Jane"}).
+		Then()
+}
+
+func TestOnUpdateProfileOfAnotherUser(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: uuid.New()})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}).
+		When(ctx, user.OnUpdateProfile(f.repository), user.UpdateProfile{ID: id, Name: "Jane Doe"}).
+		ThenError(apperrors.ErrForbidden)
+}
+
+func TestOnRequestAccountDeletion(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := setUp(t)
+	result := f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}).
+		When(ctx, user.OnRequestAccountDeletion(f.repository, 24*time.Hour), user.RequestAccountDeletion{ID: id})
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := domaintest.Equal("events", eventTypes(result), []string{user.AccountDeletionWasRequestedType}); err != nil {
+		t.Fatal(err)
+	}
+
+	e := result.Events()[0].Payload.(*user.AccountDeletionWasRequested)
+	if err := domaintest.Equal("grace period", e.DeleteAt.Sub(e.RequestedAt), 24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOnRequestAccountDeletionOfAnotherUser(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: uuid.New()})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}).
+		When(ctx, user.OnRequestAccountDeletion(f.repository, 24*time.Hour), user.RequestAccountDeletion{ID: id}).
+		ThenError(apperrors.ErrForbidden)
+}
+
+func TestOnDeleteAccountBeforeGracePeriod(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.AccountDeletionWasRequested{ID: id, Email: "test@test.com", RequestedAt: now, DeleteAt: now.Add(time.Hour)},
+	).
+		When(context.Background(), user.OnDeleteAccount(f.repository), user.DeleteAccount{ID: id}).
+		ThenError(apperrors.ErrConflict)
+}
+
+func TestOnDeleteAccountCancelled(t *testing.T) {
+	id := uuid.New()
+	now := time.Now().Add(-time.Hour)
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.AccountDeletionWasRequested{ID: id, Email: "test@test.com", RequestedAt: now, DeleteAt: now},
+		&user.AccountDeletionWasCancelled{ID: id},
+	).
+		When(context.Background(), user.OnDeleteAccount(f.repository), user.DeleteAccount{ID: id}).
+		ThenError(apperrors.ErrConflict)
+}
+
+func TestOnDeleteAccount(t *testing.T) {
+	id := uuid.New()
+	now := time.Now().Add(-time.Hour)
+
+	f := setUp(t)
+
+	published := make(chan *domain.Event, 1)
+	if err := f.integration.Subscribe(context.Background(), userproto.UserWasDeletedEventType, func(ctx context.Context, event *domain.Event) error {
+		published <- event
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.AccountDeletionWasRequested{ID: id, Email: "test@test.com", RequestedAt: now, DeleteAt: now},
+	).
+		When(context.Background(), user.OnDeleteAccount(f.repository), user.DeleteAccount{ID: id}).
+		Then(&user.WasDeleted{ID: id}).
+		ThenReadModel(func(ctx context.Context) error {
+			_, err := f.users.Get(ctx, id.String())
+			if !errors.Is(err, apperrors.ErrNotFound) {
+				return fmt.Errorf("read model was not removed: %v", err)
+			}
+			events, err := f.EventStore().GetStream(ctx, id, user.StreamName)
+			if err != nil {
+				return err
+			}
+			if len(events) != 0 {
+				return fmt.Errorf("%d events of deleted user were kept", len(events))
+			}
+			return nil
+		})
+
+	select {
+	case event := <-published:
+		if err := domaintest.Equal("user id", event.Payload.(userproto.UserWasDeleted).UserID, id); err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("integration event was not published")
+	}
+}
+
+func TestOnGrantRole(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: uuid.New(), Role: identity.RoleUser | identity.RoleAdmin})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}).
+		When(ctx, user.OnGrantRole(f.repository), user.GrantRole{ID: id, Role: "ADMIN"}).
+		Then(&user.RoleWasGranted{ID: id, Role: identity.RoleAdmin}).
+		ThenReadModel(func(ctx context.Context) error {
+			u, err := f.users.Get(ctx, id.String())
+			if err != nil {
+				return err
+			}
+			return domaintest.Equal("role", u.GetRole(), identity.RoleUser|identity.RoleAdmin)
+		})
+}
+
+func TestOnGrantRoleAlreadyGranted(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: uuid.New(), Role: identity.RoleSuperAdmin})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.RoleWasGranted{ID: id, Role: identity.RoleAdmin},
+	).
+		When(ctx, user.OnGrantRole(f.repository), user.GrantRole{ID: id, Role: "ADMIN"}).
+		Then()
+}
+
+func TestOnGrantRoleForbidden(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name     string
+		identity *identity.Identity
+		role     string
+	}{
+		{"super admin by admin", &identity.Identity{UserID: uuid.New(), Role: identity.RoleAdmin}, "SUPER_ADMIN"},
+		{"admin by user", &identity.Identity{UserID: uuid.New(), Role: identity.RoleUser}, "ADMIN"},
+		{"own role", &identity.Identity{UserID: id, Role: identity.RoleSuperAdmin}, "ADMIN"},
+		{"service role", &identity.Identity{UserID: uuid.New(), Role: identity.RoleSuperAdmin}, "SERVICE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setUp(t)
+			f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}).
+				When(identity.ContextWithIdentity(context.Background(), tt.identity), user.OnGrantRole(f.repository), user.GrantRole{ID: id, Role: tt.role}).
+				ThenError(apperrors.ErrForbidden)
+		})
+	}
+}
+
+func TestOnRevokeRole(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: uuid.New(), Role: identity.RoleSuperAdmin})
+
+	f := setUp(t)
+
+	published := make(chan *domain.Event, 1)
+	if err := f.integration.Subscribe(context.Background(), userproto.UserRoleWasRevokedEventType, func(ctx context.Context, event *domain.Event) error {
+		published <- event
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.RoleWasGranted{ID: id, Role: identity.RoleSuperAdmin},
+	).
+		When(ctx, user.OnRevokeRole(f.repository), user.RevokeRole{ID: id, Role: "SUPER_ADMIN"}).
+		Then(&user.RoleWasRevoked{ID: id, Role: identity.RoleSuperAdmin}).
+		ThenReadModel(func(ctx context.Context) error {
+			u, err := f.users.Get(ctx, id.String())
+			if err != nil {
+				return err
+			}
+			return domaintest.Equal("role", u.GetRole(), identity.RoleUser)
+		})
+
+	select {
+	case event := <-published:
+		if err := domaintest.Equal("user id", event.Payload.(userproto.UserRoleWasRevoked).UserID, id); err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("integration event was not published")
+	}
+}
+
+const mfaSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+func currentCode(t *testing.T) string {
+	code, err := totp.Code(mfaSecret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func mfaEnabled(id uuid.UUID, recoveryCodes ...string) []domain.RawEvent {
+	hashes := make([]string, len(recoveryCodes))
+	for i, code := range recoveryCodes {
+		hashes[i] = totp.HashRecoveryCode(code)
+	}
+
+	return []domain.RawEvent{
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.MFAEnrolmentWasStarted{ID: id, Secret: mfaSecret},
+		&user.MFAWasEnabled{ID: id, RecoveryCodes: hashes},
+	}
+}
+
+func TestOnEnableMFA(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+	recoveryCodes, err := totp.GenerateRecoveryCodes(user.RecoveryCodesCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := setUp(t)
+	result := f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.MFAEnrolmentWasStarted{ID: id, Secret: mfaSecret},
+	).When(ctx, user.OnEnableMFA(f.repository), user.EnableMFA{ID: id, Code: currentCode(t), RecoveryCodes: recoveryCodes})
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := domaintest.Equal("events", eventTypes(result), []string{user.MFAWasEnabledType}); err != nil {
+		t.Fatal(err)
+	}
+
+	e := result.Events()[0].Payload.(*user.MFAWasEnabled)
+	if e.RecoveryCodes[0] != totp.HashRecoveryCode(recoveryCodes[0]) {
+		t.Errorf("recovery code was not hashed: %s", e.RecoveryCodes[0])
+	}
+
+	result.ThenReadModel(func(ctx context.Context) error {
+		u, err := f.users.Get(ctx, id.String())
+		if err != nil {
+			return err
+		}
+		return domaintest.Equal("mfa enabled", u.IsMFAEnabled(), true)
+	})
+}
+
+func TestOnEnableMFAInvalidCode(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+	recoveryCodes, err := totp.GenerateRecoveryCodes(user.RecoveryCodesCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		&user.MFAEnrolmentWasStarted{ID: id, Secret: mfaSecret},
+	).
+		When(ctx, user.OnEnableMFA(f.repository), user.EnableMFA{ID: id, Code: "000000", RecoveryCodes: recoveryCodes}).
+		ThenError(apperrors.ErrInvalid)
+}
+
+func TestOnPassMFAChallenge(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	step := totp.Step(time.Now())
+	code, err := totp.Code(mfaSecret, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, mfaEnabled(id)...).
+		When(ctx, user.OnPassMFAChallenge(f.repository), user.PassMFAChallenge{ID: id, Code: code}).
+		Then(&user.MFAChallengeWasPassed{ID: id, Step: step})
+}
+
+func TestOnPassMFAChallengeReusedCode(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, append(mfaEnabled(id), &user.MFAChallengeWasPassed{ID: id, Step: totp.Step(time.Now()) + 1})...).
+		When(ctx, user.OnPassMFAChallenge(f.repository), user.PassMFAChallenge{ID: id, Code: currentCode(t)}).
+		ThenError(apperrors.ErrUnauthorized)
+}
+
+func TestOnPassMFAChallengeWithRecoveryCode(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+	hash := totp.HashRecoveryCode("abcde-fghij")
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, mfaEnabled(id, "abcde-fghij", "klmno-pqrst")...).
+		When(ctx, user.OnPassMFAChallenge(f.repository), user.PassMFAChallenge{ID: id, Code: "ABCDEFGHIJ"}).
+		Then(&user.MFAChallengeWasPassed{ID: id, RecoveryCode: hash})
+
+	f = setUp(t)
+	f.Given(id, user.StreamName, append(mfaEnabled(id, "abcde-fghij", "klmno-pqrst"), &user.MFAChallengeWasPassed{ID: id, RecoveryCode: hash})...).
+		When(ctx, user.OnPassMFAChallenge(f.repository), user.PassMFAChallenge{ID: id, Code: "abcde-fghij"}).
+		ThenError(apperrors.ErrUnauthorized)
+}
+
+func TestOnPassMFAChallengeOfAnotherUser(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: uuid.New()})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, mfaEnabled(id)...).
+		When(ctx, user.OnPassMFAChallenge(f.repository), user.PassMFAChallenge{ID: id, Code: currentCode(t)}).
+		ThenError(apperrors.ErrForbidden)
+}
+
+func TestOnDisableMFA(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, mfaEnabled(id)...).
+		When(ctx, user.OnDisableMFA(f.repository), user.DisableMFA{ID: id, Code: currentCode(t)}).
+		Then(&user.MFAWasDisabled{ID: id})
+}
+
+func TestOnDisconnectGoogle(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithGoogle{ID: id, Email: "test@test.com", GoogleID: "1", AccessToken: "token"},
+		&user.PasswordWasChanged{ID: id, PasswordHash: "hash"},
+	).
+		When(ctx, user.OnDisconnectGoogle(f.repository), user.DisconnectGoogle{ID: id}).
+		Then(&user.DisconnectedFromGoogle{ID: id, GoogleID: "1"}).
+		ThenReadModel(func(ctx context.Context) error {
+			u, err := f.users.Get(ctx, id.String())
+			if err != nil {
+				return err
+			}
+			return domaintest.Equal("google id", u.GetGoogleID(), "")
+		})
+}
+
+func TestOnDisconnectGoogleLastLoginMethod(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithGoogle{ID: id, Email: "test@test.com", GoogleID: "1", AccessToken: "token"},
+		&user.EmailAddressWasChanged{ID: id, Email: "new@test.com"},
+	).
+		When(ctx, user.OnDisconnectGoogle(f.repository), user.DisconnectGoogle{ID: id}).
+		ThenError(apperrors.ErrConflict)
+}
+
+func TestOnDisconnectFacebookNotConnected(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithGoogle{ID: id, Email: "test@test.com", GoogleID: "1", AccessToken: "token"}).
+		When(ctx, user.OnDisconnectFacebook(f.repository), user.DisconnectFacebook{ID: id}).
+		Then()
+}
+
+func TestOnDisconnectFacebookOfAnotherUser(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: uuid.New()})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithFacebook{ID: id, Email: "test@test.com", FacebookID: "1", AccessToken: "token"}).
+		When(ctx, user.OnDisconnectFacebook(f.repository), user.DisconnectFacebook{ID: id}).
+		ThenError(apperrors.ErrForbidden)
+}
+
+func TestOnRegisterWithProvider(t *testing.T) {
+	f := setUp(t)
+
+	result := f.When(context.Background(), user.OnRegisterWithProvider(f.repository, f.users, verification, loginCode), user.RegisterWithProvider{
+		Provider:      "gitlab",
+		Subject:       "42",
+		Email:         "test@test.com",
+		EmailVerified: true,
+		AccessToken:   "token",
+		Name:          "Jane Doe",
+	})
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := domaintest.Equal("events", eventTypes(result), []string{user.WasRegisteredWithProviderType}); err != nil {
+		t.Fatal(err)
+	}
+
+	e := result.Events()[0].Payload.(*user.WasRegisteredWithProvider)
+
+	result.
+		ThenCommands(user.RequestAccessToken{ID: e.ID}).
+		ThenReadModel(func(ctx context.Context) error {
+			u, err := f.users.GetByProvider(ctx, "gitlab", "42")
+			if err != nil {
+				return err
+			}
+			return domaintest.Equal("user", []interface{}{u.GetID(), u.IsVerified(), u.GetName()}, []interface{}{e.ID.String(), true, "Jane Doe"})
+		})
+}
+
+func TestOnRegisterWithProviderUnverifiedEmail(t *testing.T) {
+	f := setUp(t)
+
+	result := f.When(context.Background(), user.OnRegisterWithProvider(f.repository, f.users, verification, loginCode), user.RegisterWithProvider{
+		Provider:    "github",
+		Subject:     "42",
+		Email:       "test@test.com",
+		AccessToken: "token",
+	})
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := domaintest.Equal("events", eventTypes(result), []string{user.WasRegisteredWithProviderType, user.EmailVerificationWasRequestedType}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOnRegisterWithProviderConnectsRegisteredUser(t *testing.T) {
+	id := uuid.New()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}).
+		When(context.Background(), user.OnRegisterWithProvider(f.repository, f.users, verification, loginCode), user.RegisterWithProvider{
+			Provider:      "gitlab",
+			Subject:       "42",
+			Email:         "test@test.com",
+			EmailVerified: true,
+			AccessToken:   "token",
+		}).
+		Then(&user.ConnectedWithProvider{ID: id, Provider: "gitlab", Subject: "42", AccessToken: "token"}).
+		ThenCommands(user.RequestAccessToken{ID: id}).
+		ThenReadModel(func(ctx context.Context) error {
+			u, err := f.users.GetByProvider(ctx, "gitlab", "42")
+			if err != nil {
+				return err
+			}
+			return domaintest.Equal("id", u.GetID(), id.String())
+		})
+}
+
+func TestOnRegisterWithProviderUnverifiedEmailOfRegisteredUser(t *testing.T) {
+	id := uuid.New()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}).
+		When(context.Background(), user.OnRegisterWithProvider(f.repository, f.users, verification, loginCode), user.RegisterWithProvider{
+			Provider:    "github",
+			Subject:     "42",
+			Email:       "test@test.com",
+			AccessToken: "token",
+		}).
+		ThenError(apperrors.ErrInvalid)
+}
+
+func TestOnDisconnectGoogleConnectedWithProvider(t *testing.T) {
+	id := uuid.New()
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: id})
+
+	f := setUp(t)
+	f.Given(id, user.StreamName,
+		&user.WasRegisteredWithGoogle{ID: id, Email: "test@test.com", GoogleID: "1", AccessToken: "token"},
+		&user.ConnectedWithProvider{ID: id, Provider: "gitlab", Subject: "42", AccessToken: "token"},
+		&user.EmailAddressWasChanged{ID: id, Email: "new@test.com"},
+	).
+		When(ctx, user.OnDisconnectGoogle(f.repository), user.DisconnectGoogle{ID: id}).
+		Then(&user.DisconnectedFromGoogle{ID: id, GoogleID: "1"})
+}
+
+func loginCodeRequested(id uuid.UUID, code string, expiresAt time.Time) *user.AccessTokenWasRequested {
+	return &user.AccessTokenWasRequested{ID: id, Email: "test@test.com", Code: code, RequestedAt: expiresAt.Add(-loginCode.TTL), ExpiresAt: expiresAt}
+}
+
+func TestOnUseLoginCode(t *testing.T) {
+	id := uuid.New()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}, loginCodeRequested(id, "code", time.Now().Add(time.Minute))).
+		When(context.Background(), user.OnUseLoginCode(f.repository), user.UseLoginCode{ID: id, Code: "code"}).
+		Then(&user.LoginCodeWasUsed{ID: id, Code: "code"})
+}
+
+func TestOnUseLoginCodeInvalid(t *testing.T) {
+	id := uuid.New()
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}, loginCodeRequested(id, "code", time.Now().Add(time.Minute))).
+		When(context.Background(), user.OnUseLoginCode(f.repository), user.UseLoginCode{ID: id, Code: "other"}).
+		ThenError(apperrors.ErrInvalid)
+
+	f = setUp(t)
+	f.Given(id, user.StreamName, &user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"}, loginCodeRequested(id, "code", time.Now().Add(-time.Minute))).
+		When(context.Background(), user.OnUseLoginCode(f.repository), user.UseLoginCode{ID: id, Code: "code"}).
+		ThenError(apperrors.ErrInvalid)
+}
+
+func TestOnUseLoginCodeReused(t *testing.T) {
+	id := uuid.New()
+	expiresAt := time.Now().Add(time.Minute)
+	given := []domain.RawEvent{
+		&user.WasRegisteredWithEmail{ID: id, Email: "test@test.com"},
+		loginCodeRequested(id, "used", expiresAt),
+		loginCodeRequested(id, "outstanding", expiresAt),
+		&user.LoginCodeWasUsed{ID: id, Code: "used"},
+	}
+
+	f := setUp(t)
+	result := f.Given(id, user.StreamName, given...).
+		When(context.Background(), user.OnUseLoginCode(f.repository), user.UseLoginCode{ID: id, Code: "used"})
+	if !errors.Is(result.Err(), user.ErrLoginCodeReused) {
+		t.Fatalf("error = %v, want %v", result.Err(), user.ErrLoginCodeReused)
+	}
+	if err := domaintest.Equal("events", eventTypes(result), []string{user.LoginCodesWereRevokedType}); err != nil {
+		t.Error(err)
+	}
+
+	// outstanding codes can not be used once revoked
+	f = setUp(t)
+	f.Given(id, user.StreamName, append(given, &user.LoginCodesWereRevoked{ID: id})...).
+		When(context.Background(), user.OnUseLoginCode(f.repository), user.UseLoginCode{ID: id, Code: "outstanding"}).
+		ThenError(apperrors.ErrInvalid)
+}
+
+func TestOnUseLoginCodeWithMFA(t *testing.T) {
+	id := uuid.New()
+	given := append(mfaEnabled(id), loginCodeRequested(id, "code", time.Now().Add(time.Minute)))
+
+	f := setUp(t)
+	f.Given(id, user.StreamName, given...).
+		When(context.Background(), user.OnUseLoginCode(f.repository), user.UseLoginCode{ID: id, Code: "code"}).
+		ThenError(apperrors.ErrUnauthorized)
+
+	step := totp.Step(time.Now())
+	otp, err := totp.Code(mfaSecret, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f = setUp(t)
+	f.Given(id, user.StreamName, given...).
+		When(context.Background(), user.OnUseLoginCode(f.repository), user.UseLoginCode{ID: id, Code: "code", OTP: otp}).
+		Then(&user.LoginCodeWasUsed{ID: id, Code: "code"}, &user.MFAChallengeWasPassed{ID: id, Step: step})
+}
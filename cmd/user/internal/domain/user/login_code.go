@@ -0,0 +1,29 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+// LoginCodePolicy describes lifetime of login codes sent by email
+type LoginCodePolicy struct {
+	TTL time.Duration // how long login code can be exchanged for access token
+}
+
+// loginCode sent to user, used codes are kept until they expire so their reuse can be detected
+type loginCode struct {
+	expiresAt time.Time
+	used      bool
+}
+
+// newLoginCode returns random url safe code
+func newLoginCode() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("could not generate login code: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
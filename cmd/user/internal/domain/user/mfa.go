@@ -0,0 +1,56 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/pkg/auth/totp"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+// RecoveryCodesCount is the number of recovery codes issued when multi-factor authentication is enabled
+const RecoveryCodesCount = 10
+
+type mfa struct {
+	enabled       bool
+	secret        string
+	pendingSecret string
+	lastStep      int64    // time step of last accepted one-time password, codes can not be reused
+	recoveryCodes []string // hashes of unused recovery codes
+}
+
+// verify accepts one-time password not used before or unused recovery code,
+// returns time step or recovery code hash to be recorded
+func (m mfa) verify(code string, now time.Time) (int64, string, error) {
+	if len(code) == totp.Digits {
+		step, err := totp.Validate(m.secret, code, now)
+		if err != nil {
+			return 0, "", apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrUnauthorized, err))
+		}
+		if step <= m.lastStep {
+			return 0, "", apperrors.Wrap(fmt.Errorf("%w: one-time password was already used", apperrors.ErrUnauthorized))
+		}
+
+		return step, "", nil
+	}
+
+	hash := totp.HashRecoveryCode(code)
+	for _, recoveryCode := range m.recoveryCodes {
+		if recoveryCode == hash {
+			return 0, hash, nil
+		}
+	}
+
+	return 0, "", apperrors.Wrap(fmt.Errorf("%w: invalid recovery code", apperrors.ErrUnauthorized))
+}
+
+func (m mfa) withoutRecoveryCode(hash string) []string {
+	codes := make([]string, 0, len(m.recoveryCodes))
+	for _, recoveryCode := range m.recoveryCodes {
+		if recoveryCode != hash {
+			codes = append(codes, recoveryCode)
+		}
+	}
+
+	return codes
+}
@@ -0,0 +1,44 @@
+package user
+
+import (
+	"github.com/vardius/go-api-boilerplate/pkg/auth/password"
+	"github.com/vardius/go-api-boilerplate/pkg/contract"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+// Password is a plain text password value object, it is never printed
+type Password string
+
+func (p Password) String() string {
+	return "[REDACTED]"
+}
+
+// GoString implements fmt.GoStringer interface
+func (p Password) GoString() string {
+	return p.String()
+}
+
+// JSONSchema implements contract.Describer interface
+func (p Password) JSONSchema() *contract.Schema {
+	return &contract.Schema{Type: "string", Format: "password"}
+}
+
+// PasswordHasher validates passwords against policy and hashes them
+type PasswordHasher struct {
+	Policy password.Policy
+	Params password.Params
+}
+
+// Hash returns argon2id hash of password meeting the policy
+func (h PasswordHasher) Hash(p Password) (string, error) {
+	if err := h.Policy.Validate(string(p)); err != nil {
+		return "", apperrors.Wrap(err)
+	}
+
+	hash, err := password.Hash(string(p), h.Params)
+	if err != nil {
+		return "", apperrors.Wrap(err)
+	}
+
+	return hash, nil
+}
@@ -0,0 +1,47 @@
+package user
+
+import (
+	"golang.org/x/text/language"
+)
+
+// Profile holds user public profile data
+type Profile struct {
+	Name      string `json:"name,omitempty" bson:"name,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty" bson:"avatar_url,omitempty"`
+	Locale    string `json:"locale,omitempty" bson:"locale,omitempty"`
+	Timezone  string `json:"timezone,omitempty" bson:"timezone,omitempty"`
+}
+
+// GetName the display name
+func (p Profile) GetName() string {
+	return p.Name
+}
+
+// GetAvatarURL the avatar url
+func (p Profile) GetAvatarURL() string {
+	return p.AvatarURL
+}
+
+// GetLocale the BCP 47 language tag
+func (p Profile) GetLocale() string {
+	return p.Locale
+}
+
+// GetTimezone the IANA time zone name
+func (p Profile) GetTimezone() string {
+	return p.Timezone
+}
+
+// socialProfile builds profile from data returned by identity provider,
+// locale is normalized to BCP 47 and dropped if provider sent value we can not parse
+func socialProfile(name, avatarURL, locale string) Profile {
+	p := Profile{
+		Name:      name,
+		AvatarURL: avatarURL,
+	}
+	if tag, err := language.Parse(locale); err == nil {
+		p.Locale = tag.String()
+	}
+
+	return p
+}
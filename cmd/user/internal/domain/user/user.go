@@ -5,14 +5,17 @@ package user
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/access"
+	"github.com/vardius/go-api-boilerplate/pkg/auth/password"
+	"github.com/vardius/go-api-boilerplate/pkg/auth/totp"
 	"github.com/vardius/go-api-boilerplate/pkg/domain"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
-	"github.com/vardius/go-api-boilerplate/pkg/identity"
-	"github.com/vardius/go-api-boilerplate/pkg/metadata"
 )
 
 // StreamName for user domain
@@ -20,67 +23,42 @@ var StreamName = fmt.Sprintf("%T", User{})
 
 // User aggregate root
 type User struct {
-	id      uuid.UUID
-	version int
-	changes []*domain.Event
-
-	email EmailAddress
+	domain.AggregateRoot
+
+	email        EmailAddress
+	passwordHash string
+	googleID     string
+	facebookID   string
+	providers    map[string]string // subjects by identity provider
+	verified     bool
+	verification verification
+	emailChange  emailChange
+	loginCodes   map[string]loginCode
+	profile      Profile
+	role         access.Role
+	mfa          mfa
+	deleteAt     time.Time
+	deleted      bool
 }
 
-// New creates an User
-func New() User {
-	return User{}
-}
-
-// FromHistory loads current aggregate root state by applying all events in order
-func FromHistory(ctx context.Context, events []*domain.Event) (User, error) {
-	u := New()
-
-	for _, domainEvent := range events {
-		var e domain.RawEvent
-
-		switch domainEvent.Type {
-		case AccessTokenWasRequestedType:
-			e = domainEvent.Payload.(*AccessTokenWasRequested)
-		case EmailAddressWasChangedType:
-			e = domainEvent.Payload.(*EmailAddressWasChanged)
-		case WasRegisteredWithEmailType:
-			e = domainEvent.Payload.(*WasRegisteredWithEmail)
-		case WasRegisteredWithFacebookType:
-			e = domainEvent.Payload.(*WasRegisteredWithFacebook)
-		case ConnectedWithFacebookType:
-			e = domainEvent.Payload.(*ConnectedWithFacebook)
-		case WasRegisteredWithGoogleType:
-			e = domainEvent.Payload.(*WasRegisteredWithGoogle)
-		case ConnectedWithGoogleType:
-			e = domainEvent.Payload.(*ConnectedWithGoogle)
-		default:
-			return u, apperrors.Wrap(fmt.Errorf("unhandled user event %s", domainEvent.Type))
-		}
-
-		if err := u.transition(e); err != nil {
-			return u, apperrors.Wrap(err)
-		}
-
-		u.version++
-	}
-
-	return u, nil
+type verification struct {
+	token       string
+	requestedAt time.Time
+	expiresAt   time.Time
 }
 
-// ID returns aggregate root id
-func (u User) ID() uuid.UUID {
-	return u.id
+// emailChange waiting for confirmation
+type emailChange struct {
+	email             EmailAddress
+	token             string
+	cancellationToken string
+	requestedAt       time.Time
+	expiresAt         time.Time
 }
 
-// Version returns current aggregate root version
-func (u User) Version() int {
-	return u.version
-}
-
-// Changes returns all new applied events
-func (u User) Changes() []*domain.Event {
-	return u.changes
+// New creates an User
+func New() User {
+	return User{AggregateRoot: domain.NewAggregateRoot(StreamName)}
 }
 
 // RegisterWithEmail alters current user state and append changes to aggregate root
@@ -90,204 +68,799 @@ func (u *User) RegisterWithEmail(ctx context.Context, id uuid.UUID, email EmailA
 		Email: email,
 	}
 
-	if err := u.transition(e); err != nil {
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	domainEvent, err := domain.NewEventFromRawEvent(u.id, StreamName, u.version, e)
-	if err != nil {
+	return nil
+}
+
+// RegisterWithGoogle alters current user state and append changes to aggregate root
+func (u *User) RegisterWithGoogle(ctx context.Context, id uuid.UUID, email EmailAddress, googleID, accessToken, redirectPath string, profile Profile) error {
+	e := &WasRegisteredWithGoogle{
+		ID:           id,
+		Email:        email,
+		GoogleID:     googleID,
+		AccessToken:  accessToken,
+		RedirectPath: redirectPath,
+		Profile:      profile,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	if err := u.trackChange(ctx, domainEvent); err != nil {
+	return nil
+}
+
+// ConnectWithGoogle alters current user state and append changes to aggregate root
+func (u *User) ConnectWithGoogle(ctx context.Context, googleID, accessToken, redirectPath string) error {
+	e := &ConnectedWithGoogle{
+		ID:           u.ID(),
+		GoogleID:     googleID,
+		AccessToken:  accessToken,
+		RedirectPath: redirectPath,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
 	return nil
 }
 
-// RegisterWithGoogle alters current user state and append changes to aggregate root
-func (u *User) RegisterWithGoogle(ctx context.Context, id uuid.UUID, email EmailAddress, googleID, accessToken, redirectPath string) error {
-	e := &WasRegisteredWithGoogle{
+// RegisterWithFacebook alters current user state and append changes to aggregate root
+func (u *User) RegisterWithFacebook(ctx context.Context, id uuid.UUID, email EmailAddress, facebookID, accessToken, redirectPath string, profile Profile) error {
+	e := &WasRegisteredWithFacebook{
 		ID:           id,
 		Email:        email,
-		GoogleID:     googleID,
+		FacebookID:   facebookID,
 		AccessToken:  accessToken,
 		RedirectPath: redirectPath,
+		Profile:      profile,
 	}
 
-	if err := u.transition(e); err != nil {
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	domainEvent, err := domain.NewEventFromRawEvent(u.id, StreamName, u.version, e)
-	if err != nil {
+	return nil
+}
+
+// ConnectWithFacebook alters current user state and append changes to aggregate root
+func (u *User) ConnectWithFacebook(ctx context.Context, facebookID, accessToken, redirectPath string) error {
+	e := &ConnectedWithFacebook{
+		ID:           u.ID(),
+		FacebookID:   facebookID,
+		AccessToken:  accessToken,
+		RedirectPath: redirectPath,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	if err := u.trackChange(ctx, domainEvent); err != nil {
+	return nil
+}
+
+// RegisterWithProvider alters current user state and append changes to aggregate root
+func (u *User) RegisterWithProvider(ctx context.Context, id uuid.UUID, email EmailAddress, emailVerified bool, provider, subject, accessToken, redirectPath string, profile Profile) error {
+	e := &WasRegisteredWithProvider{
+		ID:            id,
+		Email:         email,
+		EmailVerified: emailVerified,
+		Provider:      provider,
+		Subject:       subject,
+		AccessToken:   accessToken,
+		RedirectPath:  redirectPath,
+		Profile:       profile,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
 	return nil
 }
 
-// ConnectWithGoogle alters current user state and append changes to aggregate root
-func (u *User) ConnectWithGoogle(ctx context.Context, googleID, accessToken, redirectPath string) error {
-	e := &ConnectedWithGoogle{
-		ID:           u.id,
-		GoogleID:     googleID,
+// ConnectWithProvider connects identity provider account, user can be connected with one account of each provider
+func (u *User) ConnectWithProvider(ctx context.Context, provider, subject, accessToken, redirectPath string) error {
+	if connected, ok := u.providers[provider]; ok && connected != subject {
+		return apperrors.Wrap(fmt.Errorf("%w: user connected to another %s account", apperrors.ErrInvalid, provider))
+	}
+
+	e := &ConnectedWithProvider{
+		ID:           u.ID(),
+		Provider:     provider,
+		Subject:      subject,
 		AccessToken:  accessToken,
 		RedirectPath: redirectPath,
 	}
 
-	if err := u.transition(e); err != nil {
+	if err := domain.Record(ctx, u, e); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// DisconnectGoogle unlinks google account, disconnecting account that is not connected is a no-op
+func (u *User) DisconnectGoogle(ctx context.Context) error {
+	if u.googleID == "" {
+		return nil
+	}
+	if u.passwordHash == "" && u.facebookID == "" && len(u.providers) == 0 && !u.verified {
+		return apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrConflict, ErrLastLoginMethod))
+	}
+
+	e := &DisconnectedFromGoogle{
+		ID:       u.ID(),
+		GoogleID: u.googleID,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	domainEvent, err := domain.NewEventFromRawEvent(u.id, StreamName, u.version, e)
+	return nil
+}
+
+// DisconnectFacebook unlinks facebook account, disconnecting account that is not connected is a no-op
+func (u *User) DisconnectFacebook(ctx context.Context) error {
+	if u.facebookID == "" {
+		return nil
+	}
+	if u.passwordHash == "" && u.googleID == "" && len(u.providers) == 0 && !u.verified {
+		return apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrConflict, ErrLastLoginMethod))
+	}
+
+	e := &DisconnectedFromFacebook{
+		ID:         u.ID(),
+		FacebookID: u.facebookID,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// RequestEmailAddressChange issues tokens confirming change to new email address and cancelling it,
+// email address is changed once confirmed, new change can be requested once resend interval since previous one has passed
+func (u *User) RequestEmailAddressChange(ctx context.Context, email EmailAddress, policy VerificationPolicy, now time.Time) error {
+	if email == u.email {
+		return apperrors.Wrap(fmt.Errorf("%w: email address is already in use by this account", apperrors.ErrInvalid))
+	}
+	if !u.emailChange.requestedAt.IsZero() && now.Before(u.emailChange.requestedAt.Add(policy.ResendInterval)) {
+		return apperrors.Wrap(fmt.Errorf("%w: email address change was requested at %s", apperrors.ErrTooManyRequests, u.emailChange.requestedAt.Format(time.RFC3339)))
+	}
+
+	token, err := newVerificationToken()
 	if err != nil {
 		return apperrors.Wrap(err)
 	}
+	cancellationToken, err := newVerificationToken()
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	e := &EmailAddressChangeWasRequested{
+		ID:                u.ID(),
+		Email:             u.email,
+		NewEmail:          email,
+		Token:             token,
+		CancellationToken: cancellationToken,
+		RequestedAt:       now,
+		ExpiresAt:         now.Add(policy.TTL),
+	}
 
-	if err := u.trackChange(ctx, domainEvent); err != nil {
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
 	return nil
 }
 
-// RegisterWithFacebook alters current user state and append changes to aggregate root
-func (u *User) RegisterWithFacebook(ctx context.Context, id uuid.UUID, email EmailAddress, facebookID, accessToken, redirectPath string) error {
-	e := &WasRegisteredWithFacebook{
+// ConfirmEmailAddressChange changes email address if token matches the last one issued and has not expired,
+// new email address is verified by confirmation
+func (u *User) ConfirmEmailAddressChange(ctx context.Context, token string, now time.Time) error {
+	if u.emailChange.token == "" || subtle.ConstantTimeCompare([]byte(u.emailChange.token), []byte(token)) != 1 {
+		return apperrors.Wrap(fmt.Errorf("%w: invalid email address change token", apperrors.ErrInvalid))
+	}
+	if now.After(u.emailChange.expiresAt) {
+		return apperrors.Wrap(fmt.Errorf("%w: email address change token has expired", apperrors.ErrInvalid))
+	}
+
+	email := u.emailChange.email
+
+	if err := domain.Record(ctx, u, &EmailAddressWasChanged{
+		ID:    u.ID(),
+		Email: email,
+	}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.Record(ctx, u, &EmailAddressWasVerified{
+		ID:    u.ID(),
+		Email: email,
+	}); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// CancelEmailAddressChange discards pending email address change,
+// token is the cancellation token sent to current email address, it is empty when change is cancelled by signed in account owner
+func (u *User) CancelEmailAddressChange(ctx context.Context, token string) error {
+	if u.emailChange.email == "" {
+		return apperrors.Wrap(fmt.Errorf("%w: there is no pending email address change", apperrors.ErrConflict))
+	}
+	if token != "" && subtle.ConstantTimeCompare([]byte(u.emailChange.cancellationToken), []byte(token)) != 1 {
+		return apperrors.Wrap(fmt.Errorf("%w: invalid cancellation token", apperrors.ErrInvalid))
+	}
+
+	e := &EmailAddressChangeWasCancelled{
+		ID:       u.ID(),
+		NewEmail: u.emailChange.email,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// RegisterWithPassword alters current user state and append changes to aggregate root
+func (u *User) RegisterWithPassword(ctx context.Context, id uuid.UUID, email EmailAddress, passwordHash string) error {
+	e := &WasRegisteredWithPassword{
 		ID:           id,
 		Email:        email,
-		FacebookID:   facebookID,
-		AccessToken:  accessToken,
-		RedirectPath: redirectPath,
+		PasswordHash: passwordHash,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// ChangePassword verifies current password, if one was set, and replaces it with new password hash
+func (u *User) ChangePassword(ctx context.Context, currentPassword Password, passwordHash string) error {
+	if u.passwordHash != "" {
+		if err := password.Compare(u.passwordHash, string(currentPassword)); err != nil {
+			return apperrors.Wrap(fmt.Errorf("%w: current password does not match", apperrors.ErrForbidden))
+		}
+	}
+
+	e := &PasswordWasChanged{
+		ID:           u.ID(),
+		PasswordHash: passwordHash,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// LoginWithPassword verifies password and records successful login,
+// users with multi-factor authentication enabled are refused with ErrMFARequired until they pass challenge
+func (u *User) LoginWithPassword(ctx context.Context, plain Password) error {
+	if u.passwordHash == "" {
+		return apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrUnauthorized, ErrInvalidCredentials))
+	}
+	if err := password.Compare(u.passwordHash, string(plain)); err != nil {
+		return apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrUnauthorized, ErrInvalidCredentials))
+	}
+	if u.mfa.enabled {
+		return apperrors.Wrap(ErrMFARequired)
 	}
 
-	if err := u.transition(e); err != nil {
+	e := &LoggedInWithPassword{
+		ID:    u.ID(),
+		Email: u.email,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	domainEvent, err := domain.NewEventFromRawEvent(u.id, StreamName, u.version, e)
+	return nil
+}
+
+// RequestEmailVerification issues token verifying current email address,
+// new token can be requested once resend interval since previous one has passed
+func (u *User) RequestEmailVerification(ctx context.Context, policy VerificationPolicy, now time.Time) error {
+	if u.verified {
+		return apperrors.Wrap(fmt.Errorf("%w: email address is already verified", apperrors.ErrConflict))
+	}
+	if !u.verification.requestedAt.IsZero() && now.Before(u.verification.requestedAt.Add(policy.ResendInterval)) {
+		return apperrors.Wrap(fmt.Errorf("%w: verification was requested at %s", apperrors.ErrTooManyRequests, u.verification.requestedAt.Format(time.RFC3339)))
+	}
+
+	token, err := newVerificationToken()
 	if err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	if err := u.trackChange(ctx, domainEvent); err != nil {
+	e := &EmailVerificationWasRequested{
+		ID:          u.ID(),
+		Email:       u.email,
+		Token:       token,
+		RequestedAt: now,
+		ExpiresAt:   now.Add(policy.TTL),
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
 	return nil
 }
 
-// ConnectWithFacebook alters current user state and append changes to aggregate root
-func (u *User) ConnectWithFacebook(ctx context.Context, facebookID, accessToken, redirectPath string) error {
-	e := &ConnectedWithFacebook{
-		ID:           u.id,
-		FacebookID:   facebookID,
-		AccessToken:  accessToken,
-		RedirectPath: redirectPath,
+// VerifyEmailAddress marks current email address as verified if token matches the last one issued and has not expired
+func (u *User) VerifyEmailAddress(ctx context.Context, token string, now time.Time) error {
+	if u.verified {
+		return nil
+	}
+	if u.verification.token == "" || subtle.ConstantTimeCompare([]byte(u.verification.token), []byte(token)) != 1 {
+		return apperrors.Wrap(fmt.Errorf("%w: invalid verification token", apperrors.ErrInvalid))
+	}
+	if now.After(u.verification.expiresAt) {
+		return apperrors.Wrap(fmt.Errorf("%w: verification token has expired", apperrors.ErrInvalid))
+	}
+
+	e := &EmailAddressWasVerified{
+		ID:    u.ID(),
+		Email: u.email,
 	}
 
-	if err := u.transition(e); err != nil {
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	domainEvent, err := domain.NewEventFromRawEvent(u.id, StreamName, u.version, e)
-	if err != nil {
+	return nil
+}
+
+// UpdateProfile replaces user profile data, nothing is recorded if profile did not change
+func (u *User) UpdateProfile(ctx context.Context, profile Profile) error {
+	if u.profile == profile {
+		return nil
+	}
+
+	e := &ProfileWasUpdated{
+		ID:      u.ID(),
+		Profile: profile,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	if err := u.trackChange(ctx, domainEvent); err != nil {
+	return nil
+}
+
+// RequestDeletion schedules account deletion once grace period passes, until then deletion can be cancelled
+func (u *User) RequestDeletion(ctx context.Context, gracePeriod time.Duration, now time.Time) error {
+	if u.deleted {
+		return apperrors.Wrap(fmt.Errorf("%w: account was deleted", apperrors.ErrNotFound))
+	}
+	if !u.deleteAt.IsZero() {
+		return apperrors.Wrap(fmt.Errorf("%w: account deletion is already scheduled at %s", apperrors.ErrConflict, u.deleteAt.Format(time.RFC3339)))
+	}
+
+	e := &AccountDeletionWasRequested{
+		ID:          u.ID(),
+		Email:       u.email,
+		RequestedAt: now,
+		DeleteAt:    now.Add(gracePeriod),
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
 	return nil
 }
 
-// ChangeEmailAddress alters current user state and append changes to aggregate root
-func (u *User) ChangeEmailAddress(ctx context.Context, email EmailAddress) error {
-	e := &EmailAddressWasChanged{
-		ID:    u.id,
-		Email: email,
+// CancelDeletion cancels scheduled account deletion
+func (u *User) CancelDeletion(ctx context.Context) error {
+	if u.deleted {
+		return apperrors.Wrap(fmt.Errorf("%w: account was deleted", apperrors.ErrNotFound))
+	}
+	if u.deleteAt.IsZero() {
+		return apperrors.Wrap(fmt.Errorf("%w: account deletion was not requested", apperrors.ErrConflict))
+	}
+
+	e := &AccountDeletionWasCancelled{
+		ID: u.ID(),
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// Delete deletes account which deletion was requested and grace period has passed
+func (u *User) Delete(ctx context.Context, now time.Time) error {
+	if u.deleted {
+		return nil
+	}
+	if u.deleteAt.IsZero() {
+		return apperrors.Wrap(fmt.Errorf("%w: account deletion was not requested", apperrors.ErrConflict))
+	}
+	if now.Before(u.deleteAt) {
+		return apperrors.Wrap(fmt.Errorf("%w: account deletion is scheduled at %s", apperrors.ErrConflict, u.deleteAt.Format(time.RFC3339)))
+	}
+
+	e := &WasDeleted{
+		ID: u.ID(),
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// GrantRole grants role to user, granting role user already has is a no-op
+func (u *User) GrantRole(ctx context.Context, role access.Role) error {
+	if u.deleted {
+		return apperrors.Wrap(fmt.Errorf("%w: account was deleted", apperrors.ErrNotFound))
+	}
+	if u.role.Has(role) {
+		return nil
+	}
+
+	e := &RoleWasGranted{
+		ID:   u.ID(),
+		Role: role,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// RevokeRole revokes role from user, revoking role user does not have is a no-op
+func (u *User) RevokeRole(ctx context.Context, role access.Role) error {
+	if u.deleted {
+		return apperrors.Wrap(fmt.Errorf("%w: account was deleted", apperrors.ErrNotFound))
+	}
+	if !u.role.Has(role) {
+		return nil
+	}
+
+	e := &RoleWasRevoked{
+		ID:   u.ID(),
+		Role: role,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// StartMFAEnrolment stores secret one-time passwords will be generated with,
+// multi-factor authentication is enabled once user confirms it with valid code
+func (u *User) StartMFAEnrolment(ctx context.Context, secret string) error {
+	if u.mfa.enabled {
+		return apperrors.Wrap(fmt.Errorf("%w: multi-factor authentication is already enabled", apperrors.ErrConflict))
+	}
+	if _, err := totp.Code(secret, 0); err != nil {
+		return apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrInvalid, err))
+	}
+
+	e := &MFAEnrolmentWasStarted{
+		ID:     u.ID(),
+		Secret: secret,
 	}
 
-	if err := u.transition(e); err != nil {
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	domainEvent, err := domain.NewEventFromRawEvent(u.id, StreamName, u.version, e)
+	return nil
+}
+
+// EnableMFA confirms enrolment with one-time password generated with pending secret,
+// only hashes of recovery codes are recorded
+func (u *User) EnableMFA(ctx context.Context, code string, recoveryCodes []string, now time.Time) error {
+	if u.mfa.enabled {
+		return apperrors.Wrap(fmt.Errorf("%w: multi-factor authentication is already enabled", apperrors.ErrConflict))
+	}
+	if u.mfa.pendingSecret == "" {
+		return apperrors.Wrap(fmt.Errorf("%w: multi-factor authentication enrolment was not started", apperrors.ErrConflict))
+	}
+
+	step, err := totp.Validate(u.mfa.pendingSecret, code, now)
 	if err != nil {
+		return apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrInvalid, err))
+	}
+
+	hashes := make([]string, len(recoveryCodes))
+	for i, recoveryCode := range recoveryCodes {
+		hashes[i] = totp.HashRecoveryCode(recoveryCode)
+	}
+
+	e := &MFAWasEnabled{
+		ID:            u.ID(),
+		Step:          step,
+		RecoveryCodes: hashes,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	if err := u.trackChange(ctx, domainEvent); err != nil {
+	return nil
+}
+
+// DisableMFA disables multi-factor authentication, requires one-time password or recovery code
+func (u *User) DisableMFA(ctx context.Context, code string, now time.Time) error {
+	if !u.mfa.enabled {
+		return apperrors.Wrap(fmt.Errorf("%w: multi-factor authentication is not enabled", apperrors.ErrConflict))
+	}
+	if _, _, err := u.mfa.verify(code, now); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	e := &MFAWasDisabled{
+		ID: u.ID(),
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
 	return nil
 }
 
-// RequestAccessToken dispatches AccessTokenWasRequested event
-func (u *User) RequestAccessToken(ctx context.Context, redirectPath string) error {
-	e := &AccessTokenWasRequested{
-		ID:           u.id,
-		Email:        u.email,
-		RedirectPath: redirectPath,
+// PassMFAChallenge verifies one-time password or recovery code before access token is issued,
+// each one-time password and recovery code can be used once
+func (u *User) PassMFAChallenge(ctx context.Context, code string, now time.Time) error {
+	if !u.mfa.enabled {
+		return apperrors.Wrap(fmt.Errorf("%w: multi-factor authentication is not enabled", apperrors.ErrConflict))
 	}
 
-	if err := u.transition(e); err != nil {
+	step, recoveryCode, err := u.mfa.verify(code, now)
+	if err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	domainEvent, err := domain.NewEventFromRawEvent(u.id, StreamName, u.version, e)
+	e := &MFAChallengeWasPassed{
+		ID:           u.ID(),
+		Step:         step,
+		RecoveryCode: recoveryCode,
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// RequestAccessToken issues single-use login code to be sent by email and exchanged for access token
+func (u *User) RequestAccessToken(ctx context.Context, redirectPath string, policy LoginCodePolicy, now time.Time) error {
+	code, err := newLoginCode()
 	if err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	if err := u.trackChange(ctx, domainEvent); err != nil {
+	e := &AccessTokenWasRequested{
+		ID:           u.ID(),
+		Email:        u.email,
+		RedirectPath: redirectPath,
+		Code:         code,
+		RequestedAt:  now,
+		ExpiresAt:    now.Add(policy.TTL),
+	}
+
+	if err := domain.Record(ctx, u, e); err != nil {
 		return apperrors.Wrap(err)
 	}
 
 	return nil
 }
 
-func (u *User) trackChange(ctx context.Context, event *domain.Event) error {
-	var meta domain.EventMetadata
-	if i, hasIdentity := identity.FromContext(ctx); hasIdentity {
-		meta.Identity = i
+// UseLoginCode uses up login code before access token is issued, users with multi-factor authentication enabled
+// have to provide one-time password or recovery code as well, login code used again revokes all outstanding codes
+// as it might have leaked, ErrLoginCodeReused is returned after revocation is recorded
+func (u *User) UseLoginCode(ctx context.Context, code, otp string, now time.Time) error {
+	var (
+		loginCode loginCode
+		found     bool
+	)
+	for c, lc := range u.loginCodes {
+		if subtle.ConstantTimeCompare([]byte(c), []byte(code)) == 1 {
+			loginCode, found = lc, true
+		}
+	}
+
+	if !found {
+		return apperrors.Wrap(fmt.Errorf("%w: invalid login code", apperrors.ErrInvalid))
 	}
-	if m, ok := metadata.FromContext(ctx); ok {
-		meta.IPAddress = m.IPAddress
-		meta.UserAgent = m.UserAgent
-		meta.Referer = m.Referer
+	if loginCode.used {
+		if err := domain.Record(ctx, u, &LoginCodesWereRevoked{
+			ID: u.ID(),
+		}); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return apperrors.Wrap(ErrLoginCodeReused)
 	}
-	if !meta.IsEmpty() {
-		event.WithMetadata(&meta)
+	if now.After(loginCode.expiresAt) {
+		return apperrors.Wrap(fmt.Errorf("%w: login code has expired", apperrors.ErrInvalid))
 	}
 
-	u.changes = append(u.changes, event)
-	u.version++
+	var challenge *MFAChallengeWasPassed
+	if u.mfa.enabled {
+		if otp == "" {
+			return apperrors.Wrap(fmt.Errorf("%w: multi-factor authentication is required", apperrors.ErrUnauthorized))
+		}
+
+		step, recoveryCode, err := u.mfa.verify(otp, now)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		challenge = &MFAChallengeWasPassed{
+			ID:           u.ID(),
+			Step:         step,
+			RecoveryCode: recoveryCode,
+		}
+	}
+
+	if err := domain.Record(ctx, u, &LoginCodeWasUsed{
+		ID:   u.ID(),
+		Code: code,
+	}); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if challenge != nil {
+		if err := domain.Record(ctx, u, challenge); err != nil {
+			return apperrors.Wrap(err)
+		}
+	}
 
 	return nil
 }
 
-func (u *User) transition(e domain.RawEvent) error {
+// Apply alters current user state by event
+func (u *User) Apply(e domain.RawEvent) error {
 	switch e := e.(type) {
 	case *WasRegisteredWithEmail:
-		u.id = e.ID
+		u.SetID(e.ID)
+		u.role = e.GetRole()
 		u.email = e.Email
+		u.profile = e.Profile
 	case *WasRegisteredWithGoogle:
-		u.id = e.ID
+		u.SetID(e.ID)
+		u.role = e.GetRole()
 		u.email = e.Email
+		u.googleID = e.GoogleID
+		u.verified = true
+		u.profile = e.Profile
 	case *WasRegisteredWithFacebook:
-		u.id = e.ID
+		u.SetID(e.ID)
+		u.role = e.GetRole()
+		u.email = e.Email
+		u.facebookID = e.FacebookID
+		u.verified = true
+		u.profile = e.Profile
+	case *WasRegisteredWithProvider:
+		u.SetID(e.ID)
+		u.role = e.GetRole()
+		u.email = e.Email
+		u.providers = map[string]string{e.Provider: e.Subject}
+		u.verified = e.EmailVerified
+		u.profile = e.Profile
+	case *WasRegisteredWithPassword:
+		u.SetID(e.ID)
+		u.role = e.GetRole()
 		u.email = e.Email
+		u.passwordHash = e.PasswordHash
+		u.profile = e.Profile
+	case *ProfileWasUpdated:
+		u.profile = e.Profile
+	case *AccountDeletionWasRequested:
+		u.deleteAt = e.DeleteAt
+	case *AccountDeletionWasCancelled:
+		u.deleteAt = time.Time{}
+	case *WasDeleted:
+		u.deleted = true
+	case *RoleWasGranted:
+		u.role = u.role.Add(e.Role)
+	case *RoleWasRevoked:
+		u.role = u.role.Remove(e.Role)
+	case *MFAEnrolmentWasStarted:
+		u.mfa.pendingSecret = e.Secret
+	case *MFAWasEnabled:
+		u.mfa = mfa{enabled: true, secret: u.mfa.pendingSecret, lastStep: e.Step, recoveryCodes: e.RecoveryCodes}
+	case *MFAWasDisabled:
+		u.mfa = mfa{}
+	case *MFAChallengeWasPassed:
+		if e.Step > u.mfa.lastStep {
+			u.mfa.lastStep = e.Step
+		}
+		if e.RecoveryCode != "" {
+			u.mfa.recoveryCodes = u.mfa.withoutRecoveryCode(e.RecoveryCode)
+		}
 	case *EmailAddressWasChanged:
 		u.email = e.Email
+		u.verified = false
+		u.verification = verification{}
+		u.emailChange = emailChange{}
+	case *EmailAddressChangeWasRequested:
+		u.emailChange = emailChange{
+			email:             e.NewEmail,
+			token:             e.Token,
+			cancellationToken: e.CancellationToken,
+			requestedAt:       e.RequestedAt,
+			expiresAt:         e.ExpiresAt,
+		}
+	case *EmailAddressChangeWasCancelled:
+		u.emailChange = emailChange{requestedAt: u.emailChange.requestedAt} // cancelling does not reset resend interval
+	case *EmailVerificationWasRequested:
+		u.verification = verification{token: e.Token, requestedAt: e.RequestedAt, expiresAt: e.ExpiresAt}
+	case *EmailAddressWasVerified:
+		u.verified = true
+		u.verification = verification{}
+	case *PasswordWasChanged:
+		u.passwordHash = e.PasswordHash
+	case *ConnectedWithGoogle:
+		u.googleID = e.GoogleID
+	case *ConnectedWithFacebook:
+		u.facebookID = e.FacebookID
+	case *ConnectedWithProvider:
+		if u.providers == nil {
+			u.providers = make(map[string]string)
+		}
+		u.providers[e.Provider] = e.Subject
+	case *DisconnectedFromGoogle:
+		u.googleID = ""
+	case *DisconnectedFromFacebook:
+		u.facebookID = ""
+	case *AccessTokenWasRequested:
+		if e.Code == "" {
+			break
+		}
+		if u.loginCodes == nil {
+			u.loginCodes = make(map[string]loginCode)
+		}
+		// expired codes are forgotten, there is no point in detecting their reuse
+		for code, lc := range u.loginCodes {
+			if lc.expiresAt.Before(e.RequestedAt) {
+				delete(u.loginCodes, code)
+			}
+		}
+		u.loginCodes[e.Code] = loginCode{expiresAt: e.ExpiresAt}
+	case *LoginCodeWasUsed:
+		if lc, ok := u.loginCodes[e.Code]; ok {
+			lc.used = true
+			u.loginCodes[e.Code] = lc
+		}
+	case *LoginCodesWereRevoked:
+		for code, lc := range u.loginCodes {
+			if !lc.used {
+				delete(u.loginCodes, code)
+			}
+		}
+	case *LoggedInWithPassword:
+	default:
+		return fmt.Errorf("unhandled user event %T", e)
 	}
 
 	return nil
@@ -0,0 +1,24 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+// VerificationPolicy describes lifetime of email verification tokens
+type VerificationPolicy struct {
+	TTL            time.Duration // how long verification token is valid
+	ResendInterval time.Duration // how long user has to wait before requesting another token
+}
+
+// newVerificationToken returns random url safe token
+func newVerificationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("could not generate verification token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -1,16 +1,25 @@
 package memory
 
 import (
+	"time"
+
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/access"
 )
 
 // User model
 type User struct {
-	ID         string      `json:"id"`
-	Email      string      `json:"email"`
-	FacebookID string      `json:"facebook_id"`
-	GoogleID   string      `json:"google_id"`
-	Role       access.Role `json:"role"`
+	ID           string      `json:"id"`
+	Email        string      `json:"email"`
+	FacebookID   string      `json:"facebook_id"`
+	GoogleID     string      `json:"google_id"`
+	Role         access.Role `json:"role"`
+	Verified     bool        `json:"verified"`
+	Name         string      `json:"name"`
+	AvatarURL    string      `json:"avatar_url"`
+	Locale       string      `json:"locale"`
+	Timezone     string      `json:"timezone"`
+	MFAEnabled   bool        `json:"mfa_enabled"`
+	RegisteredAt time.Time   `json:"registered_at"`
 }
 
 // GetID the id
@@ -37,3 +46,38 @@ func (u User) GetGoogleID() string {
 func (u User) GetRole() access.Role {
 	return u.Role
 }
+
+// IsVerified returns true if user email address was verified
+func (u User) IsVerified() bool {
+	return u.Verified
+}
+
+// IsMFAEnabled returns true if user has multi-factor authentication enabled
+func (u User) IsMFAEnabled() bool {
+	return u.MFAEnabled
+}
+
+// GetName the display name
+func (u User) GetName() string {
+	return u.Name
+}
+
+// GetAvatarURL the avatar url
+func (u User) GetAvatarURL() string {
+	return u.AvatarURL
+}
+
+// GetLocale the BCP 47 language tag
+func (u User) GetLocale() string {
+	return u.Locale
+}
+
+// GetTimezone the IANA time zone name
+func (u User) GetTimezone() string {
+	return u.Timezone
+}
+
+// GetRegisteredAt the time user was registered at
+func (u User) GetRegisteredAt() time.Time {
+	return u.RegisteredAt
+}
@@ -5,42 +5,168 @@ package memory
 
 import (
 	"context"
-	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"sort"
+	"strings"
 	"sync"
+	"time"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/access"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
 )
 
 // NewUserRepository returns memory view model repository for user
 func NewUserRepository() persistence.UserRepository {
-	return &userRepository{users: make(map[string]persistence.User)}
+	return &userRepository{users: make(map[string]persistence.User), providers: make(map[providerKey]string)}
 }
 
 type userRepository struct {
 	sync.RWMutex
-	users map[string]persistence.User
+	users     map[string]persistence.User
+	providers map[providerKey]string // user ids by identity provider account
+}
+
+type providerKey struct {
+	provider string
+	subject  string
 }
 
-func (r *userRepository) FindAll(ctx context.Context, limit, offset int64) ([]persistence.User, error) {
+func (r *userRepository) FindAll(ctx context.Context, query persistence.UserQuery) ([]persistence.User, string, error) {
+	after, err := query.After()
+	if err != nil {
+		return nil, "", apperrors.Wrap(err)
+	}
+
 	r.RLock()
 	defer r.RUnlock()
 
-	var i int64
+	users := r.filter(query)
+	sort.Slice(users, func(i, j int) bool {
+		return follows(query, users[j], users[i].GetID(), sortValue(query.GetSort(), users[i]))
+	})
+
+	if after != nil {
+		value, err := cursorValue(after)
+		if err != nil {
+			return nil, "", apperrors.Wrap(err)
+		}
+
+		i := sort.Search(len(users), func(i int) bool {
+			return follows(query, users[i], after.ID, value)
+		})
+		users = users[i:]
+	}
+
+	if int64(len(users)) <= query.GetLimit() {
+		return users, "", nil
+	}
+
+	users = users[:query.GetLimit()]
+	nextCursor, err := query.NextCursor(users[len(users)-1])
+	if err != nil {
+		return nil, "", apperrors.Wrap(err)
+	}
+
+	return users, nextCursor, nil
+}
+
+// filter returns users matching query filters
+func (r *userRepository) filter(query persistence.UserQuery) []persistence.User {
+	providers := make(map[string]map[string]bool)
+	for k, id := range r.providers {
+		if providers[id] == nil {
+			providers[id] = make(map[string]bool)
+		}
+		providers[id][k.provider] = true
+	}
+
 	var users []persistence.User
 	for _, v := range r.users {
-		if i < offset {
+		if query.Email != "" && !strings.Contains(strings.ToLower(v.GetEmail()), strings.ToLower(query.Email)) {
+			continue
+		}
+		if query.Role != 0 && v.GetRole()&query.Role != query.Role {
+			continue
+		}
+		if !query.RegisteredAfter.IsZero() && !v.GetRegisteredAt().After(query.RegisteredAfter) {
+			continue
+		}
+		if !query.RegisteredBefore.IsZero() && !v.GetRegisteredAt().Before(query.RegisteredBefore) {
 			continue
 		}
-		i++
+
+		switch query.Provider {
+		case "":
+		case persistence.ProviderEmail:
+			if v.GetGoogleID() != "" || v.GetFacebookID() != "" || len(providers[v.GetID()]) > 0 {
+				continue
+			}
+		case persistence.ProviderGoogle:
+			if v.GetGoogleID() == "" {
+				continue
+			}
+		case persistence.ProviderFacebook:
+			if v.GetFacebookID() == "" {
+				continue
+			}
+		default:
+			if !providers[v.GetID()][query.Provider] {
+				continue
+			}
+		}
 
 		users = append(users, v)
+	}
+
+	return users
+}
 
-		if int64(len(users)) == limit {
-			return users, nil
+// sortValue returns value of user sort field, registration date is returned as time.Time
+func sortValue(field persistence.UserSort, u persistence.User) interface{} {
+	switch field {
+	case persistence.SortByEmail:
+		return u.GetEmail()
+	case persistence.SortByName:
+		return u.GetName()
+	default:
+		return u.GetRegisteredAt()
+	}
+}
+
+// cursorValue returns value of cursor sort field, same as sortValue of user cursor points at
+func cursorValue(c *persistence.Cursor) (interface{}, error) {
+	if c.Sort == persistence.SortByRegisteredAt {
+		return c.RegisteredAt()
+	}
+	return c.Value, nil
+}
+
+// follows returns true if user comes after user of given id and sort field value in query order
+func follows(query persistence.UserQuery, u persistence.User, id string, value interface{}) bool {
+	if query.Descending {
+		return compare(query.GetSort(), u, id, value) < 0
+	}
+	return compare(query.GetSort(), u, id, value) > 0
+}
+
+// compare compares user with sort field value and id in ascending order
+func compare(field persistence.UserSort, u persistence.User, id string, value interface{}) int {
+	switch v := sortValue(field, u).(type) {
+	case time.Time:
+		if t := value.(time.Time); !v.Equal(t) {
+			if v.Before(t) {
+				return -1
+			}
+			return 1
+		}
+	case string:
+		if c := strings.Compare(v, value.(string)); c != 0 {
+			return c
 		}
 	}
 
-	return users, nil
+	return strings.Compare(u.GetID(), id)
 }
 
 func (r *userRepository) Get(ctx context.Context, id string) (persistence.User, error) {
@@ -93,11 +219,24 @@ func (r *userRepository) GetByGoogleID(ctx context.Context, googleID string) (pe
 	return nil, apperrors.ErrNotFound
 }
 
-func (r *userRepository) Add(ctx context.Context, u persistence.User) error {
+func (r *userRepository) Add(ctx context.Context, u persistence.Registration, registeredAt time.Time) error {
 	r.Lock()
 	defer r.Unlock()
 
-	r.users[u.GetID()] = u
+	r.users[u.GetID()] = User{
+		ID:           u.GetID(),
+		Email:        u.GetEmail(),
+		FacebookID:   u.GetFacebookID(),
+		GoogleID:     u.GetGoogleID(),
+		Role:         u.GetRole(),
+		Verified:     u.IsVerified(),
+		Name:         u.GetName(),
+		AvatarURL:    u.GetAvatarURL(),
+		Locale:       u.GetLocale(),
+		Timezone:     u.GetTimezone(),
+		MFAEnabled:   u.IsMFAEnabled(),
+		RegisteredAt: registeredAt,
+	}
 	return nil
 }
 
@@ -111,11 +250,18 @@ func (r *userRepository) UpdateEmail(ctx context.Context, id, email string) erro
 	}
 
 	r.users[id] = User{
-		ID:         v.GetID(),
-		Email:      email,
-		FacebookID: v.GetFacebookID(),
-		GoogleID:   v.GetGoogleID(),
-		Role:       v.GetRole(),
+		ID:           v.GetID(),
+		Email:        email,
+		FacebookID:   v.GetFacebookID(),
+		GoogleID:     v.GetGoogleID(),
+		Role:         v.GetRole(),
+		Verified:     v.IsVerified(),
+		Name:         v.GetName(),
+		AvatarURL:    v.GetAvatarURL(),
+		Locale:       v.GetLocale(),
+		Timezone:     v.GetTimezone(),
+		MFAEnabled:   v.IsMFAEnabled(),
+		RegisteredAt: v.GetRegisteredAt(),
 	}
 
 	return nil
@@ -131,11 +277,18 @@ func (r *userRepository) UpdateFacebookID(ctx context.Context, id, facebookID st
 	}
 
 	r.users[id] = User{
-		ID:         v.GetID(),
-		Email:      v.GetEmail(),
-		FacebookID: facebookID,
-		GoogleID:   v.GetGoogleID(),
-		Role:       v.GetRole(),
+		ID:           v.GetID(),
+		Email:        v.GetEmail(),
+		FacebookID:   facebookID,
+		GoogleID:     v.GetGoogleID(),
+		Role:         v.GetRole(),
+		Verified:     v.IsVerified(),
+		Name:         v.GetName(),
+		AvatarURL:    v.GetAvatarURL(),
+		Locale:       v.GetLocale(),
+		Timezone:     v.GetTimezone(),
+		MFAEnabled:   v.IsMFAEnabled(),
+		RegisteredAt: v.GetRegisteredAt(),
 	}
 
 	return nil
@@ -151,11 +304,127 @@ func (r *userRepository) UpdateGoogleID(ctx context.Context, id, googleID string
 	}
 
 	r.users[id] = User{
-		ID:         v.GetID(),
-		Email:      v.GetEmail(),
-		FacebookID: v.GetFacebookID(),
-		GoogleID:   googleID,
-		Role:       v.GetRole(),
+		ID:           v.GetID(),
+		Email:        v.GetEmail(),
+		FacebookID:   v.GetFacebookID(),
+		GoogleID:     googleID,
+		Role:         v.GetRole(),
+		Verified:     v.IsVerified(),
+		Name:         v.GetName(),
+		AvatarURL:    v.GetAvatarURL(),
+		Locale:       v.GetLocale(),
+		Timezone:     v.GetTimezone(),
+		MFAEnabled:   v.IsMFAEnabled(),
+		RegisteredAt: v.GetRegisteredAt(),
+	}
+
+	return nil
+}
+
+func (r *userRepository) GetByProvider(ctx context.Context, provider, subject string) (persistence.User, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	id, ok := r.providers[providerKey{provider, subject}]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	v, ok := r.users[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return v, nil
+}
+
+func (r *userRepository) AddProvider(ctx context.Context, id, provider, subject string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.users[id]; !ok {
+		return apperrors.ErrNotFound
+	}
+
+	r.providers[providerKey{provider, subject}] = id
+
+	return nil
+}
+
+func (r *userRepository) UpdateVerified(ctx context.Context, id string, verified bool) error {
+	r.Lock()
+	defer r.Unlock()
+
+	v, ok := r.users[id]
+	if !ok {
+		return apperrors.ErrNotFound
+	}
+
+	r.users[id] = User{
+		ID:           v.GetID(),
+		Email:        v.GetEmail(),
+		FacebookID:   v.GetFacebookID(),
+		GoogleID:     v.GetGoogleID(),
+		Role:         v.GetRole(),
+		Verified:     verified,
+		Name:         v.GetName(),
+		AvatarURL:    v.GetAvatarURL(),
+		Locale:       v.GetLocale(),
+		Timezone:     v.GetTimezone(),
+		MFAEnabled:   v.IsMFAEnabled(),
+		RegisteredAt: v.GetRegisteredAt(),
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateMFAEnabled(ctx context.Context, id string, enabled bool) error {
+	r.Lock()
+	defer r.Unlock()
+
+	v, ok := r.users[id]
+	if !ok {
+		return apperrors.ErrNotFound
+	}
+
+	r.users[id] = User{
+		ID:           v.GetID(),
+		Email:        v.GetEmail(),
+		FacebookID:   v.GetFacebookID(),
+		GoogleID:     v.GetGoogleID(),
+		Role:         v.GetRole(),
+		Verified:     v.IsVerified(),
+		Name:         v.GetName(),
+		AvatarURL:    v.GetAvatarURL(),
+		Locale:       v.GetLocale(),
+		Timezone:     v.GetTimezone(),
+		MFAEnabled:   enabled,
+		RegisteredAt: v.GetRegisteredAt(),
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateProfile(ctx context.Context, id string, profile persistence.Profile) error {
+	r.Lock()
+	defer r.Unlock()
+
+	v, ok := r.users[id]
+	if !ok {
+		return apperrors.ErrNotFound
+	}
+
+	r.users[id] = User{
+		ID:           v.GetID(),
+		Email:        v.GetEmail(),
+		FacebookID:   v.GetFacebookID(),
+		GoogleID:     v.GetGoogleID(),
+		Role:         v.GetRole(),
+		Verified:     v.IsVerified(),
+		Name:         profile.GetName(),
+		AvatarURL:    profile.GetAvatarURL(),
+		Locale:       profile.GetLocale(),
+		Timezone:     profile.GetTimezone(),
+		MFAEnabled:   v.IsMFAEnabled(),
+		RegisteredAt: v.GetRegisteredAt(),
 	}
 
 	return nil
@@ -168,13 +437,53 @@ func (r *userRepository) Delete(ctx context.Context, id string) error {
 	if _, ok := r.users[id]; ok {
 		delete(r.users, id)
 	}
+	for k, userID := range r.providers {
+		if userID == id {
+			delete(r.providers, k)
+		}
+	}
 
 	return nil
 }
 
-func (r *userRepository) Count(ctx context.Context) (int64, error) {
+func (r *userRepository) Count(ctx context.Context, query persistence.UserQuery) (int64, error) {
 	r.RLock()
 	defer r.RUnlock()
 
-	return int64(len(r.users)), nil
+	return int64(len(r.filter(query))), nil
+}
+
+func (r *userRepository) AddRole(ctx context.Context, id string, role access.Role) error {
+	return r.updateRole(id, func(current access.Role) access.Role { return current.Add(role) })
+}
+
+func (r *userRepository) RemoveRole(ctx context.Context, id string, role access.Role) error {
+	return r.updateRole(id, func(current access.Role) access.Role { return current.Remove(role) })
+}
+
+func (r *userRepository) updateRole(id string, update func(current access.Role) access.Role) error {
+	r.Lock()
+	defer r.Unlock()
+
+	v, ok := r.users[id]
+	if !ok {
+		return apperrors.ErrNotFound
+	}
+
+	r.users[id] = User{
+		ID:           v.GetID(),
+		Email:        v.GetEmail(),
+		FacebookID:   v.GetFacebookID(),
+		GoogleID:     v.GetGoogleID(),
+		Role:         update(v.GetRole()),
+		Verified:     v.IsVerified(),
+		Name:         v.GetName(),
+		AvatarURL:    v.GetAvatarURL(),
+		Locale:       v.GetLocale(),
+		Timezone:     v.GetTimezone(),
+		MFAEnabled:   v.IsMFAEnabled(),
+		RegisteredAt: v.GetRegisteredAt(),
+	}
+
+	return nil
 }
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/access"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+func newTestRepository(t *testing.T) persistence.UserRepository {
+	t.Helper()
+
+	ctx := context.Background()
+	registeredAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	repository := NewUserRepository()
+
+	for i, u := range []User{
+		{ID: "1", Email: "jane@example.com", Name: "Jane", Role: access.RoleUser},
+		{ID: "2", Email: "john@example.com", Name: "John", Role: access.RoleUser | access.RoleAdmin, GoogleID: "g1"},
+		{ID: "3", Email: "anna@test.com", Name: "Anna", Role: access.RoleUser, FacebookID: "f1"},
+		{ID: "4", Email: "bob@example.com", Name: "Bob", Role: access.RoleUser},
+		{ID: "5", Email: "eve@test.com", Name: "Eve", Role: access.RoleUser | access.RoleAdmin},
+	} {
+		// users 4 and 5 are registered at the same time so they are sorted by id
+		at := registeredAt.Add(time.Duration(i) * time.Hour)
+		if i == 4 {
+			at = registeredAt.Add(3 * time.Hour)
+		}
+		if err := repository.Add(ctx, u, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repository.AddProvider(ctx, "5", "gitlab", "42"); err != nil {
+		t.Fatal(err)
+	}
+
+	return repository
+}
+
+func TestUserRepositoryFindAll(t *testing.T) {
+	registeredAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query persistence.UserQuery
+		want  []string
+	}{
+		{"all", persistence.UserQuery{}, []string{"1", "2", "3", "4", "5"}},
+		{"descending", persistence.UserQuery{Descending: true}, []string{"5", "4", "3", "2", "1"}},
+		{"by name", persistence.UserQuery{Sort: persistence.SortByName}, []string{"3", "4", "5", "1", "2"}},
+		{"email", persistence.UserQuery{Email: "EXAMPLE"}, []string{"1", "2", "4"}},
+		{"role", persistence.UserQuery{Role: access.RoleAdmin}, []string{"2", "5"}},
+		{"registered between", persistence.UserQuery{RegisteredAfter: registeredAt, RegisteredBefore: registeredAt.Add(3 * time.Hour)}, []string{"2", "3"}},
+		{"email provider", persistence.UserQuery{Provider: persistence.ProviderEmail}, []string{"1", "4"}},
+		{"google provider", persistence.UserQuery{Provider: persistence.ProviderGoogle}, []string{"2"}},
+		{"facebook provider", persistence.UserQuery{Provider: persistence.ProviderFacebook}, []string{"3"}},
+		{"identity provider", persistence.UserQuery{Provider: "gitlab"}, []string{"5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newTestRepository(t)
+
+			users, nextCursor, err := repository.FindAll(context.Background(), tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if nextCursor != "" {
+				t.Errorf("FindAll() next cursor = %q, want empty", nextCursor)
+			}
+			if got := ids(users); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAll() = %v, want %v", got, tt.want)
+			}
+
+			total, err := repository.Count(context.Background(), tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != int64(len(tt.want)) {
+				t.Errorf("Count() = %d, want %d", total, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestUserRepositoryFindAllPages(t *testing.T) {
+	for _, query := range []persistence.UserQuery{
+		{Limit: 2},
+		{Limit: 2, Descending: true},
+		{Limit: 2, Sort: persistence.SortByEmail},
+		{Limit: 2, Sort: persistence.SortByName, Descending: true},
+	} {
+		repository := newTestRepository(t)
+
+		all, _, err := repository.FindAll(context.Background(), persistence.UserQuery{Sort: query.Sort, Descending: query.Descending})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []persistence.User
+		for pages := 0; ; pages++ {
+			if pages > 3 {
+				t.Fatalf("FindAll(%+v) did not reach last page", query)
+			}
+
+			users, nextCursor, err := repository.FindAll(context.Background(), query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, users...)
+
+			if nextCursor == "" {
+				break
+			}
+			query.Cursor = nextCursor
+		}
+
+		if !reflect.DeepEqual(ids(got), ids(all)) {
+			t.Errorf("FindAll(%+v) pages = %v, want %v", query, ids(got), ids(all))
+		}
+	}
+}
+
+func TestUserRepositoryFindAllInvalidCursor(t *testing.T) {
+	repository := newTestRepository(t)
+
+	_, nextCursor, err := repository.FindAll(context.Background(), persistence.UserQuery{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, query := range []persistence.UserQuery{
+		{Cursor: "invalid"},
+		{Cursor: nextCursor, Sort: persistence.SortByEmail},
+		{Cursor: nextCursor, Descending: true},
+	} {
+		if _, _, err := repository.FindAll(context.Background(), query); !errors.Is(err, apperrors.ErrInvalid) {
+			t.Errorf("FindAll(%+v) error = %v, want %v", query, err, apperrors.ErrInvalid)
+		}
+	}
+}
+
+func ids(users []persistence.User) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.GetID()
+	}
+	return ids
+}
@@ -1,16 +1,32 @@
 package mongo
 
 import (
+	"time"
+
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/access"
 )
 
 // User model
 type User struct {
-	ID         string      `json:"id" bson:"user_id"`
-	Email      string      `json:"email" bson:"email_address"`
-	FacebookID string      `json:"facebook_id" bson:"facebook_id,omitempty"`
-	GoogleID   string      `json:"google_id" bson:"google_id,omitempty"`
-	Role       access.Role `json:"role" bson:"role"`
+	ID           string      `json:"id" bson:"user_id"`
+	Email        string      `json:"email" bson:"email_address"`
+	FacebookID   string      `json:"facebook_id" bson:"facebook_id,omitempty"`
+	GoogleID     string      `json:"google_id" bson:"google_id,omitempty"`
+	Role         access.Role `json:"role" bson:"role"`
+	Verified     bool        `json:"verified" bson:"verified"`
+	Name         string      `json:"name" bson:"name"`
+	AvatarURL    string      `json:"avatar_url" bson:"avatar_url"`
+	Locale       string      `json:"locale" bson:"locale"`
+	Timezone     string      `json:"timezone" bson:"timezone"`
+	MFAEnabled   bool        `json:"mfa_enabled" bson:"mfa_enabled"`
+	RegisteredAt time.Time   `json:"registered_at" bson:"registered_at"`
+	Providers    []Provider  `json:"providers,omitempty" bson:"providers,omitempty"`
+}
+
+// Provider account user is connected with
+type Provider struct {
+	Provider string `json:"provider" bson:"provider"`
+	Subject  string `json:"subject" bson:"subject"`
 }
 
 // GetID the id
@@ -37,3 +53,38 @@ func (u User) GetGoogleID() string {
 func (u User) GetRole() access.Role {
 	return u.Role
 }
+
+// IsVerified returns true if user email address was verified
+func (u User) IsVerified() bool {
+	return u.Verified
+}
+
+// IsMFAEnabled returns true if user has multi-factor authentication enabled
+func (u User) IsMFAEnabled() bool {
+	return u.MFAEnabled
+}
+
+// GetName the display name
+func (u User) GetName() string {
+	return u.Name
+}
+
+// GetAvatarURL the avatar url
+func (u User) GetAvatarURL() string {
+	return u.AvatarURL
+}
+
+// GetLocale the BCP 47 language tag
+func (u User) GetLocale() string {
+	return u.Locale
+}
+
+// GetTimezone the IANA time zone name
+func (u User) GetTimezone() string {
+	return u.Timezone
+}
+
+// GetRegisteredAt the time user was registered at
+func (u User) GetRegisteredAt() time.Time {
+	return u.RegisteredAt
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/access"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -18,8 +23,14 @@ func NewUserRepository(ctx context.Context, mongoDB *mongo.Database) (persistenc
 	if _, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
 		{Keys: bson.D{{Key: "email_address", Value: 1}}, Options: options.Index().SetUnique(true)},
-		{Keys: bson.D{{Key: "facebook_id", Value: 1}}, Options: options.Index().SetUnique(true)},
-		{Keys: bson.D{{Key: "google_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "facebook_id", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+		{Keys: bson.D{{Key: "google_id", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+		{Keys: bson.D{{Key: "registered_at", Value: 1}, {Key: "user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "name", Value: 1}, {Key: "user_id", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "providers.provider", Value: 1}, {Key: "providers.subject", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"providers": bson.M{"$exists": true}}),
+		},
 	}); err != nil {
 		return nil, apperrors.Wrap(err)
 	}
@@ -33,13 +44,40 @@ type userRepository struct {
 	collection *mongo.Collection
 }
 
-func (r *userRepository) FindAll(ctx context.Context, limit, offset int64) ([]persistence.User, error) {
-	findOptions := options.Find().SetLimit(limit).SetSkip(offset)
-	filter := bson.M{}
+func (r *userRepository) FindAll(ctx context.Context, query persistence.UserQuery) ([]persistence.User, string, error) {
+	after, err := query.After()
+	if err != nil {
+		return nil, "", apperrors.Wrap(err)
+	}
+
+	filter := filter(query)
+
+	field, order, op := sortFields[query.GetSort()], 1, "$gt"
+	if query.Descending {
+		order, op = -1, "$lt"
+	}
+	if after != nil {
+		var value interface{} = after.Value
+		if after.Sort == persistence.SortByRegisteredAt {
+			if value, err = after.RegisteredAt(); err != nil {
+				return nil, "", apperrors.Wrap(err)
+			}
+		}
+
+		// keyset pagination, users with equal sort values are ordered by id
+		filter["$or"] = bson.A{
+			bson.M{field: bson.M{op: value}},
+			bson.M{field: value, "user_id": bson.M{op: after.ID}},
+		}
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: field, Value: order}, {Key: "user_id", Value: order}}).
+		SetLimit(query.GetLimit() + 1) // one more user tells if there is next page
 
 	cur, err := r.collection.Find(ctx, filter, findOptions)
 	if err != nil {
-		return nil, apperrors.Wrap(err)
+		return nil, "", apperrors.Wrap(err)
 	}
 	defer cur.Close(ctx)
 
@@ -47,13 +85,27 @@ func (r *userRepository) FindAll(ctx context.Context, limit, offset int64) ([]pe
 	for cur.Next(ctx) {
 		var item User
 		if err := cur.Decode(&item); err != nil {
-			return nil, apperrors.Wrap(err)
+			return nil, "", apperrors.Wrap(err)
 		}
 
 		result = append(result, &item)
 	}
 
-	return result, nil
+	if err := cur.Err(); err != nil {
+		return nil, "", apperrors.Wrap(err)
+	}
+
+	if int64(len(result)) <= query.GetLimit() {
+		return result, "", nil
+	}
+
+	result = result[:query.GetLimit()]
+	nextCursor, err := query.NextCursor(result[len(result)-1])
+	if err != nil {
+		return nil, "", apperrors.Wrap(err)
+	}
+
+	return result, nextCursor, nil
 }
 
 func (r *userRepository) Get(ctx context.Context, id string) (persistence.User, error) {
@@ -120,12 +172,38 @@ func (r *userRepository) GetByGoogleID(ctx context.Context, googleID string) (pe
 	return &result, nil
 }
 
-func (r *userRepository) Add(ctx context.Context, u persistence.User) error {
+func (r *userRepository) GetByProvider(ctx context.Context, provider, subject string) (persistence.User, error) {
+	filter := bson.M{
+		"providers": bson.M{
+			"$elemMatch": bson.M{"provider": provider, "subject": subject},
+		},
+	}
+
+	var result User
+	if err := r.collection.FindOne(ctx, filter).Decode(&result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperrors.Wrap(fmt.Errorf("%s: %w", err, apperrors.ErrNotFound))
+		}
+		return nil, apperrors.Wrap(err)
+	}
+
+	return &result, nil
+}
+
+func (r *userRepository) Add(ctx context.Context, u persistence.Registration, registeredAt time.Time) error {
 	token := User{
-		ID:         u.GetID(),
-		Email:      u.GetEmail(),
-		FacebookID: u.GetFacebookID(),
-		GoogleID:   u.GetGoogleID(),
+		ID:           u.GetID(),
+		Email:        u.GetEmail(),
+		FacebookID:   u.GetFacebookID(),
+		GoogleID:     u.GetGoogleID(),
+		Role:         u.GetRole(),
+		Verified:     u.IsVerified(),
+		Name:         u.GetName(),
+		AvatarURL:    u.GetAvatarURL(),
+		Locale:       u.GetLocale(),
+		Timezone:     u.GetTimezone(),
+		MFAEnabled:   u.IsMFAEnabled(),
+		RegisteredAt: registeredAt,
 	}
 
 	if _, err := r.collection.InsertOne(ctx, token); err != nil {
@@ -165,6 +243,80 @@ func (r *userRepository) UpdateFacebookID(ctx context.Context, id, facebookID st
 			"facebook_id": facebookID,
 		},
 	}
+	if facebookID == "" {
+		// field is unset rather than emptied, unique index would not allow more than one empty value
+		update = bson.M{
+			"$unset": bson.M{
+				"facebook_id": "",
+			},
+		}
+	}
+	opt := options.FindOneAndUpdate().SetUpsert(false)
+
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opt).Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return apperrors.Wrap(fmt.Errorf("%s: %w", err, apperrors.ErrNotFound))
+		}
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateVerified(ctx context.Context, id string, verified bool) error {
+	filter := bson.M{
+		"user_id": id,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"verified": verified,
+		},
+	}
+	opt := options.FindOneAndUpdate().SetUpsert(false)
+
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opt).Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return apperrors.Wrap(fmt.Errorf("%s: %w", err, apperrors.ErrNotFound))
+		}
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateMFAEnabled(ctx context.Context, id string, enabled bool) error {
+	filter := bson.M{
+		"user_id": id,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"mfa_enabled": enabled,
+		},
+	}
+	opt := options.FindOneAndUpdate().SetUpsert(false)
+
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opt).Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return apperrors.Wrap(fmt.Errorf("%s: %w", err, apperrors.ErrNotFound))
+		}
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateProfile(ctx context.Context, id string, profile persistence.Profile) error {
+	filter := bson.M{
+		"user_id": id,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"name":       profile.GetName(),
+			"avatar_url": profile.GetAvatarURL(),
+			"locale":     profile.GetLocale(),
+			"timezone":   profile.GetTimezone(),
+		},
+	}
 	opt := options.FindOneAndUpdate().SetUpsert(false)
 
 	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opt).Err(); err != nil {
@@ -186,6 +338,35 @@ func (r *userRepository) UpdateGoogleID(ctx context.Context, id, googleID string
 			"google_id": googleID,
 		},
 	}
+	if googleID == "" {
+		// field is unset rather than emptied, unique index would not allow more than one empty value
+		update = bson.M{
+			"$unset": bson.M{
+				"google_id": "",
+			},
+		}
+	}
+	opt := options.FindOneAndUpdate().SetUpsert(false)
+
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opt).Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return apperrors.Wrap(fmt.Errorf("%s: %w", err, apperrors.ErrNotFound))
+		}
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) AddProvider(ctx context.Context, id, provider, subject string) error {
+	filter := bson.M{
+		"user_id": id,
+	}
+	update := bson.M{
+		"$addToSet": bson.M{
+			"providers": Provider{Provider: provider, Subject: subject},
+		},
+	}
 	opt := options.FindOneAndUpdate().SetUpsert(false)
 
 	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opt).Err(); err != nil {
@@ -210,11 +391,85 @@ func (r *userRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *userRepository) Count(ctx context.Context) (int64, error) {
-	total, err := r.collection.CountDocuments(ctx, bson.M{})
+func (r *userRepository) Count(ctx context.Context, query persistence.UserQuery) (int64, error) {
+	total, err := r.collection.CountDocuments(ctx, filter(query))
 	if err != nil {
 		return 0, apperrors.Wrap(err)
 	}
 
 	return total, nil
 }
+
+func (r *userRepository) AddRole(ctx context.Context, id string, role access.Role) error {
+	return r.updateRole(ctx, id, bson.M{"or": int32(role)})
+}
+
+func (r *userRepository) RemoveRole(ctx context.Context, id string, role access.Role) error {
+	return r.updateRole(ctx, id, bson.M{"and": int32(^role)})
+}
+
+func (r *userRepository) updateRole(ctx context.Context, id string, operation bson.M) error {
+	filter := bson.M{
+		"user_id": id,
+	}
+	update := bson.M{
+		"$bit": bson.M{
+			"role": operation,
+		},
+	}
+	opt := options.FindOneAndUpdate().SetUpsert(false)
+
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opt).Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return apperrors.Wrap(fmt.Errorf("%s: %w", err, apperrors.ErrNotFound))
+		}
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// sortFields by sort fields
+var sortFields = map[persistence.UserSort]string{
+	persistence.SortByRegisteredAt: "registered_at",
+	persistence.SortByEmail:        "email_address",
+	persistence.SortByName:         "name",
+}
+
+// filter returns filter document of query filters
+func filter(query persistence.UserQuery) bson.M {
+	filter := bson.M{}
+	if query.Email != "" {
+		filter["email_address"] = primitive.Regex{Pattern: regexp.QuoteMeta(query.Email), Options: "i"}
+	}
+	if query.Role != 0 {
+		filter["role"] = bson.M{"$bitsAllSet": int32(query.Role)}
+	}
+
+	registeredAt := bson.M{}
+	if !query.RegisteredAfter.IsZero() {
+		registeredAt["$gt"] = query.RegisteredAfter
+	}
+	if !query.RegisteredBefore.IsZero() {
+		registeredAt["$lt"] = query.RegisteredBefore
+	}
+	if len(registeredAt) > 0 {
+		filter["registered_at"] = registeredAt
+	}
+
+	switch query.Provider {
+	case "":
+	case persistence.ProviderEmail:
+		filter["facebook_id"] = bson.M{"$exists": false}
+		filter["google_id"] = bson.M{"$exists": false}
+		filter["providers.0"] = bson.M{"$exists": false}
+	case persistence.ProviderGoogle:
+		filter["google_id"] = bson.M{"$exists": true}
+	case persistence.ProviderFacebook:
+		filter["facebook_id"] = bson.M{"$exists": true}
+	default:
+		filter["providers.provider"] = query.Provider
+	}
+
+	return filter
+}
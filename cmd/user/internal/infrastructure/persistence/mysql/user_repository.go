@@ -8,7 +8,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/access"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	"github.com/vardius/go-api-boilerplate/pkg/mysql"
@@ -23,22 +26,75 @@ CREATE TABLE IF NOT EXISTS user_users
     email_address VARCHAR(255) COLLATE utf8_general_ci NOT NULL,
     facebook_id   VARCHAR(255) DEFAULT NULL,
     google_id     VARCHAR(255) DEFAULT NULL,
+    verified      BOOLEAN                              NOT NULL DEFAULT FALSE,
+    name          VARCHAR(255)                         NOT NULL DEFAULT '',
+    avatar_url    VARCHAR(2048)                        NOT NULL DEFAULT '',
+    locale        VARCHAR(35)                          NOT NULL DEFAULT '',
+    timezone      VARCHAR(64)                          NOT NULL DEFAULT '',
+    mfa_enabled   BOOLEAN                              NOT NULL DEFAULT FALSE,
+    registered_at DATETIME(6)                          NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
     PRIMARY KEY (distinct_id),
     UNIQUE KEY id (id),
     UNIQUE KEY email_address (email_address),
     INDEX i_facebook_id (facebook_id),
-    INDEX i_google_id (google_id)
+    INDEX i_google_id (google_id),
+    INDEX i_registered_at (registered_at, id),
+    INDEX i_name (name, id)
 )
     ENGINE = InnoDB
     DEFAULT CHARSET = utf8
     COLLATE = utf8_bin;
 `
 
+const createUserProvidersTableSQL = `
+CREATE TABLE IF NOT EXISTS user_providers
+(
+    user_id  CHAR(36)     NOT NULL,
+    provider VARCHAR(64)  NOT NULL,
+    subject  VARCHAR(255) NOT NULL,
+    PRIMARY KEY (provider, subject),
+    UNIQUE KEY user_provider (user_id, provider)
+)
+    ENGINE = InnoDB
+    DEFAULT CHARSET = utf8
+    COLLATE = utf8_bin;
+`
+
+// userColumns are added to user_users tables created before the column was introduced,
+// users registered before registered_at column was added are given time of migration
+var userColumns = []struct {
+	name       string
+	definition string
+}{
+	{"verified", "BOOLEAN NOT NULL DEFAULT FALSE"},
+	{"name", "VARCHAR(255) NOT NULL DEFAULT ''"},
+	{"avatar_url", "VARCHAR(2048) NOT NULL DEFAULT ''"},
+	{"locale", "VARCHAR(35) NOT NULL DEFAULT ''"},
+	{"timezone", "VARCHAR(64) NOT NULL DEFAULT ''"},
+	{"mfa_enabled", "BOOLEAN NOT NULL DEFAULT FALSE"},
+	{"registered_at", "DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)"},
+}
+
+// userIndexes are added to user_users tables created before the index was introduced
+var userIndexes = []struct {
+	name    string
+	columns string
+}{
+	{"i_registered_at", "(registered_at, id)"},
+	{"i_name", "(name, id)"},
+}
+
 // NewUserRepository returns mysql view model repository for user
 func NewUserRepository(ctx context.Context, db *sql.DB) (persistence.UserRepository, error) {
 	if _, err := db.ExecContext(ctx, createUsersTableSQL); err != nil {
 		return nil, apperrors.Wrap(err)
 	}
+	if err := migrateUsersTable(ctx, db); err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	if _, err := db.ExecContext(ctx, createUserProvidersTableSQL); err != nil {
+		return nil, apperrors.Wrap(err)
+	}
 
 	return &userRepository{db}, nil
 }
@@ -47,10 +103,67 @@ type userRepository struct {
 	db *sql.DB
 }
 
-func (r *userRepository) FindAll(ctx context.Context, limit, offset int64) ([]persistence.User, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, email_address, role, facebook_id, google_id FROM user_users ORDER BY distinct_id ASC LIMIT ? OFFSET ?`, limit, offset)
+// migrateUsersTable adds missing columns and indexes, mysql has no ADD COLUMN IF NOT EXISTS
+// so information schema is checked first, migration can be safely run on every start
+func migrateUsersTable(ctx context.Context, db *sql.DB) error {
+	for _, c := range userColumns {
+		var n int
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'user_users' AND COLUMN_NAME = ?`, c.name).Scan(&n); err != nil {
+			return apperrors.Wrap(err)
+		}
+		if n > 0 {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE user_users ADD COLUMN %s %s`, c.name, c.definition)); err != nil {
+			return apperrors.Wrap(fmt.Errorf("could not add column %s: %w", c.name, err))
+		}
+	}
+
+	for _, i := range userIndexes {
+		var n int
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'user_users' AND INDEX_NAME = ?`, i.name).Scan(&n); err != nil {
+			return apperrors.Wrap(err)
+		}
+		if n > 0 {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE user_users ADD INDEX %s %s`, i.name, i.columns)); err != nil {
+			return apperrors.Wrap(fmt.Errorf("could not add index %s: %w", i.name, err))
+		}
+	}
+
+	return nil
+}
+
+func (r *userRepository) FindAll(ctx context.Context, query persistence.UserQuery) ([]persistence.User, string, error) {
+	after, err := query.After()
 	if err != nil {
-		return nil, apperrors.Wrap(err)
+		return nil, "", apperrors.Wrap(err)
+	}
+
+	conditions, args := filter(query)
+
+	column, order, op := sortColumns[query.GetSort()], "ASC", ">"
+	if query.Descending {
+		order, op = "DESC", "<"
+	}
+	if after != nil {
+		var value interface{} = after.Value
+		if after.Sort == persistence.SortByRegisteredAt {
+			if value, err = after.RegisteredAt(); err != nil {
+				return nil, "", apperrors.Wrap(err)
+			}
+		}
+
+		// keyset pagination, users with equal sort values are ordered by id
+		conditions = append(conditions, fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, op))
+		args = append(args, value, value, after.ID)
+	}
+
+	args = append(args, query.GetLimit()+1) // one more user tells if there is next page
+	rows, err := r.db.QueryContext(ctx, `SELECT id, email_address, role, facebook_id, google_id, verified, name, avatar_url, locale, timezone, mfa_enabled, registered_at FROM user_users`+where(conditions)+fmt.Sprintf(` ORDER BY %[1]s %[2]s, id %[2]s LIMIT ?`, column, order), args...)
+	if err != nil {
+		return nil, "", apperrors.Wrap(err)
 	}
 	defer rows.Close()
 
@@ -58,26 +171,36 @@ func (r *userRepository) FindAll(ctx context.Context, limit, offset int64) ([]pe
 
 	for rows.Next() {
 		var user User
-		if err := rows.Scan(&user.ID, &user.Email, &user.Role, &user.FacebookID, &user.GoogleID); err != nil {
-			return nil, apperrors.Wrap(err)
+		if err := rows.Scan(&user.ID, &user.Email, &user.Role, &user.FacebookID, &user.GoogleID, &user.Verified, &user.Name, &user.AvatarURL, &user.Locale, &user.Timezone, &user.MFAEnabled, &user.RegisteredAt); err != nil {
+			return nil, "", apperrors.Wrap(err)
 		}
 
 		users = append(users, user)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, apperrors.Wrap(err)
+		return nil, "", apperrors.Wrap(err)
+	}
+
+	if int64(len(users)) <= query.GetLimit() {
+		return users, "", nil
 	}
 
-	return users, nil
+	users = users[:query.GetLimit()]
+	nextCursor, err := query.NextCursor(users[len(users)-1])
+	if err != nil {
+		return nil, "", apperrors.Wrap(err)
+	}
+
+	return users, nextCursor, nil
 }
 
 func (r *userRepository) Get(ctx context.Context, id string) (persistence.User, error) {
-	row := r.db.QueryRowContext(ctx, `SELECT id, email_address, role, facebook_id, google_id FROM user_users WHERE id=? LIMIT 1`, id)
+	row := r.db.QueryRowContext(ctx, `SELECT id, email_address, role, facebook_id, google_id, verified, name, avatar_url, locale, timezone, mfa_enabled, registered_at FROM user_users WHERE id=? LIMIT 1`, id)
 
 	var user User
 
-	err := row.Scan(&user.ID, &user.Email, &user.Role, &user.FacebookID, &user.GoogleID)
+	err := row.Scan(&user.ID, &user.Email, &user.Role, &user.FacebookID, &user.GoogleID, &user.Verified, &user.Name, &user.AvatarURL, &user.Locale, &user.Timezone, &user.MFAEnabled, &user.RegisteredAt)
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return nil, apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrNotFound, err))
@@ -89,11 +212,11 @@ func (r *userRepository) Get(ctx context.Context, id string) (persistence.User,
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (persistence.User, error) {
-	row := r.db.QueryRowContext(ctx, `SELECT id, email_address, role, facebook_id, google_id FROM user_users WHERE email_address=? LIMIT 1`, email)
+	row := r.db.QueryRowContext(ctx, `SELECT id, email_address, role, facebook_id, google_id, verified, name, avatar_url, locale, timezone, mfa_enabled, registered_at FROM user_users WHERE email_address=? LIMIT 1`, email)
 
 	var user User
 
-	err := row.Scan(&user.ID, &user.Email, &user.Role, &user.FacebookID, &user.GoogleID)
+	err := row.Scan(&user.ID, &user.Email, &user.Role, &user.FacebookID, &user.GoogleID, &user.Verified, &user.Name, &user.AvatarURL, &user.Locale, &user.Timezone, &user.MFAEnabled, &user.RegisteredAt)
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return nil, apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrNotFound, err))
@@ -105,11 +228,11 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (persiste
 }
 
 func (r *userRepository) GetByFacebookID(ctx context.Context, facebookID string) (persistence.User, error) {
-	row := r.db.QueryRowContext(ctx, `SELECT id, email_address, role, facebook_id, google_id FROM user_users WHERE facebook_id=? LIMIT 1`, facebookID)
+	row := r.db.QueryRowContext(ctx, `SELECT id, email_address, role, facebook_id, google_id, verified, name, avatar_url, locale, timezone, mfa_enabled, registered_at FROM user_users WHERE facebook_id=? LIMIT 1`, facebookID)
 
 	var user User
 
-	err := row.Scan(&user.ID, &user.Email, &user.Role, &user.FacebookID, &user.GoogleID)
+	err := row.Scan(&user.ID, &user.Email, &user.Role, &user.FacebookID, &user.GoogleID, &user.Verified, &user.Name, &user.AvatarURL, &user.Locale, &user.Timezone, &user.MFAEnabled, &user.RegisteredAt)
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return nil, apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrNotFound, err))
@@ -121,11 +244,27 @@ func (r *userRepository) GetByFacebookID(ctx context.Context, facebookID string)
 }
 
 func (r *userRepository) GetByGoogleID(ctx context.Context, googleID string) (persistence.User, error) {
-	row := r.db.QueryRowContext(ctx, `SELECT id, email_address, role, facebook_id, google_id FROM user_users WHERE google_id=? LIMIT 1`, googleID)
+	row := r.db.QueryRowContext(ctx, `SELECT id, email_address, role, facebook_id, google_id, verified, name, avatar_url, locale, timezone, mfa_enabled, registered_at FROM user_users WHERE google_id=? LIMIT 1`, googleID)
+
+	var user User
+
+	err := row.Scan(&user.ID, &user.Email, &user.Role, &user.FacebookID, &user.GoogleID, &user.Verified, &user.Name, &user.AvatarURL, &user.Locale, &user.Timezone, &user.MFAEnabled, &user.RegisteredAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrNotFound, err))
+	case err != nil:
+		return nil, apperrors.Wrap(err)
+	default:
+		return user, nil
+	}
+}
+
+func (r *userRepository) GetByProvider(ctx context.Context, provider, subject string) (persistence.User, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT u.id, u.email_address, u.role, u.facebook_id, u.google_id, u.verified, u.name, u.avatar_url, u.locale, u.timezone, u.mfa_enabled, u.registered_at FROM user_users u JOIN user_providers p ON p.user_id=u.id WHERE p.provider=? AND p.subject=? LIMIT 1`, provider, subject)
 
 	var user User
 
-	err := row.Scan(&user.ID, &user.Email, &user.Role, &user.FacebookID, &user.GoogleID)
+	err := row.Scan(&user.ID, &user.Email, &user.Role, &user.FacebookID, &user.GoogleID, &user.Verified, &user.Name, &user.AvatarURL, &user.Locale, &user.Timezone, &user.MFAEnabled, &user.RegisteredAt)
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return nil, apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrNotFound, err))
@@ -136,7 +275,7 @@ func (r *userRepository) GetByGoogleID(ctx context.Context, googleID string) (pe
 	}
 }
 
-func (r *userRepository) Add(ctx context.Context, u persistence.User) error {
+func (r *userRepository) Add(ctx context.Context, u persistence.Registration, registeredAt time.Time) error {
 	user := User{
 		ID:    u.GetID(),
 		Email: u.GetEmail(),
@@ -148,15 +287,23 @@ func (r *userRepository) Add(ctx context.Context, u persistence.User) error {
 			String: u.GetGoogleID(),
 			Valid:  u.GetGoogleID() != "",
 		}},
+		Role:         u.GetRole(),
+		Verified:     u.IsVerified(),
+		Name:         u.GetName(),
+		AvatarURL:    u.GetAvatarURL(),
+		Locale:       u.GetLocale(),
+		Timezone:     u.GetTimezone(),
+		MFAEnabled:   u.IsMFAEnabled(),
+		RegisteredAt: registeredAt,
 	}
 
-	stmt, err := r.db.PrepareContext(ctx, `INSERT IGNORE INTO user_users (id, email_address, role, facebook_id, google_id) VALUES (?,?,?,?,?)`)
+	stmt, err := r.db.PrepareContext(ctx, `INSERT IGNORE INTO user_users (id, email_address, role, facebook_id, google_id, verified, name, avatar_url, locale, timezone, mfa_enabled, registered_at) VALUES (?,?,?,?,?,?,?,?,?,?,?,?)`)
 	if err != nil {
 		return apperrors.Wrap(err)
 	}
 	defer stmt.Close()
 
-	if _, err := stmt.ExecContext(ctx, user.ID, user.Email, user.Role, user.FacebookID, user.GoogleID); err != nil {
+	if _, err := stmt.ExecContext(ctx, user.ID, user.Email, user.Role, user.FacebookID, user.GoogleID, user.Verified, user.Name, user.AvatarURL, user.Locale, user.Timezone, user.MFAEnabled, user.RegisteredAt); err != nil {
 		return apperrors.Wrap(err)
 	}
 
@@ -194,7 +341,8 @@ func (r *userRepository) UpdateFacebookID(ctx context.Context, id, facebookID st
 	}
 	defer stmt.Close()
 
-	result, err := stmt.ExecContext(ctx, facebookID, id)
+	// empty id is stored as NULL so disconnected accounts are not matched by lookups
+	result, err := stmt.ExecContext(ctx, sql.NullString{String: facebookID, Valid: facebookID != ""}, id)
 	if err != nil {
 		return apperrors.Wrap(err)
 	}
@@ -211,14 +359,60 @@ func (r *userRepository) UpdateFacebookID(ctx context.Context, id, facebookID st
 	return nil
 }
 
+func (r *userRepository) UpdateVerified(ctx context.Context, id string, verified bool) error {
+	stmt, err := r.db.PrepareContext(ctx, `UPDATE user_users SET verified=? WHERE id=?`)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+	defer stmt.Close()
+
+	// rows are not checked as mysql does not count rows with unchanged flag as affected
+	if _, err := stmt.ExecContext(ctx, verified, id); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateMFAEnabled(ctx context.Context, id string, enabled bool) error {
+	stmt, err := r.db.PrepareContext(ctx, `UPDATE user_users SET mfa_enabled=? WHERE id=?`)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+	defer stmt.Close()
+
+	// rows are not checked as mysql does not count rows with unchanged flag as affected
+	if _, err := stmt.ExecContext(ctx, enabled, id); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateProfile(ctx context.Context, id string, profile persistence.Profile) error {
+	stmt, err := r.db.PrepareContext(ctx, `UPDATE user_users SET name=?, avatar_url=?, locale=?, timezone=? WHERE id=?`)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+	defer stmt.Close()
+
+	// rows are not checked as mysql does not count rows with unchanged values as affected
+	if _, err := stmt.ExecContext(ctx, profile.GetName(), profile.GetAvatarURL(), profile.GetLocale(), profile.GetTimezone(), id); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
 func (r *userRepository) UpdateGoogleID(ctx context.Context, id, googleID string) error {
-	stmt, err := r.db.PrepareContext(ctx, `UPDATE users SET google_id=? WHERE id=?`)
+	stmt, err := r.db.PrepareContext(ctx, `UPDATE user_users SET google_id=? WHERE id=?`)
 	if err != nil {
 		return apperrors.Wrap(err)
 	}
 	defer stmt.Close()
 
-	result, err := stmt.ExecContext(ctx, googleID, id)
+	// empty id is stored as NULL so disconnected accounts are not matched by lookups
+	result, err := stmt.ExecContext(ctx, sql.NullString{String: googleID, Valid: googleID != ""}, id)
 	if err != nil {
 		return apperrors.Wrap(err)
 	}
@@ -235,7 +429,25 @@ func (r *userRepository) UpdateGoogleID(ctx context.Context, id, googleID string
 	return nil
 }
 
+func (r *userRepository) AddProvider(ctx context.Context, id, provider, subject string) error {
+	stmt, err := r.db.PrepareContext(ctx, `INSERT IGNORE INTO user_providers (user_id, provider, subject) VALUES (?,?,?)`)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, id, provider, subject); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
 func (r *userRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM user_providers WHERE user_id=?`, id); err != nil {
+		return apperrors.Wrap(err)
+	}
+
 	stmt, err := r.db.PrepareContext(ctx, `DELETE FROM user_users WHERE id=?`)
 	if err != nil {
 		return apperrors.Wrap(err)
@@ -259,13 +471,95 @@ func (r *userRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *userRepository) Count(ctx context.Context) (int64, error) {
+func (r *userRepository) Count(ctx context.Context, query persistence.UserQuery) (int64, error) {
 	var totalUsers int64
 
-	row := r.db.QueryRowContext(ctx, `SELECT COUNT(distinct_id) FROM user_users`)
+	conditions, args := filter(query)
+
+	row := r.db.QueryRowContext(ctx, `SELECT COUNT(distinct_id) FROM user_users`+where(conditions), args...)
 	if err := row.Scan(&totalUsers); err != nil {
 		return 0, apperrors.Wrap(err)
 	}
 
 	return totalUsers, nil
 }
+
+func (r *userRepository) AddRole(ctx context.Context, id string, role access.Role) error {
+	stmt, err := r.db.PrepareContext(ctx, `UPDATE user_users SET role=role | ? WHERE id=?`)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, uint8(role), id); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) RemoveRole(ctx context.Context, id string, role access.Role) error {
+	stmt, err := r.db.PrepareContext(ctx, `UPDATE user_users SET role=role & ? WHERE id=?`)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, uint8(^role), id); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// sortColumns by sort fields
+var sortColumns = map[persistence.UserSort]string{
+	persistence.SortByRegisteredAt: "registered_at",
+	persistence.SortByEmail:        "email_address",
+	persistence.SortByName:         "name",
+}
+
+// likeEscaper escapes wildcards of LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// filter returns conditions and arguments of query filters
+func filter(query persistence.UserQuery) (conditions []string, args []interface{}) {
+	if query.Email != "" {
+		conditions = append(conditions, "email_address LIKE ?") // case insensitive as of column collation
+		args = append(args, "%"+likeEscaper.Replace(query.Email)+"%")
+	}
+	if query.Role != 0 {
+		conditions = append(conditions, "role & ? = ?")
+		args = append(args, uint8(query.Role), uint8(query.Role))
+	}
+	if !query.RegisteredAfter.IsZero() {
+		conditions = append(conditions, "registered_at > ?")
+		args = append(args, query.RegisteredAfter)
+	}
+	if !query.RegisteredBefore.IsZero() {
+		conditions = append(conditions, "registered_at < ?")
+		args = append(args, query.RegisteredBefore)
+	}
+
+	switch query.Provider {
+	case "":
+	case persistence.ProviderEmail:
+		conditions = append(conditions, "facebook_id IS NULL AND google_id IS NULL AND NOT EXISTS (SELECT 1 FROM user_providers p WHERE p.user_id=user_users.id)")
+	case persistence.ProviderGoogle:
+		conditions = append(conditions, "google_id IS NOT NULL")
+	case persistence.ProviderFacebook:
+		conditions = append(conditions, "facebook_id IS NOT NULL")
+	default:
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM user_providers p WHERE p.user_id=user_users.id AND p.provider=?)")
+		args = append(args, query.Provider)
+	}
+
+	return conditions, args
+}
+
+func where(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
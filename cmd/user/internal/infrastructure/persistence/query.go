@@ -0,0 +1,142 @@
+package persistence
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/access"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+// Users listing limits
+const (
+	DefaultUserLimit int64 = 20
+	MaxUserLimit     int64 = 100
+)
+
+// Account providers users can be filtered by, any other value matches users connected with identity provider of that name
+const (
+	ProviderEmail    = "email" // users without social accounts
+	ProviderGoogle   = "google"
+	ProviderFacebook = "facebook"
+)
+
+// UserSort field users are sorted by, users with equal values are sorted by id
+type UserSort string
+
+// Fields users can be sorted by
+const (
+	SortByRegisteredAt UserSort = "registered_at"
+	SortByEmail        UserSort = "email"
+	SortByName         UserSort = "name"
+)
+
+// ParseUserSort parses sort field, prefixed with minus sign when sorting in descending order (e.g. -registered_at)
+func ParseUserSort(s string) (sort UserSort, descending bool, err error) {
+	if s == "" {
+		return SortByRegisteredAt, false, nil
+	}
+
+	descending = strings.HasPrefix(s, "-")
+	switch sort = UserSort(strings.TrimPrefix(s, "-")); sort {
+	case SortByRegisteredAt, SortByEmail, SortByName:
+		return sort, descending, nil
+	default:
+		return "", false, apperrors.Wrap(fmt.Errorf("%w: unknown sort field %s", apperrors.ErrInvalid, s))
+	}
+}
+
+// UserQuery filters, sorts and pages users, zero values do not filter
+type UserQuery struct {
+	Email            string      // substring of email address
+	Provider         string      // see ProviderEmail, ProviderGoogle and ProviderFacebook
+	Role             access.Role // users granted all of given roles
+	RegisteredAfter  time.Time
+	RegisteredBefore time.Time
+	Sort             UserSort
+	Descending       bool
+	Limit            int64
+	Cursor           string // opaque token of the next page returned by previous query
+}
+
+// GetSort returns sort field, users are sorted by registration date by default
+func (q UserQuery) GetSort() UserSort {
+	if q.Sort == "" {
+		return SortByRegisteredAt
+	}
+	return q.Sort
+}
+
+// GetLimit returns page size bounded by MaxUserLimit
+func (q UserQuery) GetLimit() int64 {
+	switch {
+	case q.Limit < 1:
+		return DefaultUserLimit
+	case q.Limit > MaxUserLimit:
+		return MaxUserLimit
+	default:
+		return q.Limit
+	}
+}
+
+// Cursor points at the last user of previous page, next page starts after it
+type Cursor struct {
+	Sort       UserSort `json:"s"`
+	Descending bool     `json:"d,omitempty"`
+	Value      string   `json:"v"`
+	ID         string   `json:"id"`
+}
+
+// RegisteredAt returns value of cursor sorted by registration date
+func (c Cursor) RegisteredAt() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return time.Time{}, apperrors.Wrap(fmt.Errorf("%w: invalid cursor: %s", apperrors.ErrInvalid, err))
+	}
+	return t, nil
+}
+
+// After decodes cursor of the query, it returns nil when query starts from the first page
+func (q UserQuery) After() (*Cursor, error) {
+	if q.Cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return nil, apperrors.Wrap(fmt.Errorf("%w: invalid cursor: %s", apperrors.ErrInvalid, err))
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, apperrors.Wrap(fmt.Errorf("%w: invalid cursor: %s", apperrors.ErrInvalid, err))
+	}
+	if c.Sort != q.GetSort() || c.Descending != q.Descending {
+		return nil, apperrors.Wrap(fmt.Errorf("%w: cursor does not match sort order", apperrors.ErrInvalid))
+	}
+
+	return &c, nil
+}
+
+// NextCursor returns opaque token of the page starting after given user
+func (q UserQuery) NextCursor(u User) (string, error) {
+	c := Cursor{Sort: q.GetSort(), Descending: q.Descending, ID: u.GetID()}
+	switch c.Sort {
+	case SortByEmail:
+		c.Value = u.GetEmail()
+	case SortByName:
+		c.Value = u.GetName()
+	default:
+		c.Value = u.GetRegisteredAt().UTC().Format(time.RFC3339Nano)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", apperrors.Wrap(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
@@ -5,30 +5,57 @@ package persistence
 
 import (
 	"context"
+	"time"
 
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/access"
 )
 
-// User persistence model interface
-type User interface {
+// Profile persistence model interface
+type Profile interface {
+	GetName() string
+	GetAvatarURL() string
+	GetLocale() string
+	GetTimezone() string
+}
+
+// Registration persistence model interface, user read model is added from registration
+type Registration interface {
+	Profile
+
 	GetID() string
 	GetEmail() string
 	GetFacebookID() string
 	GetGoogleID() string
 	GetRole() access.Role
+	IsVerified() bool
+	IsMFAEnabled() bool
+}
+
+// User persistence model interface
+type User interface {
+	Registration
+
+	GetRegisteredAt() time.Time
 }
 
 // UserRepository allows to get/save user to mysql storage
 type UserRepository interface {
-	FindAll(ctx context.Context, limit, offset int64) ([]User, error)
+	FindAll(ctx context.Context, query UserQuery) (users []User, nextCursor string, err error)
 	Get(ctx context.Context, id string) (User, error)
 	GetByEmail(ctx context.Context, email string) (User, error)
 	GetByFacebookID(ctx context.Context, facebookID string) (User, error)
 	GetByGoogleID(ctx context.Context, googleID string) (User, error)
-	Add(ctx context.Context, user User) error
+	GetByProvider(ctx context.Context, provider, subject string) (User, error)
+	Add(ctx context.Context, registration Registration, registeredAt time.Time) error
 	Delete(ctx context.Context, id string) error
-	Count(ctx context.Context) (int64, error)
+	Count(ctx context.Context, query UserQuery) (int64, error)
 	UpdateEmail(ctx context.Context, id, email string) error
 	UpdateFacebookID(ctx context.Context, id, facebookID string) error
 	UpdateGoogleID(ctx context.Context, id, googleID string) error
+	AddProvider(ctx context.Context, id, provider, subject string) error
+	UpdateVerified(ctx context.Context, id string, verified bool) error
+	UpdateMFAEnabled(ctx context.Context, id string, enabled bool) error
+	UpdateProfile(ctx context.Context, id string, profile Profile) error
+	AddRole(ctx context.Context, id string, role access.Role) error
+	RemoveRole(ctx context.Context, id string, role access.Role) error
 }
@@ -15,40 +15,29 @@ import (
 )
 
 type userRepository struct {
-	eventStore eventstore.EventStore
-	eventBus   eventbus.EventBus
+	repository *eventstore.Repository
 }
 
 // NewUserRepository creates new user event sourced repository
 func NewUserRepository(store eventstore.EventStore, bus eventbus.EventBus) user.Repository {
-	return &userRepository{store, bus}
+	return &userRepository{eventstore.NewRepository(store, bus)}
 }
 
 // Save current user changes to event store and publish each event with an event bus
 func (r *userRepository) Save(ctx context.Context, u user.User) error {
-	if err := r.eventStore.Store(ctx, u.Changes()); err != nil {
+	if err := r.repository.Save(ctx, &u); err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	for _, event := range u.Changes() {
-		if err := r.eventBus.Publish(ctx, event); err != nil {
-			return apperrors.Wrap(err)
-		}
-	}
-
 	return nil
 }
 
 // Get user with current state applied
 func (r *userRepository) Get(ctx context.Context, id uuid.UUID) (user.User, error) {
-	events, err := r.eventStore.GetStream(ctx, id, user.StreamName)
-	if err != nil {
+	u := user.New()
+	if err := r.repository.Get(ctx, id, &u); err != nil {
 		return user.User{}, apperrors.Wrap(err)
 	}
 
-	if len(events) == 0 {
-		return user.User{}, apperrors.ErrNotFound
-	}
-
-	return user.FromHistory(ctx, events)
+	return u, nil
 }
@@ -5,27 +5,37 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/services/token"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
 	"github.com/vardius/go-api-boilerplate/cmd/user/proto"
 	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	grpcerrors "github.com/vardius/go-api-boilerplate/pkg/grpc/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
 )
 
 type userServer struct {
 	commandBus     commandbus.CommandBus
+	commands       *registry.Registry
 	userRepository persistence.UserRepository
+	tokenPolicy    token.Policy
 }
 
 // NewServer returns new user server object
-func NewServer(cb commandbus.CommandBus, r persistence.UserRepository) proto.UserServiceServer {
+func NewServer(cb commandbus.CommandBus, commands *registry.Registry, r persistence.UserRepository, tokenPolicy token.Policy) proto.UserServiceServer {
 	s := &userServer{
 		commandBus:     cb,
+		commands:       commands,
 		userRepository: r,
+		tokenPolicy:    tokenPolicy,
 	}
 
 	return s
@@ -33,7 +43,7 @@ func NewServer(cb commandbus.CommandBus, r persistence.UserRepository) proto.Use
 
 // DispatchUserCommand implements proto.UserServiceServer interface
 func (s *userServer) DispatchUserCommand(ctx context.Context, r *proto.DispatchUserCommandRequest) (*empty.Empty, error) {
-	c, err := user.NewCommandFromPayload(r.GetName(), r.GetPayload())
+	c, err := s.commands.NewCommand(ctx, r.GetName(), r.GetPayload())
 	if err != nil {
 		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(err))
 	}
@@ -53,58 +63,154 @@ func (s *userServer) GetUser(ctx context.Context, r *proto.GetUserRequest) (*pro
 	}
 
 	return &proto.User{
-		Id:         u.GetID(),
-		Email:      u.GetEmail(),
-		FacebookId: u.GetFacebookID(),
-		GoogleId:   u.GetGoogleID(),
+		Id:           u.GetID(),
+		Email:        u.GetEmail(),
+		FacebookId:   u.GetFacebookID(),
+		GoogleId:     u.GetGoogleID(),
+		Name:         u.GetName(),
+		AvatarUrl:    u.GetAvatarURL(),
+		Locale:       u.GetLocale(),
+		Timezone:     u.GetTimezone(),
+		RegisteredAt: timestamppb.New(u.GetRegisteredAt()),
 	}, nil
 }
 
-// ListUsers implements proto.UserServiceServer interface
-func (s *userServer) ListUsers(ctx context.Context, r *proto.ListUserRequest) (*proto.ListUserResponse, error) {
-	if r.GetPage() < 1 || r.GetLimit() < 1 {
-		return nil, grpcerrors.NewGRPCError(apperrors.New("Invalid page or limit value. Please provide values greater then 1"))
+// AuthenticateWithPassword implements proto.UserServiceServer interface,
+// it dispatches login command and returns user credentials belong to, users with multi-factor authentication enabled are refused
+func (s *userServer) AuthenticateWithPassword(ctx context.Context, r *proto.AuthenticateWithPasswordRequest) (*proto.User, error) {
+	c := user.LoginWithPassword{
+		Email:    user.EmailAddress(r.GetEmail()),
+		Password: user.Password(r.GetPassword()),
+	}
+	if err := commandbus.Validate(c); err != nil {
+		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(err))
+	}
+
+	// password grant can not carry one-time password, user aggregate refuses users with multi-factor authentication enabled
+	if err := s.commandBus.Publish(ctx, c); err != nil {
+		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(err))
+	}
+
+	u, err := s.userRepository.GetByEmail(ctx, r.GetEmail())
+	if err != nil {
+		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(err))
+	}
+
+	return &proto.User{
+		Id:           u.GetID(),
+		Email:        u.GetEmail(),
+		FacebookId:   u.GetFacebookID(),
+		GoogleId:     u.GetGoogleID(),
+		Name:         u.GetName(),
+		AvatarUrl:    u.GetAvatarURL(),
+		Locale:       u.GetLocale(),
+		Timezone:     u.GetTimezone(),
+		RegisteredAt: timestamppb.New(u.GetRegisteredAt()),
+	}, nil
+}
+
+// AuthenticateWithLoginCode implements proto.UserServiceServer interface,
+// it uses up login code sent by email and returns user code was issued to with roles and permissions granted by token policy
+func (s *userServer) AuthenticateWithLoginCode(ctx context.Context, r *proto.AuthenticateWithLoginCodeRequest) (*proto.AuthenticateWithLoginCodeResponse, error) {
+	id, err := uuid.Parse(r.GetId())
+	if err != nil {
+		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrInvalid, err)))
+	}
+
+	c := user.UseLoginCode{
+		ID:   id,
+		Code: r.GetCode(),
+		OTP:  r.GetOtp(),
+	}
+	if err := commandbus.Validate(c); err != nil {
+		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(err))
 	}
 
-	var users []persistence.User
-	var list []*proto.User
+	if err := s.commandBus.Publish(ctx, c); err != nil {
+		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(err))
+	}
 
-	totalUsers, err := s.userRepository.Count(ctx)
+	u, err := s.userRepository.Get(ctx, id.String())
 	if err != nil {
 		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(err))
 	}
 
-	offset := (r.GetPage() * r.GetLimit()) - r.GetLimit()
+	granted := s.tokenPolicy.Identity(id, u.IsVerified(), u.GetRole())
+	permissions := make([]string, len(granted.Permissions))
+	for i := range granted.Permissions {
+		permissions[i] = string(granted.Permissions[i])
+	}
 
-	if totalUsers < 1 || offset > (totalUsers-1) {
-		return &proto.ListUserResponse{
-			Page:  r.GetPage(),
-			Limit: r.GetLimit(),
-			Total: totalUsers,
-			Users: list,
-		}, nil
+	return &proto.AuthenticateWithLoginCodeResponse{
+		User: &proto.User{
+			Id:           u.GetID(),
+			Email:        u.GetEmail(),
+			FacebookId:   u.GetFacebookID(),
+			GoogleId:     u.GetGoogleID(),
+			Name:         u.GetName(),
+			AvatarUrl:    u.GetAvatarURL(),
+			Locale:       u.GetLocale(),
+			Timezone:     u.GetTimezone(),
+			RegisteredAt: timestamppb.New(u.GetRegisteredAt()),
+		},
+		Role:        granted.Role.String(),
+		Permissions: permissions,
+	}, nil
+}
+
+// ListUsers implements proto.UserServiceServer interface
+func (s *userServer) ListUsers(ctx context.Context, r *proto.ListUserRequest) (*proto.ListUserResponse, error) {
+	query := persistence.UserQuery{
+		Email:    r.GetEmail(),
+		Provider: r.GetProvider(),
+		Limit:    r.GetLimit(),
+		Cursor:   r.GetCursor(),
+	}
+
+	var err error
+	if query.Sort, query.Descending, err = persistence.ParseUserSort(r.GetSort()); err != nil {
+		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(err))
+	}
+	if query.Role, err = identity.ParseRole(r.GetRole()); err != nil {
+		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrInvalid, err)))
+	}
+	if r.GetRegisteredAfter() != nil {
+		query.RegisteredAfter = r.GetRegisteredAfter().AsTime()
+	}
+	if r.GetRegisteredBefore() != nil {
+		query.RegisteredBefore = r.GetRegisteredBefore().AsTime()
+	}
+
+	totalUsers, err := s.userRepository.Count(ctx, query)
+	if err != nil {
+		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(err))
 	}
 
-	users, err = s.userRepository.FindAll(ctx, r.GetLimit(), offset)
+	users, nextCursor, err := s.userRepository.FindAll(ctx, query)
 	if err != nil {
 		return nil, grpcerrors.NewGRPCError(apperrors.Wrap(err))
 	}
 
-	list = make([]*proto.User, len(users))
+	list := make([]*proto.User, len(users))
 	for i := range users {
 		list[i] = &proto.User{
-			Id:         users[i].GetID(),
-			Email:      users[i].GetEmail(),
-			FacebookId: users[i].GetFacebookID(),
-			GoogleId:   users[i].GetGoogleID(),
+			Id:           users[i].GetID(),
+			Email:        users[i].GetEmail(),
+			FacebookId:   users[i].GetFacebookID(),
+			GoogleId:     users[i].GetGoogleID(),
+			Name:         users[i].GetName(),
+			AvatarUrl:    users[i].GetAvatarURL(),
+			Locale:       users[i].GetLocale(),
+			Timezone:     users[i].GetTimezone(),
+			RegisteredAt: timestamppb.New(users[i].GetRegisteredAt()),
 		}
 	}
 
 	response := &proto.ListUserResponse{
-		Page:  r.GetPage(),
-		Limit: r.GetLimit(),
-		Total: totalUsers,
-		Users: list,
+		Limit:      query.GetLimit(),
+		Total:      totalUsers,
+		Users:      list,
+		NextCursor: nextCursor,
 	}
 
 	return response, nil
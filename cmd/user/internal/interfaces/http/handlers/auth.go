@@ -3,30 +3,66 @@ package handlers
 import (
 	"crypto/rand"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
-	"net/url"
 	"time"
 
-	"golang.org/x/oauth2"
+	"github.com/google/uuid"
 
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/pkg/auth/oidc"
 	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	httpjson "github.com/vardius/go-api-boilerplate/pkg/http/response/json"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
 )
 
-type requestBody struct {
-	Email string `json:"email"`
+const authCookieName = "oauthstate"
+
+// ProfileMapper maps identity provider profile to register command
+type ProfileMapper func(provider, accessToken string, profile oidc.Profile) domain.Command
+
+// GoogleProfile maps google profile to user.RegisterWithGoogle command
+func GoogleProfile(provider, accessToken string, profile oidc.Profile) domain.Command {
+	return user.RegisterWithGoogle{
+		Email:       user.EmailAddress(profile.Email),
+		GoogleID:    profile.Subject,
+		AccessToken: accessToken,
+		Name:        profile.Name,
+		AvatarURL:   profile.Picture,
+		Locale:      profile.Locale,
+	}
 }
 
-const authCookieName = "oauthstate"
+// FacebookProfile maps facebook profile to user.RegisterWithFacebook command
+func FacebookProfile(provider, accessToken string, profile oidc.Profile) domain.Command {
+	return user.RegisterWithFacebook{
+		Email:       user.EmailAddress(profile.Email),
+		FacebookID:  profile.Subject,
+		AccessToken: accessToken,
+		Name:        profile.Name,
+		AvatarURL:   profile.Picture,
+	}
+}
+
+// ProviderProfile maps profile of any identity provider to user.RegisterWithProvider command
+func ProviderProfile(provider, accessToken string, profile oidc.Profile) domain.Command {
+	return user.RegisterWithProvider{
+		Provider:      provider,
+		Subject:       profile.Subject,
+		Email:         user.EmailAddress(profile.Email),
+		EmailVerified: profile.EmailVerified,
+		AccessToken:   accessToken,
+		Name:          profile.Name,
+		AvatarURL:     profile.Picture,
+		Locale:        profile.Locale,
+	}
+}
 
-// BuildSocialAuthHandler wraps user gRPC client with http.Handler
-func BuildSocialAuthHandler(config *oauth2.Config) http.Handler {
+// BuildSocialAuthHandler redirects to identity provider consent page
+func BuildSocialAuthHandler(provider *oidc.Provider, redirectURL string) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) error {
 		expiration := time.Now().Add(365 * 24 * time.Hour)
 
@@ -37,10 +73,15 @@ func BuildSocialAuthHandler(config *oauth2.Config) http.Handler {
 
 		state := base64.URLEncoding.EncodeToString(b)
 
+		authURL, err := provider.AuthCodeURL(r.Context(), redirectURL, state)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
 		cookie := http.Cookie{Name: authCookieName, Value: state, Expires: expiration}
 		http.SetCookie(w, &cookie)
 
-		http.Redirect(w, r, config.AuthCodeURL(state), http.StatusTemporaryRedirect)
+		http.Redirect(w, r, authURL, http.StatusTemporaryRedirect)
 
 		return nil
 	}
@@ -48,31 +89,28 @@ func BuildSocialAuthHandler(config *oauth2.Config) http.Handler {
 	return httpjson.HandlerFunc(fn)
 }
 
-// BuildAuthCallbackHandler wraps user gRPC client with http.Handler
-func BuildAuthCallbackHandler(authConfig *oauth2.Config, apiURL string, cb commandbus.CommandBus, commandName string) http.Handler {
+// BuildAuthCallbackHandler exchanges authorization code for token and dispatches register command with user profile,
+// access token is sent to user by email. Register command is built only from profile returned by identity provider
+// as it decides which account user is logged in to
+func BuildAuthCallbackHandler(provider *oidc.Provider, redirectURL string, cb commandbus.CommandBus, mapProfile ProfileMapper) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) error {
-		oauthState, _ := r.Cookie(authCookieName)
-		if r.FormValue("state") != oauthState.Value {
-			return apperrors.Wrap(fmt.Errorf("invalid oauth state"))
+		oauthState, err := r.Cookie(authCookieName)
+		if err != nil || r.FormValue("state") != oauthState.Value {
+			return apperrors.Wrap(fmt.Errorf("%w: invalid oauth state", apperrors.ErrInvalid))
 		}
 
-		oauthToken, err := authConfig.Exchange(r.Context(), r.FormValue("code"))
+		oauthToken, err := provider.Exchange(r.Context(), redirectURL, r.FormValue("code"))
 		if err != nil {
 			return apperrors.Wrap(err)
 		}
 
-		profileData, err := getProfile(oauthToken.AccessToken, apiURL)
+		profile, err := provider.Profile(r.Context(), oauthToken)
 		if err != nil {
 			return apperrors.Wrap(err)
 		}
 
-		var emailData requestBody
-		if err := json.Unmarshal(profileData, &emailData); err != nil {
-			return apperrors.Wrap(err)
-		}
-
-		c, err := user.NewCommandFromPayload(commandName, profileData)
-		if err != nil {
+		c := mapProfile(provider.Name(), oauthToken.AccessToken, profile)
+		if err := commandbus.Validate(c); err != nil {
 			return apperrors.Wrap(err)
 		}
 
@@ -88,17 +126,22 @@ func BuildAuthCallbackHandler(authConfig *oauth2.Config, apiURL string, cb comma
 	return httpjson.HandlerFunc(fn)
 }
 
-func getProfile(accessToken, apiURL string) ([]byte, error) {
-	resp, e := http.Get(apiURL + "?access_token=" + url.QueryEscape(accessToken))
-	if e != nil {
-		return nil, e
-	}
+// BuildDisconnectHandler unlinks identity provider account from authenticated user
+func BuildDisconnectHandler(cb commandbus.CommandBus, newCommand func(userID uuid.UUID) domain.Command) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) error {
+		i, ok := identity.FromContext(r.Context())
+		if !ok {
+			return apperrors.Wrap(apperrors.ErrUnauthorized)
+		}
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return body, apperrors.Wrap(err)
+		if err := cb.Publish(r.Context(), newCommand(i.UserID)); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
 	}
 
-	return body, nil
+	return httpjson.HandlerFunc(fn)
 }
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/vardius/go-api-boilerplate/pkg/contract"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	httpjson "github.com/vardius/go-api-boilerplate/pkg/http/response/json"
+)
+
+// BuildContractsHandler provides JSON Schemas of published events and accepted commands
+func BuildContractsHandler() http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) error {
+		if err := httpjson.JSON(r.Context(), w, http.StatusOK, struct {
+			Events   map[string]*contract.Schema `json:"events"`
+			Commands map[string]*contract.Schema `json:"commands"`
+		}{
+			Events:   contract.Events(),
+			Commands: contract.Commands(),
+		}); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return httpjson.HandlerFunc(fn)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventstore"
+	httpjson "github.com/vardius/go-api-boilerplate/pkg/http/response/json"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+// secretPayloadFields are removed from exported events, they hold credentials rather than personal data
+var secretPayloadFields = []string{"password_hash", "access_token", "token", "cancellation_token", "secret", "recovery_codes", "recovery_code", "code"}
+
+// BuildExportHandler responds with zip archive of authenticated user view model and event history
+func BuildExportHandler(repository persistence.UserRepository, eventStore eventstore.EventStore) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) error {
+		i, ok := identity.FromContext(r.Context())
+		if !ok {
+			return apperrors.Wrap(apperrors.ErrUnauthorized)
+		}
+
+		u, err := repository.Get(r.Context(), i.UserID.String())
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		events, err := eventStore.GetStream(r.Context(), i.UserID, user.StreamName)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		history, err := exportEvents(events)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+		if err := writeArchiveJSON(archive, "user.json", u); err != nil {
+			return apperrors.Wrap(err)
+		}
+		if err := writeArchiveJSON(archive, "events.json", history); err != nil {
+			return apperrors.Wrap(err)
+		}
+		if err := archive.Close(); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%s-%s.zip"`, i.UserID, time.Now().UTC().Format("20060102")))
+		w.WriteHeader(http.StatusOK)
+
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return httpjson.HandlerFunc(fn)
+}
+
+func exportEvents(events []*domain.Event) ([]map[string]interface{}, error) {
+	history := make([]map[string]interface{}, 0, len(events))
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+
+		var event map[string]interface{}
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+
+		if payload, ok := event["payload"].(map[string]interface{}); ok {
+			for _, field := range secretPayloadFields {
+				delete(payload, field)
+			}
+		}
+		// identity events were recorded with belongs to whoever dispatched the command and may carry credentials
+		if metadata, ok := event["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "identity")
+		}
+
+		history = append(history, event)
+	}
+
+	return history, nil
+}
+
+func writeArchiveJSON(archive *zip.Writer, name string, v interface{}) error {
+	f, err := archive.Create(name)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
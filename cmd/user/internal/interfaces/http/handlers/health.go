@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
 	httpjson "github.com/vardius/go-api-boilerplate/pkg/http/response/json"
 
 	"google.golang.org/grpc"
@@ -24,7 +25,7 @@ func BuildLivenessHandler() http.Handler {
 }
 
 // BuildReadinessHandler provides readiness handler
-func BuildReadinessHandler(sqlConn *sql.DB, mongoConn *mongo.Client, connMap map[string]*grpc.ClientConn) http.Handler {
+func BuildReadinessHandler(sqlConn *sql.DB, mongoConn *mongo.Client, connMap map[string]*grpc.ClientConn, eventBus eventbus.EventBus) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) error {
 		if sqlConn != nil {
 			if err := sqlConn.PingContext(r.Context()); err != nil {
@@ -43,6 +44,12 @@ func BuildReadinessHandler(sqlConn *sql.DB, mongoConn *mongo.Client, connMap map
 			}
 		}
 
+		if hc, ok := eventBus.(eventbus.HealthChecker); ok {
+			if err := hc.HealthCheck(r.Context()); err != nil {
+				return apperrors.Wrap(err)
+			}
+		}
+
 		w.WriteHeader(http.StatusNoContent)
 
 		return nil
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/services/token"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
+	"github.com/vardius/go-api-boilerplate/pkg/auth"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	httpjson "github.com/vardius/go-api-boilerplate/pkg/http/response/json"
+)
+
+type loginResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	MFARequired bool   `json:"mfa_required,omitempty"`
+}
+
+// BuildPasswordLoginHandler verifies user credentials and responds with access token stored by auth service,
+// users with multi-factor authentication enabled receive challenge token to be exchanged for access token instead
+func BuildPasswordLoginHandler(cb commandbus.CommandBus, repository persistence.UserRepository, signedMethod jwt.SigningMethod, authenticator auth.Authenticator, tokenPolicy token.Policy) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) error {
+		if r.Body == nil {
+			return fmt.Errorf("%w: %v", apperrors.ErrInvalid, ErrEmptyRequestBody)
+		}
+
+		defer r.Body.Close()
+
+		var c user.LoginWithPassword
+		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+			return apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrInvalid, err))
+		}
+		if err := commandbus.Validate(c); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		// multi-factor authentication is enforced by user aggregate, read model might not be updated yet
+		mfaRequired := false
+		if err := cb.Publish(r.Context(), c); errors.Is(err, user.ErrMFARequired) {
+			mfaRequired = true
+		} else if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.GetByEmail(r.Context(), c.Email.String())
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		userID, err := uuid.Parse(u.GetID())
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		i, ttl := tokenPolicy.Identity(userID, u.IsVerified(), u.GetRole()), token.TTL
+		if mfaRequired {
+			i, ttl = tokenPolicy.ChallengeIdentity(userID), tokenPolicy.ChallengeTTL
+		}
+
+		accessToken, expiresAt, err := token.Issue(r.Context(), signedMethod, authenticator, cb, i, ttl)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := httpjson.JSON(r.Context(), w, http.StatusOK, loginResponse{
+			AccessToken: accessToken,
+			TokenType:   "Bearer",
+			ExpiresIn:   int64(time.Until(expiresAt).Seconds()),
+			MFARequired: mfaRequired,
+		}); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return httpjson.HandlerFunc(fn)
+}
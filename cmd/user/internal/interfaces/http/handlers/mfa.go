@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/services/token"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
+	"github.com/vardius/go-api-boilerplate/pkg/auth"
+	"github.com/vardius/go-api-boilerplate/pkg/auth/totp"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	httpjson "github.com/vardius/go-api-boilerplate/pkg/http/response/json"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+type mfaCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type mfaEnrolmentResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type mfaRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// BuildStartMFAEnrolmentHandler generates one-time password secret for authenticated user,
+// responds with secret and otpauth URI authenticator app can be enrolled with
+func BuildStartMFAEnrolmentHandler(cb commandbus.CommandBus, repository persistence.UserRepository, issuer string) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) error {
+		i, ok := identity.FromContext(r.Context())
+		if !ok {
+			return apperrors.Wrap(apperrors.ErrUnauthorized)
+		}
+
+		u, err := repository.Get(r.Context(), i.UserID.String())
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		secret, err := totp.GenerateSecret()
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := cb.Publish(r.Context(), user.StartMFAEnrolment{ID: i.UserID, Secret: secret}); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := httpjson.JSON(r.Context(), w, http.StatusOK, mfaEnrolmentResponse{
+			Secret: secret,
+			URI:    totp.URI(issuer, u.GetEmail(), secret),
+		}); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return httpjson.HandlerFunc(fn)
+}
+
+// BuildEnableMFAHandler confirms enrolment with one-time password,
+// responds with recovery codes which are shown to user only once
+func BuildEnableMFAHandler(cb commandbus.CommandBus) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) error {
+		i, ok := identity.FromContext(r.Context())
+		if !ok {
+			return apperrors.Wrap(apperrors.ErrUnauthorized)
+		}
+
+		req, err := decodeMFACode(r)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		recoveryCodes, err := totp.GenerateRecoveryCodes(user.RecoveryCodesCount)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		c := user.EnableMFA{ID: i.UserID, Code: req.Code, RecoveryCodes: recoveryCodes}
+		if err := commandbus.Validate(c); err != nil {
+			return apperrors.Wrap(err)
+		}
+		if err := cb.Publish(r.Context(), c); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := httpjson.JSON(r.Context(), w, http.StatusOK, mfaRecoveryCodesResponse{RecoveryCodes: recoveryCodes}); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return httpjson.HandlerFunc(fn)
+}
+
+// BuildMFAChallengeHandler exchanges challenge token and one-time password or recovery code
+// for access token stored by auth service
+func BuildMFAChallengeHandler(cb commandbus.CommandBus, repository persistence.UserRepository, signedMethod jwt.SigningMethod, authenticator auth.Authenticator, tokenPolicy token.Policy) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) error {
+		i, ok := identity.FromContext(r.Context())
+		if !ok {
+			return apperrors.Wrap(apperrors.ErrUnauthorized)
+		}
+
+		req, err := decodeMFACode(r)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		c := user.PassMFAChallenge{ID: i.UserID, Code: req.Code}
+		if err := commandbus.Validate(c); err != nil {
+			return apperrors.Wrap(err)
+		}
+		if err := cb.Publish(r.Context(), c); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		u, err := repository.Get(r.Context(), i.UserID.String())
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		accessToken, expiresAt, err := token.Issue(r.Context(), signedMethod, authenticator, cb, tokenPolicy.Identity(i.UserID, u.IsVerified(), u.GetRole()), token.TTL)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := httpjson.JSON(r.Context(), w, http.StatusOK, loginResponse{
+			AccessToken: accessToken,
+			TokenType:   "Bearer",
+			ExpiresIn:   int64(time.Until(expiresAt).Seconds()),
+		}); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		return nil
+	}
+
+	return httpjson.HandlerFunc(fn)
+}
+
+func decodeMFACode(r *http.Request) (mfaCodeRequest, error) {
+	var req mfaCodeRequest
+	if r.Body == nil {
+		return req, fmt.Errorf("%w: %v", apperrors.ErrInvalid, ErrEmptyRequestBody)
+	}
+	defer r.Body.Close()
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return req, apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrInvalid, err))
+	}
+
+	return req, nil
+}
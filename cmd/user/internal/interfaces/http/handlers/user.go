@@ -2,58 +2,19 @@ package handlers
 
 import (
 	"fmt"
-	"io/ioutil"
-	"math"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/vardius/gorouter/v4/context"
 
-	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
-	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	httpjson "github.com/vardius/go-api-boilerplate/pkg/http/response/json"
 	"github.com/vardius/go-api-boilerplate/pkg/identity"
 )
 
-// BuildUserCommandDispatchHandler
-func BuildUserCommandDispatchHandler(cb commandbus.CommandBus) http.Handler {
-	fn := func(w http.ResponseWriter, r *http.Request) error {
-		if r.Body == nil {
-			return fmt.Errorf("%w: %v", apperrors.ErrInvalid, ErrEmptyRequestBody)
-		}
-
-		params, ok := context.Parameters(r.Context())
-		if !ok {
-			return fmt.Errorf("%w: %v", apperrors.ErrInvalid, ErrInvalidURLParams)
-		}
-
-		defer r.Body.Close()
-		body, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			return apperrors.Wrap(err)
-		}
-
-		c, err := user.NewCommandFromPayload(params.Value("command"), body)
-		if err != nil {
-			return apperrors.Wrap(err)
-		}
-
-		if err := cb.Publish(r.Context(), c); err != nil {
-			return apperrors.Wrap(err)
-		}
-
-		if err := httpjson.JSON(r.Context(), w, http.StatusCreated, nil); err != nil {
-			return apperrors.Wrap(err)
-		}
-
-		return nil
-	}
-
-	return httpjson.HandlerFunc(fn)
-}
-
 // BuildMeHandler
 func BuildMeHandler(repository persistence.UserRepository) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) error {
@@ -97,40 +58,33 @@ func BuildGetUserHandler(repository persistence.UserRepository) http.Handler {
 	return httpjson.HandlerFunc(fn)
 }
 
-// BuildListUserHandler
+// BuildListUserHandler lists users matching query parameters:
+// email (substring), provider (email, google, facebook or identity provider name), role,
+// registered_after and registered_before (RFC 3339), sort (registered_at, email or name, prefixed with minus sign for descending order),
+// limit and cursor (next_cursor of previous page)
 func BuildListUserHandler(repository persistence.UserRepository) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) error {
-		pageInt, _ := strconv.ParseInt(r.URL.Query().Get("page"), 10, 32)
-		limitInt, _ := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 32)
-		page := int64(math.Max(float64(pageInt), 1))
-		limit := int64(math.Max(float64(limitInt), 20))
-
-		totalUsers, err := repository.Count(r.Context())
+		query, err := userQuery(r.URL.Query())
 		if err != nil {
 			return apperrors.Wrap(err)
 		}
 
-		offset := (page * limit) - limit
+		totalUsers, err := repository.Count(r.Context(), query)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
 
 		paginatedList := struct {
-			Users []persistence.User `json:"users"`
-			Page  int64              `json:"page"`
-			Limit int64              `json:"limit"`
-			Total int64              `json:"total"`
+			Users      []persistence.User `json:"users"`
+			Limit      int64              `json:"limit"`
+			Total      int64              `json:"total"`
+			NextCursor string             `json:"next_cursor,omitempty"`
 		}{
-			Page:  page,
-			Limit: limit,
+			Limit: query.GetLimit(),
 			Total: totalUsers,
 		}
 
-		if totalUsers < 1 || offset > (totalUsers-1) {
-			if err := httpjson.JSON(r.Context(), w, http.StatusOK, paginatedList); err != nil {
-				return apperrors.Wrap(err)
-			}
-			return nil
-		}
-
-		paginatedList.Users, err = repository.FindAll(r.Context(), limit, offset)
+		paginatedList.Users, paginatedList.NextCursor, err = repository.FindAll(r.Context(), query)
 		if err != nil {
 			return apperrors.Wrap(err)
 		}
@@ -144,3 +98,38 @@ func BuildListUserHandler(repository persistence.UserRepository) http.Handler {
 
 	return httpjson.HandlerFunc(fn)
 }
+
+func userQuery(values url.Values) (persistence.UserQuery, error) {
+	query := persistence.UserQuery{
+		Email:    values.Get("email"),
+		Provider: values.Get("provider"),
+		Cursor:   values.Get("cursor"),
+	}
+
+	var err error
+	if query.Sort, query.Descending, err = persistence.ParseUserSort(values.Get("sort")); err != nil {
+		return query, apperrors.Wrap(err)
+	}
+	if v := values.Get("limit"); v != "" {
+		if query.Limit, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return query, apperrors.Wrap(fmt.Errorf("%w: invalid limit: %s", apperrors.ErrInvalid, err))
+		}
+	}
+	if v := values.Get("role"); v != "" {
+		if query.Role, err = identity.ParseRole(v); err != nil {
+			return query, apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrInvalid, err))
+		}
+	}
+	if v := values.Get("registered_after"); v != "" {
+		if query.RegisteredAfter, err = time.Parse(time.RFC3339, v); err != nil {
+			return query, apperrors.Wrap(fmt.Errorf("%w: invalid registered_after: %s", apperrors.ErrInvalid, err))
+		}
+	}
+	if v := values.Get("registered_before"); v != "" {
+		if query.RegisteredBefore, err = time.Parse(time.RFC3339, v); err != nil {
+			return query, apperrors.Wrap(fmt.Errorf("%w: invalid registered_before: %s", apperrors.ErrInvalid, err))
+		}
+	}
+
+	return query, nil
+}
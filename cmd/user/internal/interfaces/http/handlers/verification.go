@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	httpjson "github.com/vardius/go-api-boilerplate/pkg/http/response/json"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+// BuildResendVerificationHandler sends new email verification link to authenticated user
+func BuildResendVerificationHandler(cb commandbus.CommandBus) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) error {
+		i, ok := identity.FromContext(r.Context())
+		if !ok {
+			return apperrors.Wrap(apperrors.ErrUnauthorized)
+		}
+
+		if err := cb.Publish(r.Context(), user.RequestEmailVerification{ID: i.UserID}); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+
+	return httpjson.HandlerFunc(fn)
+}
@@ -6,37 +6,60 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/access"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/config"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/services/token"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/domain/user"
 	userpersistence "github.com/vardius/go-api-boilerplate/cmd/user/internal/infrastructure/persistence"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/interfaces/http/handlers"
 	"github.com/vardius/go-api-boilerplate/pkg/auth"
+	"github.com/vardius/go-api-boilerplate/pkg/auth/oidc"
 	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+	"github.com/vardius/go-api-boilerplate/pkg/eventstore"
+	"github.com/vardius/go-api-boilerplate/pkg/execution"
 	httpmiddleware "github.com/vardius/go-api-boilerplate/pkg/http/middleware"
 	httpauthenticator "github.com/vardius/go-api-boilerplate/pkg/http/middleware/authenticator"
 	"github.com/vardius/go-api-boilerplate/pkg/http/response/json"
+	"github.com/vardius/go-api-boilerplate/pkg/idempotency"
 	"github.com/vardius/go-api-boilerplate/pkg/identity"
 	"github.com/vardius/gorouter/v4"
 	"go.mongodb.org/mongo-driver/mongo"
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/facebook"
-	"golang.org/x/oauth2/google"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 )
 
-const googleAPIURL = "https://www.googleapis.com/oauth2/v2/userinfo"
-const facebookAPIURL = "https://graph.facebook.com/me"
+// reservedPaths can not be used as identity provider names, social login routes are registered next to them
+var reservedPaths = map[string]bool{"login": true, "me": true, "mfa": true, "dispatch": true, "commands": true, "contracts": true}
+
+// socialLogins of providers which accounts are stored with their own ids, other providers are registered with user.RegisterWithProvider
+var socialLogins = map[string]handlers.ProfileMapper{
+	"google":   handlers.GoogleProfile,
+	"facebook": handlers.FacebookProfile,
+}
 
 // NewRouter provides new router
 func NewRouter(
 	cfg *config.Config,
 	tokenAuthorizer auth.TokenAuthorizer,
+	tokenAuthenticator auth.Authenticator,
+	identityProviders *oidc.Registry,
 	repository userpersistence.UserRepository,
 	commandBus commandbus.CommandBus,
+	commands *registry.Registry,
+	eventBus eventbus.EventBus,
+	eventStore eventstore.EventStore,
+	executionStore execution.Store,
+	idempotencyStore idempotency.Store,
 	sqlConn *sql.DB, mongoConn *mongo.Client,
 	grpcConnectionMap map[string]*grpc.ClientConn,
 ) http.Handler {
 	authenticator := httpauthenticator.NewToken(tokenAuthorizer.Auth)
+	dispatcher := execution.NewDispatcher(commandBus, executionStore, cfg.Execution.TTL)
 
 	// Global middleware
 	router := gorouter.New(
@@ -59,33 +82,53 @@ func NewRouter(
 	router.NotFound(json.NotFound())
 	router.NotAllowed(json.NotAllowed())
 
-	router.GET("/", handlers.BuildListUserHandler(repository))
+	router.GET("/", httpmiddleware.GrantAccessForRole(identity.RoleAdmin|identity.RoleSuperAdmin)(handlers.BuildListUserHandler(repository)))
 	router.GET("/me", handlers.BuildMeHandler(repository))
+	router.GET("/me/export", handlers.BuildExportHandler(repository, eventStore))
+	router.GET("/contracts", handlers.BuildContractsHandler())
 	router.GET("/{id}", handlers.BuildGetUserHandler(repository))
-	router.POST("/dispatch/user/{command}", handlers.BuildUserCommandDispatchHandler(commandBus))
-
-	var googleOauthConfig = &oauth2.Config{
-		RedirectURL:  fmt.Sprintf("%s/v1/google/callback", cfg.App.ApiBaseURL),
-		ClientID:     cfg.Google.ClientID,
-		ClientSecret: cfg.Google.ClientSecret,
-		Scopes:       []string{"https://www.googleapis.com/auth/userinfo.email"},
-		Endpoint:     google.Endpoint,
-	}
-	router.POST("/google", handlers.BuildSocialAuthHandler(googleOauthConfig))
-	router.POST("/google/callback", handlers.BuildAuthCallbackHandler(googleOauthConfig, googleAPIURL, commandBus, user.RegisterUserWithGoogle))
-
-	var facebookOauthConfig = &oauth2.Config{
-		RedirectURL:  fmt.Sprintf("%s/v1/facebook/callback", cfg.App.ApiBaseURL),
-		ClientID:     cfg.Google.ClientID,
-		ClientSecret: cfg.Google.ClientSecret,
-		Scopes:       []string{"public_profile"},
-		Endpoint:     facebook.Endpoint,
+	router.POST("/dispatch/user/{command}", handlers.BuildCommandDispatchHandler(commands, commandBus, dispatcher))
+	router.GET("/commands/{id}", handlers.BuildGetCommandHandler(executionStore))
+	router.POST("/login", handlers.BuildPasswordLoginHandler(commandBus, repository, jwt.SigningMethodHS512, tokenAuthenticator, token.NewPolicy(cfg)))
+	router.POST("/me/verification", handlers.BuildResendVerificationHandler(commandBus))
+	router.POST("/me/mfa", handlers.BuildStartMFAEnrolmentHandler(commandBus, repository, cfg.MFA.Issuer))
+	router.POST("/me/mfa/confirm", handlers.BuildEnableMFAHandler(commandBus))
+	router.POST("/mfa/challenge", handlers.BuildMFAChallengeHandler(commandBus, repository, jwt.SigningMethodHS512, tokenAuthenticator, token.NewPolicy(cfg)))
+
+	for _, provider := range identityProviders.Providers() {
+		if reservedPaths[provider.Name()] {
+			panic(fmt.Errorf("identity provider name %s conflicts with user routes", provider.Name()))
+		}
+
+		redirectURL := fmt.Sprintf("%s/v1/%s/callback", cfg.App.ApiBaseURL, provider.Name())
+
+		mapProfile := handlers.ProfileMapper(handlers.ProviderProfile)
+		if login, ok := socialLogins[provider.Name()]; ok {
+			mapProfile = login
+		}
+
+		router.POST("/"+provider.Name(), handlers.BuildSocialAuthHandler(provider, redirectURL))
+		router.POST("/"+provider.Name()+"/callback", handlers.BuildAuthCallbackHandler(provider, redirectURL, commandBus, mapProfile))
 	}
-	router.POST("/facebook", handlers.BuildSocialAuthHandler(facebookOauthConfig))
-	router.POST("/facebook/callback", handlers.BuildAuthCallbackHandler(facebookOauthConfig, facebookAPIURL, commandBus, user.RegisterUserWithGoogle))
+
+	router.DELETE("/me/google", handlers.BuildDisconnectHandler(commandBus, func(userID uuid.UUID) domain.Command { return user.DisconnectGoogle{ID: userID} }))
+	router.DELETE("/me/facebook", handlers.BuildDisconnectHandler(commandBus, func(userID uuid.UUID) domain.Command { return user.DisconnectFacebook{ID: userID} }))
 
 	router.USE(http.MethodGet, "/me", httpmiddleware.GrantAccessFor(identity.PermissionUserRead))
-	router.USE(http.MethodPost, "/dispatch/"+user.ChangeUserEmailAddress, httpmiddleware.GrantAccessFor(identity.PermissionUserWrite))
+	router.USE(http.MethodGet, "/me/export", httpmiddleware.GrantAccessFor(identity.PermissionUserRead))
+	router.USE(http.MethodPost, "/me/verification",
+		httpmiddleware.GrantAccessFor(identity.PermissionUserWrite),
+		httpmiddleware.RateLimit(rate.Every(cfg.Verification.ResendInterval), 1, 10*time.Minute), // one verification email per resend interval
+	)
+	router.USE(http.MethodPost, "/me/mfa", httpmiddleware.GrantAccessFor(identity.PermissionUserWrite))
+	router.USE(http.MethodDelete, "/me/google", httpmiddleware.GrantAccessFor(identity.PermissionUserWrite))
+	router.USE(http.MethodDelete, "/me/facebook", httpmiddleware.GrantAccessFor(identity.PermissionUserWrite))
+	router.USE(http.MethodPost, "/login", httpmiddleware.RateLimit(rate.Every(time.Minute/5), 5, 10*time.Minute)) // slows down guessing passwords
+	router.USE(http.MethodPost, "/mfa/challenge",
+		httpmiddleware.GrantAccessFor(access.PermissionMFAChallenge),
+		httpmiddleware.RateLimit(rate.Every(time.Minute/5), 5, 10*time.Minute), // slows down guessing one-time passwords
+	)
+	router.USE(http.MethodPost, "/dispatch", httpmiddleware.Idempotent(idempotencyStore, cfg.Idempotency.TTL))
 
 	mainRouter := gorouter.New()
 	mainRouter.NotFound(json.NotFound())
@@ -95,7 +138,7 @@ func NewRouter(
 	// Liveness probes are to indicate that your application is running
 	mainRouter.GET("/health", handlers.BuildLivenessHandler())
 	// Readiness is meant to check if your application is ready to serve traffic
-	mainRouter.GET("/readiness", handlers.BuildReadinessHandler(sqlConn, mongoConn, grpcConnectionMap))
+	mainRouter.GET("/readiness", handlers.BuildReadinessHandler(sqlConn, mongoConn, grpcConnectionMap, eventBus))
 
 	mainRouter.Mount("/v1", router)
 
@@ -15,6 +15,7 @@ import (
 
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/config"
 	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/services"
+	"github.com/vardius/go-api-boilerplate/cmd/user/internal/application/services/token"
 	usergrpc "github.com/vardius/go-api-boilerplate/cmd/user/internal/interfaces/grpc"
 	userhttp "github.com/vardius/go-api-boilerplate/cmd/user/internal/interfaces/http"
 	userproto "github.com/vardius/go-api-boilerplate/cmd/user/proto"
@@ -58,28 +59,37 @@ func main() {
 		[]grpc.UnaryServerInterceptor{
 			middleware.TransformUnaryOutgoingError(),
 			middleware.CountIncomingUnaryRequests(),
-			// 	firewall.GrantAccessForUnaryRequest(identity.RoleUser),
+			middleware.IdempotentUnaryRequest(container.IdempotencyStore, cfg.Idempotency.TTL),
+			// 	firewall.GrantAccessForRoleUnaryRequest(identity.RoleUser),
 		},
 		[]grpc.StreamServerInterceptor{
 			middleware.TransformStreamOutgoingError(),
 			middleware.CountIncomingStreamRequests(),
-			// 	firewall.GrantAccessForStreamRequest(identity.RoleUser),
+			// 	firewall.GrantAccessForRoleStreamRequest(identity.RoleUser),
 		},
 	)
 
 	router := userhttp.NewRouter(
 		cfg,
 		container.TokenAuthorizer,
+		container.Authenticator,
+		container.IdentityProviders,
 		container.UserPersistenceRepository,
 		container.CommandBus,
+		container.CommandRegistry,
+		container.EventBus,
+		container.EventStore,
+		container.ExecutionStore,
+		container.IdempotencyStore,
 		container.SQL,
 		container.Mongo,
 		map[string]*grpc.ClientConn{
-			"user": container.UserConn,
+			"user":   container.UserConn,
+			"pubsub": container.PubSubConn,
 		},
 	)
 
-	grpcUserServer := usergrpc.NewServer(container.CommandBus, container.UserPersistenceRepository)
+	grpcUserServer := usergrpc.NewServer(container.CommandBus, container.CommandRegistry, container.UserPersistenceRepository, token.NewPolicy(cfg))
 	userproto.RegisterUserServiceServer(grpcServer, grpcUserServer)
 
 	app := application.New()
@@ -99,6 +109,7 @@ func main() {
 			fmt.Sprintf("%s:%d", cfg.GRPC.Host, cfg.GRPC.Port),
 			grpcServer,
 		),
+		container.CommandScheduler,
 	)
 
 	if cfg.App.Environment == "development" {
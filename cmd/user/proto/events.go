@@ -0,0 +1,34 @@
+package proto
+
+import (
+	"github.com/google/uuid"
+)
+
+// Integration events published by user service, other services can subscribe to them with pkg/eventbus/pubsub
+const (
+	// UserWasDeletedEventType is the type of event published once user account was deleted
+	UserWasDeletedEventType = "user.integration.UserWasDeleted"
+	// UserRoleWasRevokedEventType is the type of event published once role was revoked from user
+	UserRoleWasRevokedEventType = "user.integration.UserRoleWasRevoked"
+)
+
+// UserWasDeleted integration event, services should remove all data they keep for the user
+type UserWasDeleted struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// GetType returns event type
+func (e UserWasDeleted) GetType() string {
+	return UserWasDeletedEventType
+}
+
+// UserRoleWasRevoked integration event, roles are carried by access tokens
+// so services should revoke access tokens they issued for the user
+type UserRoleWasRevoked struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// GetType returns event type
+func (e UserRoleWasRevoked) GetType() string {
+	return UserRoleWasRevokedEventType
+}
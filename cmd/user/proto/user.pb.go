@@ -11,6 +11,7 @@ import (
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	math "math"
 )
 
@@ -75,13 +76,18 @@ func (m *DispatchUserCommandRequest) GetPayload() []byte {
 
 // User object
 type User struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email                string   `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	FacebookId           string   `protobuf:"bytes,3,opt,name=facebookId,proto3" json:"facebookId,omitempty"`
-	GoogleId             string   `protobuf:"bytes,4,opt,name=googleId,proto3" json:"googleId,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int64    `json:"-"`
+	Id                   string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email                string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	FacebookId           string                 `protobuf:"bytes,3,opt,name=facebookId,proto3" json:"facebookId,omitempty"`
+	GoogleId             string                 `protobuf:"bytes,4,opt,name=googleId,proto3" json:"googleId,omitempty"`
+	Name                 string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	AvatarUrl            string                 `protobuf:"bytes,6,opt,name=avatarUrl,proto3" json:"avatarUrl,omitempty"`
+	Locale               string                 `protobuf:"bytes,7,opt,name=locale,proto3" json:"locale,omitempty"`
+	Timezone             string                 `protobuf:"bytes,8,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	RegisteredAt         *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=registeredAt,proto3" json:"registeredAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int64                  `json:"-"`
 }
 
 func (m *User) Reset()         { *m = User{} }
@@ -137,6 +143,41 @@ func (m *User) GetGoogleId() string {
 	return ""
 }
 
+func (m *User) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *User) GetAvatarUrl() string {
+	if m != nil {
+		return m.AvatarUrl
+	}
+	return ""
+}
+
+func (m *User) GetLocale() string {
+	if m != nil {
+		return m.Locale
+	}
+	return ""
+}
+
+func (m *User) GetTimezone() string {
+	if m != nil {
+		return m.Timezone
+	}
+	return ""
+}
+
+func (m *User) GetRegisteredAt() *timestamppb.Timestamp {
+	if m != nil {
+		return m.RegisteredAt
+	}
+	return nil
+}
+
 // GetUserRequest is a request data to read user
 type GetUserRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -177,13 +218,19 @@ func (m *GetUserRequest) GetId() string {
 	return ""
 }
 
-// ListUserRequest is a request data to read all user for a given page
+// ListUserRequest is a request data to read page of users matching filters, page starts after cursor
 type ListUserRequest struct {
-	Page                 int64    `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
-	Limit                int64    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int64    `json:"-"`
+	Limit                int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Email                string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Provider             string                 `protobuf:"bytes,4,opt,name=provider,proto3" json:"provider,omitempty"`
+	Role                 string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`
+	RegisteredAfter      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=registeredAfter,proto3" json:"registeredAfter,omitempty"`
+	RegisteredBefore     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=registeredBefore,proto3" json:"registeredBefore,omitempty"`
+	Sort                 string                 `protobuf:"bytes,8,opt,name=sort,proto3" json:"sort,omitempty"`
+	Cursor               string                 `protobuf:"bytes,9,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int64                  `json:"-"`
 }
 
 func (m *ListUserRequest) Reset()         { *m = ListUserRequest{} }
@@ -211,26 +258,68 @@ func (m *ListUserRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ListUserRequest proto.InternalMessageInfo
 
-func (m *ListUserRequest) GetPage() int64 {
+func (m *ListUserRequest) GetLimit() int64 {
 	if m != nil {
-		return m.Page
+		return m.Limit
 	}
 	return 0
 }
 
-func (m *ListUserRequest) GetLimit() int64 {
+func (m *ListUserRequest) GetEmail() string {
 	if m != nil {
-		return m.Limit
+		return m.Email
 	}
-	return 0
+	return ""
 }
 
-// ListUserResponse list of all users
+func (m *ListUserRequest) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
+func (m *ListUserRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *ListUserRequest) GetRegisteredAfter() *timestamppb.Timestamp {
+	if m != nil {
+		return m.RegisteredAfter
+	}
+	return nil
+}
+
+func (m *ListUserRequest) GetRegisteredBefore() *timestamppb.Timestamp {
+	if m != nil {
+		return m.RegisteredBefore
+	}
+	return nil
+}
+
+func (m *ListUserRequest) GetSort() string {
+	if m != nil {
+		return m.Sort
+	}
+	return ""
+}
+
+func (m *ListUserRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+// ListUserResponse page of users, nextCursor is empty on the last page
 type ListUserResponse struct {
 	Users                []*User  `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
-	Page                 int64    `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
 	Limit                int64    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
 	Total                int64    `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	NextCursor           string   `protobuf:"bytes,5,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int64    `json:"-"`
@@ -268,13 +357,6 @@ func (m *ListUserResponse) GetUsers() []*User {
 	return nil
 }
 
-func (m *ListUserResponse) GetPage() int64 {
-	if m != nil {
-		return m.Page
-	}
-	return 0
-}
-
 func (m *ListUserResponse) GetLimit() int64 {
 	if m != nil {
 		return m.Limit
@@ -289,12 +371,183 @@ func (m *ListUserResponse) GetTotal() int64 {
 	return 0
 }
 
+func (m *ListUserResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+// AuthenticateWithPasswordRequest is a request data to verify user credentials
+type AuthenticateWithPasswordRequest struct {
+	Email                string   `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password             string   `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int64    `json:"-"`
+}
+
+func (m *AuthenticateWithPasswordRequest) Reset()         { *m = AuthenticateWithPasswordRequest{} }
+func (m *AuthenticateWithPasswordRequest) String() string { return proto.CompactTextString(m) }
+func (*AuthenticateWithPasswordRequest) ProtoMessage()    {}
+func (*AuthenticateWithPasswordRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_116e343673f7ffaf, []int{5}
+}
+
+func (m *AuthenticateWithPasswordRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthenticateWithPasswordRequest.Unmarshal(m, b)
+}
+func (m *AuthenticateWithPasswordRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthenticateWithPasswordRequest.Marshal(b, m, deterministic)
+}
+func (m *AuthenticateWithPasswordRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthenticateWithPasswordRequest.Merge(m, src)
+}
+func (m *AuthenticateWithPasswordRequest) XXX_Size() int {
+	return xxx_messageInfo_AuthenticateWithPasswordRequest.Size(m)
+}
+func (m *AuthenticateWithPasswordRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthenticateWithPasswordRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthenticateWithPasswordRequest proto.InternalMessageInfo
+
+func (m *AuthenticateWithPasswordRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *AuthenticateWithPasswordRequest) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+// AuthenticateWithLoginCodeRequest is a request data to use up login code sent by email,
+// otp is one-time password or recovery code required from users with multi-factor authentication enabled
+type AuthenticateWithLoginCodeRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code                 string   `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Otp                  string   `protobuf:"bytes,3,opt,name=otp,proto3" json:"otp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int64    `json:"-"`
+}
+
+func (m *AuthenticateWithLoginCodeRequest) Reset()         { *m = AuthenticateWithLoginCodeRequest{} }
+func (m *AuthenticateWithLoginCodeRequest) String() string { return proto.CompactTextString(m) }
+func (*AuthenticateWithLoginCodeRequest) ProtoMessage()    {}
+func (*AuthenticateWithLoginCodeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_116e343673f7ffaf, []int{6}
+}
+
+func (m *AuthenticateWithLoginCodeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthenticateWithLoginCodeRequest.Unmarshal(m, b)
+}
+func (m *AuthenticateWithLoginCodeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthenticateWithLoginCodeRequest.Marshal(b, m, deterministic)
+}
+func (m *AuthenticateWithLoginCodeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthenticateWithLoginCodeRequest.Merge(m, src)
+}
+func (m *AuthenticateWithLoginCodeRequest) XXX_Size() int {
+	return xxx_messageInfo_AuthenticateWithLoginCodeRequest.Size(m)
+}
+func (m *AuthenticateWithLoginCodeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthenticateWithLoginCodeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthenticateWithLoginCodeRequest proto.InternalMessageInfo
+
+func (m *AuthenticateWithLoginCodeRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *AuthenticateWithLoginCodeRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *AuthenticateWithLoginCodeRequest) GetOtp() string {
+	if m != nil {
+		return m.Otp
+	}
+	return ""
+}
+
+// AuthenticateWithLoginCodeResponse user login code was issued to along with roles and permissions access token is granted
+type AuthenticateWithLoginCodeResponse struct {
+	User                 *User    `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Role                 string   `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Permissions          []string `protobuf:"bytes,3,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int64    `json:"-"`
+}
+
+func (m *AuthenticateWithLoginCodeResponse) Reset()         { *m = AuthenticateWithLoginCodeResponse{} }
+func (m *AuthenticateWithLoginCodeResponse) String() string { return proto.CompactTextString(m) }
+func (*AuthenticateWithLoginCodeResponse) ProtoMessage()    {}
+func (*AuthenticateWithLoginCodeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_116e343673f7ffaf, []int{7}
+}
+
+func (m *AuthenticateWithLoginCodeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthenticateWithLoginCodeResponse.Unmarshal(m, b)
+}
+func (m *AuthenticateWithLoginCodeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthenticateWithLoginCodeResponse.Marshal(b, m, deterministic)
+}
+func (m *AuthenticateWithLoginCodeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthenticateWithLoginCodeResponse.Merge(m, src)
+}
+func (m *AuthenticateWithLoginCodeResponse) XXX_Size() int {
+	return xxx_messageInfo_AuthenticateWithLoginCodeResponse.Size(m)
+}
+func (m *AuthenticateWithLoginCodeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthenticateWithLoginCodeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthenticateWithLoginCodeResponse proto.InternalMessageInfo
+
+func (m *AuthenticateWithLoginCodeResponse) GetUser() *User {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+func (m *AuthenticateWithLoginCodeResponse) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *AuthenticateWithLoginCodeResponse) GetPermissions() []string {
+	if m != nil {
+		return m.Permissions
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*DispatchUserCommandRequest)(nil), "proto.DispatchUserCommandRequest")
 	proto.RegisterType((*User)(nil), "proto.User")
 	proto.RegisterType((*GetUserRequest)(nil), "proto.GetUserRequest")
 	proto.RegisterType((*ListUserRequest)(nil), "proto.ListUserRequest")
 	proto.RegisterType((*ListUserResponse)(nil), "proto.ListUserResponse")
+	proto.RegisterType((*AuthenticateWithPasswordRequest)(nil), "proto.AuthenticateWithPasswordRequest")
+	proto.RegisterType((*AuthenticateWithLoginCodeRequest)(nil), "proto.AuthenticateWithLoginCodeRequest")
+	proto.RegisterType((*AuthenticateWithLoginCodeResponse)(nil), "proto.AuthenticateWithLoginCodeResponse")
 }
 
 func init() {
@@ -302,31 +555,51 @@ func init() {
 }
 
 var fileDescriptor_116e343673f7ffaf = []byte{
-	// 384 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x51, 0x41, 0xcf, 0xd2, 0x40,
-	0x10, 0x4d, 0x4b, 0x2b, 0x76, 0x30, 0x68, 0x46, 0xc5, 0xa6, 0x24, 0xa6, 0xf4, 0x44, 0x4c, 0x2c,
-	0x09, 0x1e, 0xf5, 0x84, 0x1a, 0x83, 0xf1, 0x40, 0x6a, 0xfc, 0x01, 0xdb, 0x76, 0x29, 0x1b, 0x5b,
-	0x76, 0xed, 0x6e, 0x4d, 0xf8, 0x8f, 0xfe, 0x28, 0xb3, 0xbb, 0x2d, 0x02, 0xf2, 0x9d, 0x76, 0xde,
-	0xcc, 0xdb, 0x79, 0x33, 0x6f, 0x00, 0x3a, 0x49, 0xdb, 0x54, 0xb4, 0x5c, 0x71, 0xf4, 0xcd, 0x13,
-	0xcd, 0x2b, 0xce, 0xab, 0x9a, 0xae, 0x0c, 0xca, 0xbb, 0xfd, 0x8a, 0x36, 0x42, 0x9d, 0x2c, 0x27,
-	0xf9, 0x0a, 0xd1, 0x27, 0x26, 0x05, 0x51, 0xc5, 0xe1, 0x87, 0xa4, 0xed, 0x47, 0xde, 0x34, 0xe4,
-	0x58, 0x66, 0xf4, 0x57, 0x47, 0xa5, 0x42, 0x04, 0xef, 0x48, 0x1a, 0x1a, 0x3a, 0xb1, 0xb3, 0x0c,
-	0x32, 0x13, 0x63, 0x08, 0x63, 0x41, 0x4e, 0x35, 0x27, 0x65, 0xe8, 0xc6, 0xce, 0xf2, 0x49, 0x36,
-	0xc0, 0xe4, 0x00, 0x9e, 0xee, 0x81, 0x53, 0x70, 0x59, 0xd9, 0xff, 0x71, 0x59, 0x89, 0x2f, 0xc0,
-	0xa7, 0x0d, 0x61, 0xb5, 0xe1, 0x07, 0x99, 0x05, 0xf8, 0x1a, 0x60, 0x4f, 0x0a, 0x9a, 0x73, 0xfe,
-	0x73, 0x5b, 0x86, 0x23, 0x53, 0xba, 0xc8, 0x60, 0x04, 0x8f, 0xed, 0xe0, 0xdb, 0x32, 0xf4, 0x4c,
-	0xf5, 0x8c, 0x93, 0x18, 0xa6, 0x5f, 0xa8, 0xd2, 0x62, 0xc3, 0xa4, 0x37, 0x9a, 0xc9, 0x7b, 0x78,
-	0xfa, 0x8d, 0xc9, 0x2b, 0x0a, 0x82, 0x27, 0x48, 0x65, 0x97, 0xf1, 0x33, 0x13, 0xeb, 0xd1, 0x6a,
-	0xd6, 0x30, 0x65, 0x46, 0xf3, 0x33, 0x0b, 0x92, 0x0e, 0x9e, 0xfd, 0xfb, 0x2c, 0x05, 0x3f, 0x4a,
-	0x8a, 0x0b, 0xf0, 0xb5, 0xb5, 0x32, 0x74, 0xe2, 0xd1, 0x72, 0xb2, 0x9e, 0x58, 0xff, 0x52, 0xc3,
-	0xb1, 0x95, 0xb3, 0x80, 0x7b, 0x4f, 0x60, 0x74, 0x21, 0xa0, 0xb3, 0x8a, 0x2b, 0x52, 0x9b, 0xc5,
-	0xfc, 0xcc, 0x82, 0xf5, 0x1f, 0x07, 0x26, 0xba, 0xdf, 0x77, 0xda, 0xfe, 0x66, 0x05, 0xc5, 0x1d,
-	0x3c, 0xbf, 0x73, 0x1b, 0x5c, 0xf4, 0xd2, 0x0f, 0xdf, 0x2d, 0x9a, 0xa5, 0xd6, 0xaa, 0x74, 0xb8,
-	0x79, 0xfa, 0x59, 0xdf, 0x1c, 0xdf, 0xc2, 0xb8, 0xf7, 0x0d, 0x5f, 0xf6, 0x5d, 0xae, 0x7d, 0x8c,
-	0x2e, 0xf7, 0xc2, 0x0f, 0x10, 0x0c, 0x3e, 0x48, 0x9c, 0xf5, 0x95, 0x1b, 0x5b, 0xa3, 0x57, 0xff,
-	0xe5, 0xad, 0x63, 0x9b, 0x37, 0x30, 0xaf, 0x38, 0x11, 0x2c, 0xe7, 0xac, 0xa6, 0xad, 0xa8, 0x89,
-	0xa2, 0x69, 0xd5, 0x8a, 0xc2, 0xf2, 0x37, 0x81, 0x26, 0xef, 0x74, 0xb8, 0x73, 0xf2, 0x47, 0x26,
-	0xf7, 0xee, 0x6f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x1f, 0x3e, 0xc4, 0x2b, 0xbf, 0x02, 0x00, 0x00,
+	// 691 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x94, 0xdd, 0x6e, 0xdb, 0x36,
+	0x14, 0xc7, 0x21, 0xcb, 0x76, 0xa2, 0xe3, 0x20, 0x09, 0xb8, 0x2c, 0xd3, 0xb4, 0x61, 0x51, 0x74,
+	0xb1, 0x79, 0x17, 0x73, 0x80, 0xec, 0xb6, 0x28, 0x90, 0x8f, 0xb6, 0x48, 0x11, 0xa0, 0x86, 0xd2,
+	0xa0, 0xbd, 0xa5, 0xa5, 0x63, 0x87, 0xa8, 0x24, 0xaa, 0x24, 0x9d, 0x36, 0xb9, 0xeb, 0x03, 0xb4,
+	0xaf, 0xd1, 0xd7, 0x2c, 0x48, 0x51, 0xb6, 0xac, 0xd4, 0xc9, 0x95, 0x78, 0x3e, 0xf8, 0xe7, 0xd1,
+	0xef, 0x1c, 0x12, 0x60, 0x2e, 0x51, 0x8c, 0x4a, 0xc1, 0x15, 0x27, 0x3d, 0xf3, 0x09, 0xfe, 0x98,
+	0x71, 0x3e, 0xcb, 0xf0, 0xc8, 0x58, 0x93, 0xf9, 0xf4, 0x08, 0xf3, 0x52, 0xdd, 0x55, 0x39, 0xc1,
+	0x41, 0x3b, 0xa8, 0x58, 0x8e, 0x52, 0xd1, 0xbc, 0xac, 0x12, 0xa2, 0xd7, 0x10, 0x9c, 0x33, 0x59,
+	0x52, 0x95, 0xdc, 0x5c, 0x4b, 0x14, 0x67, 0x3c, 0xcf, 0x69, 0x91, 0xc6, 0xf8, 0x71, 0x8e, 0x52,
+	0x11, 0x02, 0xdd, 0x82, 0xe6, 0xe8, 0x3b, 0xa1, 0x33, 0xf4, 0x62, 0xb3, 0x26, 0x3e, 0x6c, 0x94,
+	0xf4, 0x2e, 0xe3, 0x34, 0xf5, 0x3b, 0xa1, 0x33, 0xdc, 0x8a, 0x6b, 0x33, 0xfa, 0xd6, 0x81, 0xae,
+	0x16, 0x21, 0xdb, 0xd0, 0x61, 0xa9, 0xdd, 0xd4, 0x61, 0x29, 0xd9, 0x83, 0x1e, 0xe6, 0x94, 0x65,
+	0x66, 0x83, 0x17, 0x57, 0x06, 0xf9, 0x0b, 0x60, 0x4a, 0x13, 0x9c, 0x70, 0xfe, 0xe1, 0x22, 0xf5,
+	0x5d, 0x13, 0x6a, 0x78, 0x48, 0x00, 0x9b, 0x55, 0xf5, 0x17, 0xa9, 0xdf, 0x35, 0xd1, 0x85, 0xbd,
+	0x28, 0xac, 0xd7, 0x28, 0xec, 0x4f, 0xf0, 0xe8, 0x2d, 0x55, 0x54, 0x5c, 0x8b, 0xcc, 0xef, 0x9b,
+	0xc0, 0xd2, 0x41, 0xf6, 0xa1, 0x9f, 0xf1, 0x84, 0x66, 0xe8, 0x6f, 0x98, 0x90, 0xb5, 0xf4, 0x29,
+	0x9a, 0xc9, 0x3d, 0x2f, 0xd0, 0xdf, 0xac, 0x4e, 0xa9, 0x6d, 0xf2, 0x1c, 0xb6, 0x04, 0xce, 0x98,
+	0x54, 0x28, 0x30, 0x3d, 0x51, 0xbe, 0x17, 0x3a, 0xc3, 0xc1, 0x71, 0x30, 0xaa, 0xca, 0x18, 0xd5,
+	0x50, 0x47, 0x6f, 0x6b, 0xa8, 0xf1, 0x4a, 0x7e, 0x14, 0xc2, 0xf6, 0x2b, 0x54, 0x1a, 0x49, 0x0d,
+	0xb4, 0x45, 0x26, 0xfa, 0xde, 0x81, 0x9d, 0x4b, 0x26, 0x57, 0x72, 0xf6, 0xa0, 0x97, 0xb1, 0x9c,
+	0x29, 0x43, 0xcb, 0x8d, 0x2b, 0x63, 0xc9, 0xd0, 0x6d, 0x32, 0x0c, 0x60, 0xb3, 0x14, 0xfc, 0x96,
+	0xa5, 0x28, 0x6a, 0x46, 0xb5, 0xad, 0x19, 0x09, 0x9e, 0x2d, 0x18, 0xe9, 0x35, 0x39, 0x87, 0x9d,
+	0x46, 0x85, 0x53, 0x85, 0xc2, 0xef, 0x3f, 0xf9, 0x53, 0xed, 0x2d, 0xe4, 0x25, 0xec, 0x2e, 0x5d,
+	0xa7, 0x38, 0xe5, 0xa2, 0xa2, 0xfa, 0xb8, 0xcc, 0x83, 0x3d, 0xba, 0x42, 0xc9, 0x85, 0xb2, 0xdc,
+	0xcd, 0x5a, 0xf7, 0x29, 0x99, 0x0b, 0xc9, 0x85, 0xa1, 0xed, 0xc5, 0xd6, 0x8a, 0xbe, 0x38, 0xb0,
+	0xbb, 0x24, 0x25, 0x4b, 0x5e, 0x48, 0x24, 0x87, 0xd0, 0xd3, 0x17, 0x42, 0xfa, 0x4e, 0xe8, 0x0e,
+	0x07, 0xc7, 0x83, 0xea, 0xd8, 0x91, 0xc9, 0xa9, 0x22, 0x4b, 0x9a, 0x6e, 0x8b, 0xa6, 0xe2, 0x8a,
+	0x66, 0x06, 0x9a, 0x1b, 0x57, 0x86, 0x9e, 0xc8, 0x02, 0x3f, 0xab, 0xb3, 0xea, 0xfc, 0x8a, 0x5b,
+	0xc3, 0x13, 0x5d, 0xc1, 0xc1, 0xc9, 0x5c, 0xdd, 0x60, 0xa1, 0x58, 0x42, 0x15, 0xbe, 0x63, 0xea,
+	0x66, 0x4c, 0xa5, 0xfc, 0xc4, 0x45, 0xda, 0x68, 0x5e, 0xd5, 0x26, 0xa7, 0xdd, 0x26, 0x9b, 0x68,
+	0xef, 0xc0, 0xc2, 0x8e, 0xde, 0x43, 0xd8, 0x16, 0xbd, 0xe4, 0x33, 0x56, 0x9c, 0xf1, 0x14, 0xd7,
+	0x8c, 0x8d, 0x06, 0x97, 0xf0, 0x14, 0xad, 0x96, 0x59, 0x93, 0x5d, 0x70, 0xb9, 0x2a, 0xed, 0x78,
+	0xe8, 0x65, 0x74, 0x0f, 0x87, 0x8f, 0x28, 0x5b, 0x84, 0x07, 0xd0, 0xd5, 0xa0, 0x8c, 0x78, 0x8b,
+	0xa0, 0x09, 0x2c, 0xc6, 0xa8, 0xd3, 0x18, 0xa3, 0x10, 0x06, 0x25, 0x8a, 0x9c, 0x49, 0xc9, 0x78,
+	0x21, 0x7d, 0x37, 0x74, 0x87, 0x5e, 0xdc, 0x74, 0x1d, 0x7f, 0x75, 0x61, 0xa0, 0x45, 0xae, 0x50,
+	0xdc, 0xb2, 0x04, 0xc9, 0x18, 0x7e, 0xf9, 0xc9, 0x3b, 0x43, 0x0e, 0xed, 0x79, 0xeb, 0xdf, 0xa0,
+	0x60, 0xff, 0xc1, 0x48, 0xbd, 0xd0, 0x0f, 0x1c, 0xf9, 0x0f, 0x36, 0xec, 0xe5, 0x22, 0xbf, 0x5a,
+	0x95, 0xd5, 0xcb, 0x16, 0x34, 0x7f, 0x86, 0x3c, 0x03, 0xaf, 0x1e, 0x1f, 0x49, 0xf6, 0x6d, 0xa4,
+	0x75, 0xf5, 0x82, 0xdf, 0x1e, 0xf8, 0x2d, 0xa5, 0x37, 0xe0, 0xaf, 0xeb, 0x3c, 0xf9, 0xdb, 0x6e,
+	0x7a, 0x62, 0x34, 0x56, 0xcb, 0x29, 0xe0, 0xf7, 0xb5, 0xbd, 0x21, 0xff, 0xac, 0x51, 0x6c, 0xcf,
+	0x45, 0x30, 0x7c, 0x3a, 0xb1, 0xfa, 0x81, 0xd3, 0x7f, 0x21, 0x98, 0x71, 0x5a, 0xb2, 0x09, 0x67,
+	0x19, 0x8a, 0x32, 0xa3, 0x0a, 0x47, 0x33, 0x51, 0x26, 0x23, 0xdd, 0xe3, 0x53, 0x4f, 0xd7, 0x34,
+	0xd6, 0x52, 0x63, 0x67, 0xd2, 0x37, 0x9a, 0xff, 0xff, 0x18, 0x00, 0x59, 0x26, 0x0b, 0xf2, 0x6c,
+	0x06, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -344,6 +617,8 @@ type UserServiceClient interface {
 	DispatchUserCommand(ctx context.Context, in *DispatchUserCommandRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error)
 	ListUsers(ctx context.Context, in *ListUserRequest, opts ...grpc.CallOption) (*ListUserResponse, error)
+	AuthenticateWithPassword(ctx context.Context, in *AuthenticateWithPasswordRequest, opts ...grpc.CallOption) (*User, error)
+	AuthenticateWithLoginCode(ctx context.Context, in *AuthenticateWithLoginCodeRequest, opts ...grpc.CallOption) (*AuthenticateWithLoginCodeResponse, error)
 }
 
 type userServiceClient struct {
@@ -381,11 +656,31 @@ func (c *userServiceClient) ListUsers(ctx context.Context, in *ListUserRequest,
 	return out, nil
 }
 
+func (c *userServiceClient) AuthenticateWithPassword(ctx context.Context, in *AuthenticateWithPasswordRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/proto.UserService/AuthenticateWithPassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) AuthenticateWithLoginCode(ctx context.Context, in *AuthenticateWithLoginCodeRequest, opts ...grpc.CallOption) (*AuthenticateWithLoginCodeResponse, error) {
+	out := new(AuthenticateWithLoginCodeResponse)
+	err := c.cc.Invoke(ctx, "/proto.UserService/AuthenticateWithLoginCode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 type UserServiceServer interface {
 	DispatchUserCommand(context.Context, *DispatchUserCommandRequest) (*emptypb.Empty, error)
 	GetUser(context.Context, *GetUserRequest) (*User, error)
 	ListUsers(context.Context, *ListUserRequest) (*ListUserResponse, error)
+	AuthenticateWithPassword(context.Context, *AuthenticateWithPasswordRequest) (*User, error)
+	AuthenticateWithLoginCode(context.Context, *AuthenticateWithLoginCodeRequest) (*AuthenticateWithLoginCodeResponse, error)
 }
 
 // UnimplementedUserServiceServer can be embedded to have forward compatible implementations.
@@ -401,6 +696,12 @@ func (*UnimplementedUserServiceServer) GetUser(ctx context.Context, req *GetUser
 func (*UnimplementedUserServiceServer) ListUsers(ctx context.Context, req *ListUserRequest) (*ListUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
 }
+func (*UnimplementedUserServiceServer) AuthenticateWithPassword(ctx context.Context, req *AuthenticateWithPasswordRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthenticateWithPassword not implemented")
+}
+func (*UnimplementedUserServiceServer) AuthenticateWithLoginCode(ctx context.Context, req *AuthenticateWithLoginCodeRequest) (*AuthenticateWithLoginCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthenticateWithLoginCode not implemented")
+}
 
 func RegisterUserServiceServer(s *grpc.Server, srv UserServiceServer) {
 	s.RegisterService(&_UserService_serviceDesc, srv)
@@ -460,6 +761,42 @@ func _UserService_ListUsers_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_AuthenticateWithPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticateWithPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).AuthenticateWithPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.UserService/AuthenticateWithPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).AuthenticateWithPassword(ctx, req.(*AuthenticateWithPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_AuthenticateWithLoginCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticateWithLoginCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).AuthenticateWithLoginCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.UserService/AuthenticateWithLoginCode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).AuthenticateWithLoginCode(ctx, req.(*AuthenticateWithLoginCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _UserService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.UserService",
 	HandlerType: (*UserServiceServer)(nil),
@@ -476,6 +813,14 @@ var _UserService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListUsers",
 			Handler:    _UserService_ListUsers_Handler,
 		},
+		{
+			MethodName: "AuthenticateWithPassword",
+			Handler:    _UserService_AuthenticateWithPassword_Handler,
+		},
+		{
+			MethodName: "AuthenticateWithLoginCode",
+			Handler:    _UserService_AuthenticateWithLoginCode_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "user.proto",
@@ -35,9 +35,21 @@ resource "kubernetes_secret" "config" {
     GRPC_CLIENT_TIMEOUT = "20s"
 
     COMMAND_BUS_BUFFER = "100"
+    COMMAND_BUS_HANDLER_TIMEOUT = "30s"
+
+    # replay outcome of requests with the same Idempotency-Key for 24 hours
+    IDEMPOTENCY_KEY_TTL = "24h"
+    # keep status of asynchronously dispatched commands for 24 hours
+    COMMAND_EXECUTION_TTL = "24h"
+    # look up due scheduled commands every second, lock claimed command for other replicas for 1 minute
+    COMMAND_SCHEDULER_POLL_INTERVAL = "1s"
+    COMMAND_SCHEDULER_LEASE = "1m"
 
     # wait 15 sec for oauth server to initialize
     OAUTH_INIT_TIMEOUT = "15s"
+    # login codes sent by email expire after 10 minutes, access tokens they are exchanged for after 15 minutes
+    LOGIN_CODE_TTL = "10m"
+    OAUTH_LOGIN_ACCESS_TOKEN_TTL = "15m"
 
     MONGO_HOST = "mongodb"
     MONGO_PORT = 27017
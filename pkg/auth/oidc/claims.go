@@ -0,0 +1,111 @@
+package oidc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidProfile is returned when userinfo response does not identify user
+var ErrInvalidProfile = fmt.Errorf("invalid userinfo response")
+
+// Claims are names of userinfo claims profile is mapped from,
+// nested claims are separated with dots (e.g. picture.data.url), empty names fall back to DefaultClaims
+type Claims struct {
+	Subject       string `json:"subject,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified string `json:"email_verified,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Picture       string `json:"picture,omitempty"`
+	Locale        string `json:"locale,omitempty"`
+}
+
+// DefaultClaims are standard OpenID Connect claims
+var DefaultClaims = Claims{
+	Subject:       "sub",
+	Email:         "email",
+	EmailVerified: "email_verified",
+	Name:          "name",
+	Picture:       "picture",
+	Locale:        "locale",
+}
+
+// Profile of user authenticated by provider
+type Profile struct {
+	Subject       string // identifies user within provider
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+	Locale        string
+}
+
+func (c Claims) withDefaults() Claims {
+	return Claims{
+		Subject:       fallback(c.Subject, DefaultClaims.Subject),
+		Email:         fallback(c.Email, DefaultClaims.Email),
+		EmailVerified: fallback(c.EmailVerified, DefaultClaims.EmailVerified),
+		Name:          fallback(c.Name, DefaultClaims.Name),
+		Picture:       fallback(c.Picture, DefaultClaims.Picture),
+		Locale:        fallback(c.Locale, DefaultClaims.Locale),
+	}
+}
+
+// profile maps userinfo response, email is considered verified if provider says so or is trusted to verify emails
+func (c Claims) profile(userInfo []byte, trustEmail bool) (Profile, error) {
+	var claims map[string]interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(userInfo))
+	dec.UseNumber() // numeric ids (e.g. github) are kept as they are
+	if err := dec.Decode(&claims); err != nil {
+		return Profile{}, fmt.Errorf("%w: %s", ErrInvalidProfile, err)
+	}
+
+	p := Profile{
+		Subject: claimString(claims, c.Subject),
+		Email:   claimString(claims, c.Email),
+		Name:    claimString(claims, c.Name),
+		Picture: claimString(claims, c.Picture),
+		Locale:  claimString(claims, c.Locale),
+	}
+	if p.Subject == "" {
+		return Profile{}, fmt.Errorf("%w: missing %s claim", ErrInvalidProfile, c.Subject)
+	}
+
+	// some providers return email_verified as string
+	verified, _ := strconv.ParseBool(claimString(claims, c.EmailVerified))
+	p.EmailVerified = p.Email != "" && (trustEmail || verified)
+
+	return p, nil
+}
+
+func claimString(claims map[string]interface{}, name string) string {
+	var v interface{} = claims
+	for _, key := range strings.Split(name, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		v = m[key]
+	}
+
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
+
+func fallback(name, defaultName string) string {
+	if name == "" {
+		return defaultName
+	}
+	return name
+}
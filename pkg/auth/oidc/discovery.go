@@ -0,0 +1,76 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DiscoveryPath is appended to issuer to get provider metadata
+const DiscoveryPath = "/.well-known/openid-configuration"
+
+// maxResponseSize limits size of provider responses read into memory
+const maxResponseSize = 1 << 20
+
+// ErrDiscovery is returned when provider metadata could not be discovered
+var ErrDiscovery = fmt.Errorf("openid connect discovery failed")
+
+// Metadata of OpenID Connect provider
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// Discover fetches metadata from issuer discovery document,
+// issuer of the document has to match requested one
+func Discover(ctx context.Context, client *http.Client, issuer string) (Metadata, error) {
+	var m Metadata
+
+	url := strings.TrimSuffix(issuer, "/") + DiscoveryPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return m, fmt.Errorf("%w: %s", ErrDiscovery, err)
+	}
+
+	body, err := do(client, req)
+	if err != nil {
+		return m, fmt.Errorf("%w: %s", ErrDiscovery, err)
+	}
+
+	if err := json.Unmarshal(body, &m); err != nil {
+		return m, fmt.Errorf("%w: %s", ErrDiscovery, err)
+	}
+	if m.Issuer != issuer {
+		return m, fmt.Errorf("%w: issuer %q does not match %q", ErrDiscovery, m.Issuer, issuer)
+	}
+	if m.AuthorizationEndpoint == "" || m.TokenEndpoint == "" || m.UserInfoEndpoint == "" {
+		return m, fmt.Errorf("%w: %s does not provide authorization, token and userinfo endpoints", ErrDiscovery, issuer)
+	}
+
+	return m, nil
+}
+
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s responded with %s", req.Method, req.URL, resp.Status)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,4 @@
+/*
+Package oidc provides OpenID Connect and OAuth 2.0 social login providers configured with discovery and claim mapping
+*/
+package oidc
@@ -0,0 +1,177 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stub OpenID Connect provider issuing "token" for "code" and returning userinfo claims for it
+func newStubProvider(t *testing.T, userInfo string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc(DiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Metadata{
+			Issuer:                srv.URL,
+			AuthorizationEndpoint: srv.URL + "/authorize",
+			TokenEndpoint:         srv.URL + "/token",
+			UserInfoEndpoint:      srv.URL + "/userinfo",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "code" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, userInfo)
+	})
+
+	return srv
+}
+
+func TestProvider(t *testing.T) {
+	srv := newStubProvider(t, `{"id":42,"email":"test@test.com","email_verified":"true","name":"Jane Doe","avatar":{"url":"https://example.com/avatar.png"}}`)
+
+	p, err := NewProvider(Config{
+		Name:         "stub",
+		Issuer:       srv.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Claims:       Claims{Subject: "id", Picture: "avatar.url"},
+	}, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	authURL, err := p.AuthCodeURL(ctx, "https://example.com/callback", "state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := u.Scheme + "://" + u.Host + u.Path; got != srv.URL+"/authorize" {
+		t.Errorf("auth url = %s, want %s/authorize", got, srv.URL)
+	}
+	if u.Query().Get("state") != "state" || u.Query().Get("scope") != strings.Join(DefaultScopes, " ") {
+		t.Errorf("auth url query = %s", u.RawQuery)
+	}
+
+	token, err := p.Exchange(ctx, "https://example.com/callback", "code")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := p.Profile(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Profile{
+		Subject:       "42",
+		Email:         "test@test.com",
+		EmailVerified: true,
+		Name:          "Jane Doe",
+		Picture:       "https://example.com/avatar.png",
+	}
+	if !reflect.DeepEqual(profile, want) {
+		t.Errorf("profile = %+v, want %+v", profile, want)
+	}
+}
+
+func TestProviderInvalidCode(t *testing.T) {
+	srv := newStubProvider(t, `{}`)
+
+	p, err := NewProvider(Config{Name: "stub", Issuer: srv.URL, ClientID: "client"}, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := p.Exchange(context.Background(), "https://example.com/callback", "invalid"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestDiscoverIssuerMismatch(t *testing.T) {
+	srv := newStubProvider(t, `{}`)
+
+	if _, err := Discover(context.Background(), srv.Client(), srv.URL+"/tenant"); !errors.Is(err, ErrDiscovery) {
+		t.Errorf("error = %v, want %v", err, ErrDiscovery)
+	}
+}
+
+func TestClaimsProfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		userInfo   string
+		trustEmail bool
+		want       Profile
+		wantErr    error
+	}{
+		{"verified", `{"sub":"1","email":"test@test.com","email_verified":true}`, false, Profile{Subject: "1", Email: "test@test.com", EmailVerified: true}, nil},
+		{"not verified", `{"sub":"1","email":"test@test.com","email_verified":false}`, false, Profile{Subject: "1", Email: "test@test.com"}, nil},
+		{"verification unknown", `{"sub":"1","email":"test@test.com"}`, false, Profile{Subject: "1", Email: "test@test.com"}, nil},
+		{"trusted", `{"sub":"1","email":"test@test.com"}`, true, Profile{Subject: "1", Email: "test@test.com", EmailVerified: true}, nil},
+		{"no email", `{"sub":"1","email":null}`, true, Profile{Subject: "1"}, nil},
+		{"no subject", `{"email":"test@test.com"}`, false, Profile{}, ErrInvalidProfile},
+		{"invalid", `[]`, false, Profile{}, ErrInvalidProfile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DefaultClaims.profile([]byte(tt.userInfo), tt.trustEmail)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("profile = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewProviderInvalidConfig(t *testing.T) {
+	for _, c := range []Config{
+		{Name: "Not Valid", Issuer: "https://example.com", ClientID: "client"},
+		{Name: "stub", Issuer: "https://example.com"},
+		{Name: "stub", ClientID: "client", AuthURL: "https://example.com/authorize"},
+	} {
+		if _, err := NewProvider(c, nil); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("NewProvider(%+v) error = %v, want %v", c, err, ErrInvalidConfig)
+		}
+	}
+}
+
+func TestNewRegistryDuplicate(t *testing.T) {
+	p, err := NewProvider(Google("client", "secret"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewRegistry(p, p); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("error = %v, want %v", err, ErrInvalidConfig)
+	}
+}
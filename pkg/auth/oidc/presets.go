@@ -0,0 +1,33 @@
+package oidc
+
+import (
+	"golang.org/x/oauth2/facebook"
+)
+
+// Google returns config of google provider, endpoints are discovered
+func Google(clientID, clientSecret string) Config {
+	return Config{
+		Name:         "google",
+		Issuer:       "https://accounts.google.com",
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+	}
+}
+
+// Facebook returns config of facebook provider, graph api is used as userinfo endpoint
+func Facebook(clientID, clientSecret string) Config {
+	return Config{
+		Name:         "facebook",
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       []string{"public_profile", "email"},
+		AuthURL:      facebook.Endpoint.AuthURL,
+		TokenURL:     facebook.Endpoint.TokenURL,
+		UserInfoURL:  "https://graph.facebook.com/me?fields=id,name,email,picture",
+		Claims: Claims{
+			Subject: "id",
+			Picture: "picture.data.url",
+		},
+		TrustEmail: true,
+	}
+}
@@ -0,0 +1,179 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// ErrInvalidConfig is returned when provider is misconfigured
+var ErrInvalidConfig = fmt.Errorf("invalid provider config")
+
+// DefaultScopes are requested when provider config does not set any
+var DefaultScopes = []string{"openid", "email", "profile"}
+
+// provider name is used in urls and stored with user accounts
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Config of identity provider, endpoints are discovered from issuer unless all of them are set explicitly
+// which allows to use OAuth 2.0 providers without OpenID Connect support (e.g. github, facebook)
+type Config struct {
+	Name         string   `json:"-"`
+	Issuer       string   `json:"issuer,omitempty"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Scopes       []string `json:"scopes,omitempty"`
+	AuthURL      string   `json:"auth_url,omitempty"`
+	TokenURL     string   `json:"token_url,omitempty"`
+	UserInfoURL  string   `json:"userinfo_url,omitempty"`
+	Claims       Claims   `json:"claims,omitempty"`
+	TrustEmail   bool     `json:"trust_email,omitempty"` // provider verifies email addresses without returning email verified claim
+}
+
+// Configs of identity providers by name, decoded from JSON object
+type Configs map[string]Config
+
+// UnmarshalText decodes configs from JSON object, keys are used as provider names
+func (c *Configs) UnmarshalText(text []byte) error {
+	var configs map[string]Config
+	if err := json.Unmarshal(text, &configs); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, err)
+	}
+
+	*c = make(Configs, len(configs))
+	for name, config := range configs {
+		config.Name = name
+		(*c)[name] = config
+	}
+
+	return nil
+}
+
+// Provider authenticates users with OAuth 2.0 authorization code flow and reads their profile from userinfo endpoint
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *Metadata
+}
+
+// NewProvider validates config and returns provider, metadata is discovered on first use.
+// Requests are sent with http.DefaultClient if client is nil
+func NewProvider(config Config, client *http.Client) (*Provider, error) {
+	if !namePattern.MatchString(config.Name) {
+		return nil, fmt.Errorf("%w: name %q has to match %s", ErrInvalidConfig, config.Name, namePattern)
+	}
+	if config.ClientID == "" {
+		return nil, fmt.Errorf("%w: %s client id is required", ErrInvalidConfig, config.Name)
+	}
+	if config.Issuer == "" && (config.AuthURL == "" || config.TokenURL == "" || config.UserInfoURL == "") {
+		return nil, fmt.Errorf("%w: %s requires issuer or auth, token and userinfo urls", ErrInvalidConfig, config.Name)
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = DefaultScopes
+	}
+	config.Claims = config.Claims.withDefaults()
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Provider{config: config, client: client}, nil
+}
+
+// Name of provider
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL returns url of provider consent page
+func (p *Provider) AuthCodeURL(ctx context.Context, redirectURL, state string) (string, error) {
+	c, err := p.oauth2Config(ctx, redirectURL)
+	if err != nil {
+		return "", err
+	}
+
+	return c.AuthCodeURL(state), nil
+}
+
+// Exchange converts authorization code into token
+func (p *Provider) Exchange(ctx context.Context, redirectURL, code string) (*oauth2.Token, error) {
+	c, err := p.oauth2Config(ctx, redirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.client), code)
+}
+
+// Profile reads profile of user token was issued to from userinfo endpoint
+func (p *Provider) Profile(ctx context.Context, token *oauth2.Token) (Profile, error) {
+	m, err := p.discover(ctx)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.UserInfoEndpoint, nil)
+	if err != nil {
+		return Profile{}, err
+	}
+	token.SetAuthHeader(req)
+
+	body, err := do(p.client, req)
+	if err != nil {
+		return Profile{}, fmt.Errorf("%w: %s", ErrInvalidProfile, err)
+	}
+
+	return p.config.Claims.profile(body, p.config.TrustEmail)
+}
+
+func (p *Provider) oauth2Config(ctx context.Context, redirectURL string) (*oauth2.Config, error) {
+	m, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  m.AuthorizationEndpoint,
+			TokenURL: m.TokenEndpoint,
+		},
+		RedirectURL: redirectURL,
+		Scopes:      p.config.Scopes,
+	}, nil
+}
+
+// discover returns provider endpoints, explicitly configured ones take precedence over discovered,
+// successful discovery is cached so provider being unavailable at start up does not prevent other providers from working
+func (p *Provider) discover(ctx context.Context) (Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return *p.metadata, nil
+	}
+
+	m := Metadata{Issuer: p.config.Issuer}
+	if p.config.AuthURL == "" || p.config.TokenURL == "" || p.config.UserInfoURL == "" {
+		discovered, err := Discover(ctx, p.client, p.config.Issuer)
+		if err != nil {
+			return m, err
+		}
+		m = discovered
+	}
+
+	m.AuthorizationEndpoint = fallback(p.config.AuthURL, m.AuthorizationEndpoint)
+	m.TokenEndpoint = fallback(p.config.TokenURL, m.TokenEndpoint)
+	m.UserInfoEndpoint = fallback(p.config.UserInfoURL, m.UserInfoEndpoint)
+	p.metadata = &m
+
+	return m, nil
+}
@@ -0,0 +1,43 @@
+package oidc
+
+import (
+	"fmt"
+)
+
+// ErrUnknownProvider is returned when provider is not registered
+var ErrUnknownProvider = fmt.Errorf("unknown identity provider")
+
+// Registry holds providers by name
+type Registry struct {
+	providers map[string]*Provider
+	ordered   []*Provider
+}
+
+// NewRegistry returns registry of providers, names have to be unique
+func NewRegistry(providers ...*Provider) (*Registry, error) {
+	r := &Registry{providers: make(map[string]*Provider, len(providers))}
+	for _, p := range providers {
+		if _, ok := r.providers[p.Name()]; ok {
+			return nil, fmt.Errorf("%w: provider %s is configured more than once", ErrInvalidConfig, p.Name())
+		}
+		r.providers[p.Name()] = p
+		r.ordered = append(r.ordered, p)
+	}
+
+	return r, nil
+}
+
+// Get returns provider by name
+func (r *Registry) Get(name string) (*Provider, error) {
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+	}
+
+	return p, nil
+}
+
+// Providers returns registered providers in order they were registered in
+func (r *Registry) Providers() []*Provider {
+	return r.ordered
+}
@@ -0,0 +1,4 @@
+/*
+Package password provides argon2id password hashing and password policy
+*/
+package password
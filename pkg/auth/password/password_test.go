@@ -0,0 +1,73 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+var testParams = Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestHashCompare(t *testing.T) {
+	hash, err := Hash("correct horse", testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("Hash() = %s", hash)
+	}
+
+	if err := Compare(hash, "correct horse"); err != nil {
+		t.Errorf("Compare() error = %v", err)
+	}
+	if err := Compare(hash, "battery staple"); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Compare() error = %v, want %v", err, ErrMismatch)
+	}
+	if err := Compare("$2a$10$bcrypt", "correct horse"); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("Compare() error = %v, want %v", err, ErrInvalidHash)
+	}
+
+	other, err := Hash("correct horse", testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == other {
+		t.Error("hashes of the same password should use different salt")
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	p := Policy{MinLength: 8, MaxLength: 16, RequireUpper: true, RequireDigit: true}
+
+	tests := []struct {
+		password string
+		fields   int
+	}{
+		{"Secret123", 0},
+		{"secret123", 1},
+		{"Sec1", 1},
+		{"secret", 3},
+		{"Secret123Secret123", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			err := p.Validate(tt.password)
+			if tt.fields == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var validationErr *apperrors.ValidationError
+			if !errors.As(err, &validationErr) || !errors.Is(err, apperrors.ErrInvalid) {
+				t.Fatalf("Validate() error = %v, want validation error", err)
+			}
+			if len(validationErr.Fields) != tt.fields {
+				t.Errorf("Validate() fields = %v, want %d", validationErr.Fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package password
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+// Policy describes requirements password has to meet
+type Policy struct {
+	MinLength     int
+	MaxLength     int // zero value means no limit
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// Validate returns apperrors.ErrInvalid validation error listing unmet requirements
+func (p Policy) Validate(password string) error {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	validationErr := apperrors.NewValidationError()
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		validationErr.Add("password", fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		validationErr.Add("password", fmt.Sprintf("must be at most %d characters long", p.MaxLength))
+	}
+	if p.RequireUpper && !upper {
+		validationErr.Add("password", "must contain upper case letter")
+	}
+	if p.RequireLower && !lower {
+		validationErr.Add("password", "must contain lower case letter")
+	}
+	if p.RequireDigit && !digit {
+		validationErr.Add("password", "must contain digit")
+	}
+	if p.RequireSymbol && !symbol {
+		validationErr.Add("password", "must contain symbol")
+	}
+
+	if !validationErr.IsEmpty() {
+		return apperrors.Wrap(validationErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,4 @@
+/*
+Package totp provides RFC 6238 time-based one-time passwords and recovery codes
+*/
+package totp
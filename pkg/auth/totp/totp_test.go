@@ -0,0 +1,90 @@
+package totp
+
+import (
+	"encoding/base32"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 appendix B test vectors for SHA1, truncated to 6 digits
+func TestCode(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		got, err := Code(secret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+
+	if _, err := Code("not base32!", 1); !errors.Is(err, ErrInvalidSecret) {
+		t.Errorf("Code() error = %v, want %v", err, ErrInvalidSecret)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	previous, err := Code(secret, Step(now)-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	step, err := Validate(secret, previous, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if step != Step(now)-1 {
+		t.Errorf("Validate() step = %d, want %d", step, Step(now)-1)
+	}
+
+	if _, err := Validate(secret, previous, now.Add(3*Period)); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrInvalidCode)
+	}
+	if _, err := Validate(secret, "12345", now); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrInvalidCode)
+	}
+}
+
+func TestURI(t *testing.T) {
+	got := URI("go-api-boilerplate", "test@test.com", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(got, "otpauth://totp/go-api-boilerplate:test@test.com?") || !strings.Contains(got, "secret=JBSWY3DPEHPK3PXP") {
+		t.Errorf("URI() = %s", got)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 10 || len(codes[0]) != 11 || codes[0][5] != '-' {
+		t.Fatalf("GenerateRecoveryCodes() = %v", codes)
+	}
+
+	if HashRecoveryCode(codes[0]) != HashRecoveryCode(" "+strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))) {
+		t.Error("HashRecoveryCode() does not normalize code")
+	}
+	if HashRecoveryCode(codes[0]) == HashRecoveryCode(codes[1]) {
+		t.Error("HashRecoveryCode() collision")
+	}
+}
@@ -17,3 +17,16 @@ type CommandBus interface {
 	Subscribe(ctx context.Context, commandName string, fn CommandHandler) error
 	Unsubscribe(ctx context.Context, commandName string) error
 }
+
+// MiddlewareFunc wraps command handler with additional behaviour
+type MiddlewareFunc func(next CommandHandler) CommandHandler
+
+// Chain wraps handler with middlewares,
+// first middleware is the outermost one and is called first
+func Chain(fn CommandHandler, middlewares ...MiddlewareFunc) CommandHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		fn = middlewares[i](fn)
+	}
+
+	return fn
+}
@@ -0,0 +1,34 @@
+package commandbus
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	middleware := func(name string) MiddlewareFunc {
+		return func(next CommandHandler) CommandHandler {
+			return func(ctx context.Context, command domain.Command) error {
+				calls = append(calls, name)
+				return next(ctx, command)
+			}
+		}
+	}
+
+	fn := Chain(func(ctx context.Context, command domain.Command) error {
+		calls = append(calls, "handler")
+		return nil
+	}, middleware("first"), middleware("second"))
+
+	if err := fn(context.Background(), commandMock{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"first", "second", "handler"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Chain() calls = %v, want %v", calls, want)
+	}
+}
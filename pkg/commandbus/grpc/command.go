@@ -0,0 +1,26 @@
+package grpc
+
+import (
+	"encoding/json"
+)
+
+// RemoteCommand is a command owned by other service whose type is not known locally,
+// payload is sent as is and decoded by the owning service
+type RemoteCommand struct {
+	Name    string
+	Payload json.RawMessage
+}
+
+// GetName returns command name
+func (c RemoteCommand) GetName() string {
+	return c.Name
+}
+
+// MarshalJSON returns command payload
+func (c RemoteCommand) MarshalJSON() ([]byte, error) {
+	if len(c.Payload) == 0 {
+		return []byte("{}"), nil
+	}
+
+	return c.Payload, nil
+}
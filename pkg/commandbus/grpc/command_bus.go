@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/grpc/proto"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	grpcerrors "github.com/vardius/go-api-boilerplate/pkg/grpc/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/logger"
+)
+
+// New creates command bus publishing commands to services owning them,
+// routes map command name to connection of the owning service,
+// commands without route are published to local command bus which also handles subscriptions.
+// Connections should append identity and metadata to outgoing context, see grpc.NewConnection
+func New(local commandbus.CommandBus, routes map[string]grpc.ClientConnInterface) commandbus.CommandBus {
+	clients := make(map[string]proto.CommandBusClient, len(routes))
+	for commandName, conn := range routes {
+		clients[commandName] = proto.NewCommandBusClient(conn)
+	}
+
+	return &commandBus{
+		local:   local,
+		clients: clients,
+	}
+}
+
+type commandBus struct {
+	local   commandbus.CommandBus
+	clients map[string]proto.CommandBusClient
+}
+
+// Publish dispatches command to the owning service and waits until it is handled,
+// context deadline is propagated to remote handler
+func (bus *commandBus) Publish(ctx context.Context, command domain.Command) error {
+	client, ok := bus.clients[command.GetName()]
+	if !ok {
+		return bus.local.Publish(ctx, command)
+	}
+
+	payload, err := json.Marshal(command)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	logger.Debug(ctx, fmt.Sprintf("[CommandBus] Publish remote: %s %s", command.GetName(), string(payload)))
+
+	if _, err := client.Dispatch(ctx, &proto.DispatchCommandRequest{
+		Name:    command.GetName(),
+		Payload: payload,
+	}); err != nil {
+		// connections created with grpc.NewConnection map status errors already
+		if _, ok := status.FromError(err); ok {
+			err = grpcerrors.FromGRPCError(err)
+		}
+
+		return apperrors.Wrap(fmt.Errorf("%s failed: %w", command.GetName(), err))
+	}
+
+	return nil
+}
+
+func (bus *commandBus) Subscribe(ctx context.Context, commandName string, fn commandbus.CommandHandler) error {
+	return bus.local.Subscribe(ctx, commandName, fn)
+}
+
+func (bus *commandBus) Unsubscribe(ctx context.Context, commandName string) error {
+	return bus.local.Unsubscribe(ctx, commandName)
+}
@@ -0,0 +1,184 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/grpc/proto"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/memory"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	grpcutils "github.com/vardius/go-api-boilerplate/pkg/grpc"
+	"github.com/vardius/go-api-boilerplate/pkg/grpc/middleware"
+	"github.com/vardius/go-api-boilerplate/pkg/grpc/middleware/firewall"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/metadata"
+)
+
+type commandMock struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (c commandMock) GetName() string {
+	return "grpc.commandMock"
+}
+
+type localCommandMock struct{}
+
+func (c localCommandMock) GetName() string {
+	return "grpc.localCommandMock"
+}
+
+type hiddenCommandMock struct{}
+
+func (c hiddenCommandMock) GetName() string {
+	return "grpc.hiddenCommandMock"
+}
+
+func TestCommandBus(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	remote := memory.New(runtime.NumCPU())
+	commands := registry.New(remote)
+
+	var handler commandbus.CommandHandler
+	if err := commands.Register(ctx, registry.Definition{
+		Contract:   "grpc-command-mock",
+		Command:    commandMock{},
+		Permission: identity.PermissionUserWrite,
+		Handler: func(ctx context.Context, command domain.Command) error {
+			return handler(ctx, command)
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := commands.Register(ctx, registry.Definition{
+		Contract: "grpc-hidden-command-mock",
+		Command:  hiddenCommandMock{},
+		Handler: func(ctx context.Context, command domain.Command) error {
+			return nil
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpcutils.NewServer(grpcutils.ServerConfig{}, nil, nil)
+	proto.RegisterCommandBusServer(server, NewServer(remote, commands, "grpc-command-mock"))
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(
+			middleware.AppendMetadataToOutgoingUnaryContext(),
+			firewall.AppendIdentityToOutgoingUnaryContext(),
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	local := memory.New(runtime.NumCPU())
+	bus := New(local, map[string]grpc.ClientConnInterface{
+		commandMock{}.GetName():       conn,
+		hiddenCommandMock{}.GetName(): conn,
+	})
+
+	i := &identity.Identity{UserID: uuid.New(), Permissions: identity.NewPermissions(identity.PermissionUserWrite)}
+
+	t.Run("propagates identity, metadata and deadline", func(t *testing.T) {
+		m := metadata.New()
+
+		var (
+			handled  commandMock
+			got      *identity.Identity
+			gotMeta  *metadata.Metadata
+			deadline bool
+		)
+		handler = func(ctx context.Context, command domain.Command) error {
+			handled = command.(commandMock)
+			got, _ = identity.FromContext(ctx)
+			gotMeta, _ = metadata.FromContext(ctx)
+			_, deadline = ctx.Deadline()
+			return nil
+		}
+
+		publishCtx := metadata.ContextWithMetadata(identity.ContextWithIdentity(ctx, i), m)
+		if err := bus.Publish(publishCtx, commandMock{Email: "test@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if handled.Email != "test@example.com" {
+			t.Errorf("handled = %+v", handled)
+		}
+		if got == nil || got.UserID != i.UserID || got.Permissions.String() != i.Permissions.String() {
+			t.Errorf("identity = %+v, want %+v", got, i)
+		}
+		if gotMeta == nil || gotMeta.TraceID != m.TraceID {
+			t.Errorf("metadata = %+v, want %+v", gotMeta, m)
+		}
+		if !deadline {
+			t.Error("expected deadline to be propagated")
+		}
+	})
+
+	t.Run("maps handler errors", func(t *testing.T) {
+		handler = func(ctx context.Context, command domain.Command) error {
+			return apperrors.Wrap(fmt.Errorf("%w: denied", apperrors.ErrForbidden))
+		}
+
+		if err := bus.Publish(identity.ContextWithIdentity(ctx, i), commandMock{Email: "test@example.com"}); !errors.Is(err, apperrors.ErrForbidden) {
+			t.Errorf("Publish() error = %v, want %v", err, apperrors.ErrForbidden)
+		}
+	})
+
+	t.Run("validates command", func(t *testing.T) {
+		if err := bus.Publish(identity.ContextWithIdentity(ctx, i), RemoteCommand{Name: commandMock{}.GetName(), Payload: []byte(`{"email":"invalid"}`)}); !errors.Is(err, apperrors.ErrInvalid) {
+			t.Errorf("Publish() error = %v, want %v", err, apperrors.ErrInvalid)
+		}
+	})
+
+	t.Run("authorizes command", func(t *testing.T) {
+		if err := bus.Publish(ctx, commandMock{Email: "test@example.com"}); !errors.Is(err, apperrors.ErrUnauthorized) {
+			t.Errorf("Publish() error = %v, want %v", err, apperrors.ErrUnauthorized)
+		}
+	})
+
+	t.Run("rejects commands not exposed", func(t *testing.T) {
+		if err := bus.Publish(ctx, hiddenCommandMock{}); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Errorf("Publish() error = %v, want %v", err, apperrors.ErrNotFound)
+		}
+	})
+
+	t.Run("publishes unrouted commands locally", func(t *testing.T) {
+		var handled bool
+		if err := bus.Subscribe(ctx, localCommandMock{}.GetName(), func(ctx context.Context, command domain.Command) error {
+			handled = true
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := bus.Publish(ctx, localCommandMock{}); err != nil {
+			t.Fatal(err)
+		}
+		if !handled {
+			t.Error("expected local handler to be called")
+		}
+	})
+}
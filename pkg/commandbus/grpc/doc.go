@@ -0,0 +1,4 @@
+/*
+Package grpc provides command bus dispatching commands to services owning them over gRPC
+*/
+package grpc
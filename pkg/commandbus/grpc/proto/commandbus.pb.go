@@ -0,0 +1,179 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: commandbus.proto
+
+package proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// DispatchCommandRequest carries command name and json encoded command
+type DispatchCommandRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Payload              []byte   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DispatchCommandRequest) Reset()         { *m = DispatchCommandRequest{} }
+func (m *DispatchCommandRequest) String() string { return proto.CompactTextString(m) }
+func (*DispatchCommandRequest) ProtoMessage()    {}
+func (*DispatchCommandRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a1555c3172b11908, []int{0}
+}
+
+func (m *DispatchCommandRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DispatchCommandRequest.Unmarshal(m, b)
+}
+func (m *DispatchCommandRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DispatchCommandRequest.Marshal(b, m, deterministic)
+}
+func (m *DispatchCommandRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DispatchCommandRequest.Merge(m, src)
+}
+func (m *DispatchCommandRequest) XXX_Size() int {
+	return xxx_messageInfo_DispatchCommandRequest.Size(m)
+}
+func (m *DispatchCommandRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DispatchCommandRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DispatchCommandRequest proto.InternalMessageInfo
+
+func (m *DispatchCommandRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DispatchCommandRequest) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*DispatchCommandRequest)(nil), "commandbus.DispatchCommandRequest")
+}
+
+func init() {
+	proto.RegisterFile("commandbus.proto", fileDescriptor_a1555c3172b11908)
+}
+
+var fileDescriptor_a1555c3172b11908 = []byte{
+	// 202 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x8e, 0xbd, 0x4f, 0x85, 0x30,
+	0x14, 0xc5, 0x53, 0xe3, 0xe7, 0x8d, 0x89, 0xa6, 0x03, 0x21, 0xb8, 0x10, 0x26, 0xa6, 0x92, 0xe8,
+	0xea, 0x84, 0xca, 0x4c, 0x88, 0x93, 0x5b, 0x81, 0x5a, 0x49, 0x5a, 0x6e, 0xa5, 0xed, 0xc0, 0x7f,
+	0xff, 0x02, 0x7d, 0x04, 0x86, 0x37, 0xdd, 0xcf, 0x73, 0xce, 0x0f, 0x9e, 0x3b, 0xd4, 0x9a, 0x8f,
+	0x7d, 0xeb, 0x2d, 0x33, 0x13, 0x3a, 0xa4, 0xb0, 0x6f, 0x92, 0x17, 0x89, 0x28, 0x95, 0x28, 0xd6,
+	0x4b, 0xeb, 0x7f, 0x0b, 0xa1, 0x8d, 0x9b, 0xc3, 0x63, 0x56, 0x41, 0xf4, 0x39, 0x58, 0xc3, 0x5d,
+	0xf7, 0xf7, 0x11, 0x24, 0x8d, 0xf8, 0xf7, 0xc2, 0x3a, 0x4a, 0xe1, 0x7a, 0xe4, 0x5a, 0xc4, 0x24,
+	0x25, 0xf9, 0x43, 0xb3, 0xf6, 0x34, 0x86, 0x3b, 0xc3, 0x67, 0x85, 0xbc, 0x8f, 0xaf, 0x52, 0x92,
+	0x3f, 0x36, 0xdb, 0xf8, 0xfa, 0x0d, 0x70, 0xd6, 0x97, 0xde, 0xd2, 0x0a, 0xee, 0x37, 0x57, 0x9a,
+	0xb1, 0x03, 0xdd, 0xe5, 0xac, 0x24, 0x62, 0x81, 0x91, 0x6d, 0x8c, 0xec, 0x6b, 0x61, 0x2c, 0xdf,
+	0x21, 0x95, 0xc8, 0xcd, 0xd0, 0xe2, 0xa0, 0xc4, 0x64, 0x14, 0x77, 0x82, 0xc9, 0xc9, 0x74, 0x07,
+	0xcb, 0xf2, 0x69, 0xcf, 0xad, 0x17, 0x75, 0x4d, 0x7e, 0x6e, 0x82, 0xcd, 0xed, 0x5a, 0xde, 0x4e,
+	0x03, 0x00, 0x86, 0x88, 0x7d, 0x45, 0x1f, 0x01, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// CommandBusClient is the client API for CommandBus service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CommandBusClient interface {
+	Dispatch(ctx context.Context, in *DispatchCommandRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type commandBusClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCommandBusClient(cc grpc.ClientConnInterface) CommandBusClient {
+	return &commandBusClient{cc}
+}
+
+func (c *commandBusClient) Dispatch(ctx context.Context, in *DispatchCommandRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/commandbus.CommandBus/Dispatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CommandBusServer is the server API for CommandBus service.
+type CommandBusServer interface {
+	Dispatch(context.Context, *DispatchCommandRequest) (*emptypb.Empty, error)
+}
+
+// UnimplementedCommandBusServer can be embedded to have forward compatible implementations.
+type UnimplementedCommandBusServer struct {
+}
+
+func (*UnimplementedCommandBusServer) Dispatch(ctx context.Context, req *DispatchCommandRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Dispatch not implemented")
+}
+
+func RegisterCommandBusServer(s *grpc.Server, srv CommandBusServer) {
+	s.RegisterService(&_CommandBus_serviceDesc, srv)
+}
+
+func _CommandBus_Dispatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DispatchCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandBusServer).Dispatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/commandbus.CommandBus/Dispatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandBusServer).Dispatch(ctx, req.(*DispatchCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _CommandBus_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "commandbus.CommandBus",
+	HandlerType: (*CommandBusServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Dispatch",
+			Handler:    _CommandBus_Dispatch_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "commandbus.proto",
+}
@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/grpc/proto"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/registry"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+type server struct {
+	proto.UnimplementedCommandBusServer
+
+	commandBus commandbus.CommandBus
+	commands   *registry.Registry
+	contracts  []string
+}
+
+// NewServer returns gRPC server dispatching received commands to command bus,
+// only commands of given contracts are exposed, they are authorized and decoded by registry
+// same as commands dispatched by http endpoints.
+// Server should be registered on server created with grpc.NewServer so identity,
+// metadata and errors are transformed by its interceptors
+func NewServer(commandBus commandbus.CommandBus, commands *registry.Registry, contracts ...string) proto.CommandBusServer {
+	return &server{
+		commandBus: commandBus,
+		commands:   commands,
+		contracts:  contracts,
+	}
+}
+
+// Dispatch decodes and publishes command, responds once command is handled
+func (s *server) Dispatch(ctx context.Context, r *proto.DispatchCommandRequest) (*emptypb.Empty, error) {
+	contractName, ok := s.contract(r.GetName())
+	if !ok {
+		return nil, apperrors.Wrap(fmt.Errorf("%w: command %s is not exposed", apperrors.ErrNotFound, r.GetName()))
+	}
+
+	command, err := s.commands.NewCommand(ctx, contractName, r.GetPayload())
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+
+	if err := s.commandBus.Publish(ctx, command); err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// contract returns exposed contract of command routed by name
+func (s *server) contract(name string) (string, bool) {
+	for _, contractName := range s.contracts {
+		if d, ok := s.commands.Definition(contractName); ok && d.Command.GetName() == name {
+			return contractName, true
+		}
+	}
+
+	return "", false
+}
@@ -11,18 +11,23 @@ import (
 	messagebus "github.com/vardius/message-bus"
 )
 
-// New creates in memory command bus
-func New(maxConcurrentCalls int) commandbus.CommandBus {
-	return &commandBus{messagebus.New(maxConcurrentCalls)}
+// New creates in memory command bus,
+// middlewares are applied to every subscribed handler
+func New(maxConcurrentCalls int, middlewares ...commandbus.MiddlewareFunc) commandbus.CommandBus {
+	return &commandBus{
+		messageBus:  messagebus.New(maxConcurrentCalls),
+		middlewares: middlewares,
+	}
 }
 
 type commandBus struct {
-	messageBus messagebus.MessageBus
+	messageBus  messagebus.MessageBus
+	middlewares []commandbus.MiddlewareFunc
 }
 
 func (bus *commandBus) Publish(ctx context.Context, command domain.Command) error {
+	// out is not closed, handler might still send to it after publisher context is done
 	out := make(chan error, 1)
-	defer close(out)
 
 	logger.Debug(ctx, fmt.Sprintf("[CommandBus] Publish: %s %+v", command.GetName(), command))
 	bus.messageBus.Publish(command.GetName(), ctx, command, out)
@@ -33,7 +38,7 @@ func (bus *commandBus) Publish(ctx context.Context, command domain.Command) erro
 		return apperrors.Wrap(fmt.Errorf("%w: %s", apperrors.ErrTimeout, ctx.Err()))
 	case err := <-out:
 		if err != nil {
-			return apperrors.Wrap(fmt.Errorf("create client failed: %w", err))
+			return apperrors.Wrap(fmt.Errorf("%s failed: %w", command.GetName(), err))
 		}
 		return nil
 	}
@@ -45,6 +50,8 @@ func (bus *commandBus) Subscribe(ctx context.Context, commandName string, fn com
 	// unsubscribe all other handlers
 	bus.messageBus.Close(commandName)
 
+	fn = commandbus.Chain(fn, bus.middlewares...)
+
 	return bus.messageBus.Subscribe(commandName, func(ctx context.Context, command domain.Command, out chan<- error) {
 		out <- fn(ctx, command)
 	})
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
 	"github.com/vardius/go-api-boilerplate/pkg/domain"
 )
 
@@ -61,3 +62,25 @@ func TestUnsubscribe(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestMiddlewares(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	errMiddleware := errors.New("middleware")
+	bus := New(runtime.NumCPU(), func(next commandbus.CommandHandler) commandbus.CommandHandler {
+		return func(ctx context.Context, command domain.Command) error {
+			return errMiddleware
+		}
+	})
+
+	bus.Subscribe(ctx, "command", func(ctx context.Context, _ domain.Command) error {
+		t.Fail()
+
+		return nil
+	})
+
+	if err := bus.Publish(ctx, &commandMock{}); !errors.Is(err, errMiddleware) {
+		t.Errorf("Publish() error = %v, want %v", err, errMiddleware)
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+// GrantAccessFor returns Unauthorized error if
+// Identity is not set within context
+// or Forbidden error if user does not have required permission
+func GrantAccessFor(permission identity.Permission) commandbus.MiddlewareFunc {
+	return func(next commandbus.CommandHandler) commandbus.CommandHandler {
+		return func(ctx context.Context, command domain.Command) error {
+			i, ok := identity.FromContext(ctx)
+			if !ok {
+				return apperrors.Wrap(fmt.Errorf("%w: command %s is missing identity", apperrors.ErrUnauthorized, command.GetName()))
+			}
+			if !i.Permissions.Has(permission) {
+				return apperrors.Wrap(fmt.Errorf("%w: (%s) missing permission %s", apperrors.ErrForbidden, i.Permissions, permission))
+			}
+
+			return next(ctx, command)
+		}
+	}
+}
+
+// GrantAccessForRole returns Unauthorized error if
+// Identity is not set within context
+// or Forbidden error if user does not have any of required roles
+func GrantAccessForRole(role identity.Role) commandbus.MiddlewareFunc {
+	return func(next commandbus.CommandHandler) commandbus.CommandHandler {
+		return func(ctx context.Context, command domain.Command) error {
+			i, ok := identity.FromContext(ctx)
+			if !ok {
+				return apperrors.Wrap(fmt.Errorf("%w: command %s is missing identity", apperrors.ErrUnauthorized, command.GetName()))
+			}
+			if !i.Role.Has(role) {
+				return apperrors.Wrap(fmt.Errorf("%w: (%s) missing role %s", apperrors.ErrForbidden, i.Role, role))
+			}
+
+			return next(ctx, command)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+/*
+Package middleware provides command bus middleware
+*/
+package middleware
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/logger"
+)
+
+// Logger logs handled commands along with handling duration
+func Logger() commandbus.MiddlewareFunc {
+	return func(next commandbus.CommandHandler) commandbus.CommandHandler {
+		return func(ctx context.Context, command domain.Command) error {
+			now := time.Now()
+
+			logger.Info(ctx, fmt.Sprintf("[CommandBus] Start: %s", command.GetName()))
+
+			err := next(ctx, command)
+
+			switch {
+			case err == nil:
+				logger.Info(ctx, fmt.Sprintf("[CommandBus] End: %s (%s)", command.GetName(), time.Since(now)))
+			case errors.Is(err, apperrors.ErrInternal) || !isAppError(err):
+				logger.Error(ctx, fmt.Sprintf("[CommandBus] End: %s (%s): %s", command.GetName(), time.Since(now), err))
+			default:
+				logger.Debug(ctx, fmt.Sprintf("[CommandBus] End: %s (%s): %s", command.GetName(), time.Since(now), err))
+			}
+
+			return err
+		}
+	}
+}
+
+// isAppError reports if err is one of expected application errors
+func isAppError(err error) bool {
+	for _, target := range []error{
+		apperrors.ErrInvalid,
+		apperrors.ErrUnauthorized,
+		apperrors.ErrForbidden,
+		apperrors.ErrNotFound,
+		apperrors.ErrTimeout,
+		apperrors.ErrTemporaryDisabled,
+		apperrors.ErrConflict,
+		apperrors.ErrUnprocessable,
+		apperrors.ErrTooManyRequests,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
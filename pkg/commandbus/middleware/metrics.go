@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+)
+
+// expvar names can be published only once per process, maps are shared by every bus using Metrics
+var (
+	commands  = expvar.NewMap("command_bus_commands")
+	failures  = expvar.NewMap("command_bus_errors")
+	durations = expvar.NewMap("command_bus_duration_ns")
+)
+
+// Metrics updates command counters and handling duration per command name
+func Metrics() commandbus.MiddlewareFunc {
+	return func(next commandbus.CommandHandler) commandbus.CommandHandler {
+		return func(ctx context.Context, command domain.Command) error {
+			now := time.Now()
+
+			err := next(ctx, command)
+
+			commands.Add(command.GetName(), 1)
+			durations.Add(command.GetName(), int64(time.Since(now)))
+			if err != nil {
+				failures.Add(command.GetName(), 1)
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+type commandMock struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func (c commandMock) GetName() string {
+	return "command"
+}
+
+func handler(ctx context.Context, _ domain.Command) error {
+	return nil
+}
+
+func TestGrantAccessFor(t *testing.T) {
+	fn := GrantAccessFor(identity.PermissionUserWrite)(handler)
+
+	if err := fn(context.Background(), commandMock{}); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("GrantAccessFor() error = %v, want %v", err, apperrors.ErrUnauthorized)
+	}
+
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{Permissions: identity.NewPermissions(identity.PermissionUserRead)})
+	if err := fn(ctx, commandMock{}); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("GrantAccessFor() error = %v, want %v", err, apperrors.ErrForbidden)
+	}
+
+	ctx = identity.ContextWithIdentity(context.Background(), &identity.Identity{Permissions: identity.NewPermissions(identity.PermissionUserWrite)})
+	if err := fn(ctx, commandMock{}); err != nil {
+		t.Errorf("GrantAccessFor() error = %v", err)
+	}
+}
+
+func TestGrantAccessForRole(t *testing.T) {
+	fn := GrantAccessForRole(identity.RoleAdmin | identity.RoleSuperAdmin)(handler)
+
+	if err := fn(context.Background(), commandMock{}); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("GrantAccessForRole() error = %v, want %v", err, apperrors.ErrUnauthorized)
+	}
+
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{Role: identity.RoleUser})
+	if err := fn(ctx, commandMock{}); !errors.Is(err, apperrors.ErrForbidden) {
+		t.Errorf("GrantAccessForRole() error = %v, want %v", err, apperrors.ErrForbidden)
+	}
+
+	ctx = identity.ContextWithIdentity(context.Background(), &identity.Identity{Role: identity.RoleUser.Add(identity.RoleSuperAdmin)})
+	if err := fn(ctx, commandMock{}); err != nil {
+		t.Errorf("GrantAccessForRole() error = %v", err)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	fn := Recover()(func(ctx context.Context, _ domain.Command) error {
+		panic("test")
+	})
+
+	if err := fn(context.Background(), commandMock{}); !errors.Is(err, apperrors.ErrInternal) {
+		t.Errorf("Recover() error = %v, want %v", err, apperrors.ErrInternal)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	fn := Timeout(time.Millisecond)(func(ctx context.Context, _ domain.Command) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if err := fn(context.Background(), commandMock{}); !errors.Is(err, apperrors.ErrTimeout) {
+		t.Errorf("Timeout() error = %v, want %v", err, apperrors.ErrTimeout)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	fn := commandbus.Chain(handler, Logger(), Validate())
+
+	if err := fn(context.Background(), commandMock{}); !errors.Is(err, apperrors.ErrInvalid) {
+		t.Errorf("Validate() error = %v, want %v", err, apperrors.ErrInvalid)
+	}
+	if err := fn(context.Background(), commandMock{Name: "test"}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	fn := Metrics()(handler)
+
+	if err := fn(context.Background(), commandMock{}); err != nil {
+		t.Errorf("Metrics() error = %v", err)
+	}
+
+	// second bus in the same process shares published counters
+	fn = Metrics()(handler)
+
+	if err := fn(context.Background(), commandMock{}); err != nil {
+		t.Errorf("Metrics() error = %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/logger"
+)
+
+// Recover recovers from handler panic returning internal error instead
+func Recover() commandbus.MiddlewareFunc {
+	return func(next commandbus.CommandHandler) commandbus.CommandHandler {
+		return func(ctx context.Context, command domain.Command) (err error) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					logger.Critical(ctx, fmt.Sprintf("[CommandBus] Recovered in %s %v %s", command.GetName(), rec, debug.Stack()))
+
+					err = apperrors.Wrap(fmt.Errorf("%w: recovered from panic", apperrors.ErrInternal))
+				}
+			}()
+
+			return next(ctx, command)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+// Timeout limits time handler can take by cancelling its context,
+// handler has to respect context cancellation
+func Timeout(timeout time.Duration) commandbus.MiddlewareFunc {
+	return func(next commandbus.CommandHandler) commandbus.CommandHandler {
+		return func(ctx context.Context, command domain.Command) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			err := next(ctx, command)
+			if err != nil && !errors.Is(err, apperrors.ErrTimeout) && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return apperrors.Wrap(fmt.Errorf("%w: %s exceeded %s: %s", apperrors.ErrTimeout, command.GetName(), timeout, err))
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+// Validate validates command against its declared rules before handling it
+func Validate() commandbus.MiddlewareFunc {
+	return func(next commandbus.CommandHandler) commandbus.CommandHandler {
+		return func(ctx context.Context, command domain.Command) error {
+			if err := commandbus.Validate(command); err != nil {
+				return apperrors.Wrap(err)
+			}
+
+			return next(ctx, command)
+		}
+	}
+}
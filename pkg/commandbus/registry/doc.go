@@ -0,0 +1,5 @@
+/*
+Package registry provides typed command registry, command contract, decoder, validator,
+required permission and handler are registered once and used by command bus and dispatch endpoints
+*/
+package registry
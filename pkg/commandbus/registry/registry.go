@@ -0,0 +1,175 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/middleware"
+	"github.com/vardius/go-api-boilerplate/pkg/contract"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+// Definition describes command accepted by bounded context
+type Definition struct {
+	// Contract is a public command name used by dispatch endpoints, e.g. user-register-with-email
+	Contract string
+	// Command is a zero value of command type, payload is decoded into its copy
+	Command domain.Command
+	// Decode overrides json decoding of payload into Command type
+	Decode func(payload []byte) (domain.Command, error)
+	// Validate is called for decoded command after contract schema and validate struct tags
+	Validate func(command domain.Command) error
+	// Permission required to dispatch command, zero value allows anonymous dispatch
+	Permission identity.Permission
+	// Role required to dispatch command, any of given roles is sufficient, zero value does not require any role
+	Role identity.Role
+	// Handler handles command
+	Handler commandbus.CommandHandler
+}
+
+// Registry holds command definitions of bounded context,
+// dispatch endpoints, contract schemas and authorization rules are derived from it
+type Registry struct {
+	mtx         sync.RWMutex
+	commandBus  commandbus.CommandBus
+	definitions map[string]Definition
+}
+
+// New creates registry subscribing registered command handlers to command bus
+func New(commandBus commandbus.CommandBus) *Registry {
+	return &Registry{
+		commandBus:  commandBus,
+		definitions: make(map[string]Definition),
+	}
+}
+
+// Register subscribes command handler guarded by required permission,
+// registers contract schema and command factory so command can be decoded by scheduler
+func (r *Registry) Register(ctx context.Context, d Definition) error {
+	if d.Contract == "" || d.Command == nil || d.Handler == nil {
+		return apperrors.New("invalid command definition")
+	}
+	if d.Decode == nil {
+		d.Decode = jsonDecoder(d.Command)
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if _, ok := r.definitions[d.Contract]; ok {
+		return apperrors.New(fmt.Sprintf("command %s was already registered", d.Contract))
+	}
+
+	if err := contract.RegisterCommand(d.Contract, d.Command); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := domain.RegisterCommandFactory(d.Command.GetName(), d.Decode); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	handler := d.Handler
+	if d.Permission != "" {
+		handler = commandbus.Chain(handler, middleware.GrantAccessFor(d.Permission))
+	}
+	if d.Role != 0 {
+		handler = commandbus.Chain(handler, middleware.GrantAccessForRole(d.Role))
+	}
+	if err := r.commandBus.Subscribe(ctx, d.Command.GetName(), handler); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	r.definitions[d.Contract] = d
+
+	return nil
+}
+
+// NewCommand authorizes identity carried by ctx, validates payload and decodes command of given contract
+func (r *Registry) NewCommand(ctx context.Context, contractName string, payload []byte) (domain.Command, error) {
+	d, ok := r.Definition(contractName)
+	if !ok {
+		return nil, apperrors.Wrap(fmt.Errorf("%w: command %s", apperrors.ErrNotFound, contractName))
+	}
+
+	if d.Permission != "" {
+		authorize := middleware.GrantAccessFor(d.Permission)(func(context.Context, domain.Command) error { return nil })
+		if err := authorize(ctx, d.Command); err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+	}
+	if d.Role != 0 {
+		authorize := middleware.GrantAccessForRole(d.Role)(func(context.Context, domain.Command) error { return nil })
+		if err := authorize(ctx, d.Command); err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+	}
+
+	if err := contract.ValidateCommand(d.Contract, payload); err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+
+	command, err := d.Decode(payload)
+	if err != nil {
+		return nil, apperrors.Wrap(fmt.Errorf("%w: %s: %s", apperrors.ErrInvalid, d.Contract, err))
+	}
+
+	if err := commandbus.Validate(command); err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	if d.Validate != nil {
+		if err := d.Validate(command); err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+	}
+
+	return command, nil
+}
+
+// Definition returns command definition by contract
+func (r *Registry) Definition(contractName string) (Definition, bool) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	d, ok := r.definitions[contractName]
+
+	return d, ok
+}
+
+// Definitions returns all command definitions sorted by contract
+func (r *Registry) Definitions() []Definition {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	definitions := make([]Definition, 0, len(r.definitions))
+	for _, d := range r.definitions {
+		definitions = append(definitions, d)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Contract < definitions[j].Contract
+	})
+
+	return definitions
+}
+
+// jsonDecoder decodes payload into new value of command type,
+// empty payload decodes to zero value
+func jsonDecoder(command domain.Command) func(payload []byte) (domain.Command, error) {
+	t := reflect.TypeOf(command)
+
+	return func(payload []byte) (domain.Command, error) {
+		v := reflect.New(t)
+		if len(payload) > 0 {
+			if err := json.Unmarshal(payload, v.Interface()); err != nil {
+				return nil, err
+			}
+		}
+
+		return v.Elem().Interface().(domain.Command), nil
+	}
+}
@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/memory"
+	"github.com/vardius/go-api-boilerplate/pkg/contract"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+type commandMock struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (c commandMock) GetName() string {
+	return "registry.commandMock"
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	bus := memory.New(runtime.NumCPU())
+	r := New(bus)
+
+	var handled commandMock
+	if err := r.Register(ctx, Definition{
+		Contract:   "registry-command-mock",
+		Command:    commandMock{},
+		Permission: identity.PermissionUserWrite,
+		Handler: func(ctx context.Context, command domain.Command) error {
+			handled = command.(commandMock)
+			return nil
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(ctx, Definition{Contract: "registry-command-mock", Command: commandMock{}, Handler: func(context.Context, domain.Command) error { return nil }}); err == nil {
+		t.Error("Register() expected error for duplicated contract")
+	}
+
+	if _, ok := contract.Commands()["registry-command-mock"]; !ok {
+		t.Error("expected contract schema to be registered")
+	}
+
+	payload := []byte(`{"email":"test@test.com"}`)
+	authorized := identity.ContextWithIdentity(ctx, &identity.Identity{Permissions: identity.NewPermissions(identity.PermissionUserWrite)})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		contract string
+		payload  []byte
+		wantErr  error
+	}{
+		{"unknown contract", authorized, "registry-unknown", payload, apperrors.ErrNotFound},
+		{"missing identity", ctx, "registry-command-mock", payload, apperrors.ErrUnauthorized},
+		{"missing permission", identity.ContextWithIdentity(ctx, &identity.Identity{Permissions: identity.NewPermissions(identity.PermissionUserRead)}), "registry-command-mock", payload, apperrors.ErrForbidden},
+		{"invalid schema", authorized, "registry-command-mock", []byte(`{}`), apperrors.ErrInvalid},
+		{"invalid field", authorized, "registry-command-mock", []byte(`{"email":"test"}`), apperrors.ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := r.NewCommand(tt.ctx, tt.contract, tt.payload); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewCommand() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	command, err := r.NewCommand(authorized, "registry-command-mock", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Publish(ctx, command); !errors.Is(err, apperrors.ErrUnauthorized) {
+		t.Errorf("Publish() error = %v, want handler to require identity", err)
+	}
+	if err := bus.Publish(authorized, command); err != nil {
+		t.Fatal(err)
+	}
+	if handled.Email != "test@test.com" {
+		t.Errorf("handled = %+v, want decoded command", handled)
+	}
+
+	decoded, err := domain.NewCommand(commandMock{}.GetName(), payload)
+	if err != nil || decoded.(commandMock).Email != "test@test.com" {
+		t.Errorf("domain.NewCommand() = %v, %v, want registered command factory", decoded, err)
+	}
+}
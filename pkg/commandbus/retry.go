@@ -0,0 +1,27 @@
+package commandbus
+
+import (
+	"errors"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+// IsRetryable reports if command dispatch failed for reasons other than command being rejected,
+// internal, timeout and unknown errors are retryable
+func IsRetryable(err error) bool {
+	for _, target := range []error{
+		apperrors.ErrInvalid,
+		apperrors.ErrUnauthorized,
+		apperrors.ErrForbidden,
+		apperrors.ErrNotFound,
+		apperrors.ErrConflict,
+		apperrors.ErrUnprocessable,
+		apperrors.ErrTooManyRequests,
+	} {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,18 @@
+package commandbus
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+)
+
+// Scheduler persists commands and dispatches them through command bus once they are due
+type Scheduler interface {
+	// Schedule persists command to be dispatched at dueAt, returned id allows to cancel it
+	Schedule(ctx context.Context, command domain.Command, dueAt time.Time) (uuid.UUID, error)
+	// Cancel removes scheduled command, returns apperrors.ErrNotFound if command does not exist or was already dispatched
+	Cancel(ctx context.Context, id uuid.UUID) error
+}
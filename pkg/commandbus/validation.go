@@ -0,0 +1,196 @@
+package commandbus
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/asaskevich/govalidator"
+	"golang.org/x/text/language"
+
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+// validateTagName is the struct tag commands use to declare validation rules
+//
+//	type Create struct {
+//		Domain string   `json:"domain" validate:"required,requrl"`
+//		Scopes []string `json:"scopes" validate:"required,in(all|user_read|user_write)"`
+//	}
+const validateTagName = "validate"
+
+var ruleRegexp = regexp.MustCompile(`^(\w+)(?:\((.*)\))?$`)
+
+// rule validates non zero string value, returns message if value is invalid
+type rule func(value string, params []string) string
+
+var rules = map[string]rule{
+	"email": func(value string, params []string) string {
+		if !govalidator.IsEmail(value) {
+			return "must be a valid email address"
+		}
+		return ""
+	},
+	"requrl": func(value string, params []string) string {
+		if !govalidator.IsRequestURL(value) {
+			return "must be a valid absolute URL"
+		}
+		return ""
+	},
+	"requri": func(value string, params []string) string {
+		if !govalidator.IsRequestURI(value) {
+			return "must be a valid request URI"
+		}
+		return ""
+	},
+	"in": func(value string, params []string) string {
+		if !govalidator.IsIn(value, params...) {
+			return fmt.Sprintf("must be one of: %s", strings.Join(params, ", "))
+		}
+		return ""
+	},
+	"length": func(value string, params []string) string {
+		if len(params) != 2 {
+			return "has invalid length rule"
+		}
+		min, _ := strconv.Atoi(params[0])
+		max, _ := strconv.Atoi(params[1])
+		if l := utf8.RuneCountInString(value); l < min || l > max {
+			return fmt.Sprintf("length must be between %d and %d", min, max)
+		}
+		return ""
+	},
+	"locale": func(value string, params []string) string {
+		if _, err := language.Parse(value); err != nil {
+			return "must be a valid BCP 47 language tag"
+		}
+		return ""
+	},
+	"timezone": func(value string, params []string) string {
+		if _, err := time.LoadLocation(value); err != nil || value == "Local" {
+			return "must be a valid IANA time zone"
+		}
+		return ""
+	},
+}
+
+// Validate checks command against rules declared with `validate` struct tags,
+// returns *apperrors.ValidationError with message for each invalid field
+// rules other than required are not applied to zero values,
+// rules of slice fields are applied to each element
+func Validate(command domain.Command) error {
+	v := reflect.ValueOf(command)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	validationErr := apperrors.NewValidationError()
+	validateStruct(v, validationErr)
+
+	if validationErr.IsEmpty() {
+		return nil
+	}
+
+	return validationErr
+}
+
+func validateStruct(v reflect.Value, validationErr *apperrors.ValidationError) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+
+		tag := f.Tag.Get(validateTagName)
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			name = f.Name
+		}
+
+		if message := validateField(v.Field(i), tag); message != "" {
+			validationErr.Add(name, message)
+		}
+	}
+}
+
+func validateField(v reflect.Value, tag string) string {
+	for _, spec := range strings.Split(tag, ",") {
+		match := ruleRegexp.FindStringSubmatch(strings.TrimSpace(spec))
+		if match == nil {
+			return fmt.Sprintf("has invalid validation rule %q", spec)
+		}
+
+		name := match[1]
+		var params []string
+		if match[2] != "" {
+			params = strings.Split(match[2], "|")
+		}
+
+		if name == "required" {
+			if isZero(v) {
+				return "is required"
+			}
+			continue
+		}
+
+		fn, ok := rules[name]
+		if !ok {
+			return fmt.Sprintf("has unknown validation rule %q", name)
+		}
+
+		if message := applyRule(v, fn, params); message != "" {
+			return message
+		}
+	}
+
+	return ""
+}
+
+func applyRule(v reflect.Value, fn rule, params []string) string {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return ""
+		}
+		return applyRule(v.Elem(), fn, params)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if message := applyRule(v.Index(i), fn, params); message != "" {
+				return fmt.Sprintf("item %d %s", i, message)
+			}
+		}
+		return ""
+	case reflect.String:
+		if v.Len() == 0 {
+			return ""
+		}
+		return fn(v.String(), params)
+	default:
+		return fn(fmt.Sprint(v.Interface()), params)
+	}
+}
+
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
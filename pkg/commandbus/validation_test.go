@@ -0,0 +1,61 @@
+package commandbus
+
+import (
+	"errors"
+	"testing"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+type commandMock struct {
+	Email    string   `json:"email" validate:"required,email"`
+	Redirect string   `json:"redirect_path,omitempty" validate:"requri"`
+	Scopes   []string `json:"scopes" validate:"required,in(read|write)"`
+	Name     string   `json:"name" validate:"length(2|5)"`
+	Locale   string   `json:"locale,omitempty" validate:"locale"`
+	Timezone string   `json:"timezone,omitempty" validate:"timezone"`
+}
+
+func (c commandMock) GetName() string {
+	return "command"
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		command commandMock
+		fields  []string
+	}{
+		{"valid", commandMock{Email: "test@example.com", Scopes: []string{"read"}}, nil},
+		{"valid optional", commandMock{Email: "test@example.com", Redirect: "/home", Scopes: []string{"read", "write"}, Name: "abc", Locale: "en-US", Timezone: "Europe/Warsaw"}, nil},
+		{"missing required", commandMock{}, []string{"email", "scopes"}},
+		{"invalid values", commandMock{Email: "test", Redirect: "home", Scopes: []string{"read", "all"}, Name: "a", Locale: "not a locale", Timezone: "Mars/Olympus"}, []string{"email", "redirect_path", "scopes", "name", "locale", "timezone"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&tt.command)
+			if len(tt.fields) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var validationErr *apperrors.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %v, want %T", err, validationErr)
+			}
+			if !errors.Is(err, apperrors.ErrInvalid) {
+				t.Errorf("Validate() error = %v, want %v", err, apperrors.ErrInvalid)
+			}
+			if len(validationErr.Fields) != len(tt.fields) {
+				t.Fatalf("Validate() fields = %v, want %v", validationErr.Fields, tt.fields)
+			}
+			for i, field := range tt.fields {
+				if validationErr.Fields[i].Field != field {
+					t.Errorf("Validate() field = %s, want %s", validationErr.Fields[i].Field, field)
+				}
+			}
+		})
+	}
+}
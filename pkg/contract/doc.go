@@ -0,0 +1,4 @@
+/*
+Package contract provides JSON Schema registry for event types and command contracts
+*/
+package contract
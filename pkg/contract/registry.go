@@ -0,0 +1,181 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+var defaultRegistry = NewRegistry()
+
+type entry struct {
+	schema    *Schema
+	validator *gojsonschema.Schema
+}
+
+// Registry holds JSON Schemas of event types and command contracts
+type Registry struct {
+	mtx      sync.RWMutex
+	events   map[string]entry
+	commands map[string]entry
+}
+
+// NewRegistry creates empty contract registry
+func NewRegistry() *Registry {
+	return &Registry{
+		events:   make(map[string]entry),
+		commands: make(map[string]entry),
+	}
+}
+
+// RegisterEvent generates schema for event type from its payload value
+func (r *Registry) RegisterEvent(eventType string, v interface{}) error {
+	return r.register(r.events, eventType, v)
+}
+
+// RegisterCommand generates schema for command contract from command value
+func (r *Registry) RegisterCommand(contract string, v interface{}) error {
+	return r.register(r.commands, contract, v)
+}
+
+// ValidateEvent validates event payload against registered schema
+func (r *Registry) ValidateEvent(event *domain.Event) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return apperrors.Wrap(fmt.Errorf("%w: %s: %s", apperrors.ErrInvalid, event.Type, err))
+	}
+
+	return r.validate(r.events, event.Type, payload)
+}
+
+// ValidateCommand validates command payload against registered schema,
+// empty payload is validated as empty object
+func (r *Registry) ValidateCommand(contract string, payload []byte) error {
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+
+	return r.validate(r.commands, contract, payload)
+}
+
+// Events returns schemas of all registered event types
+func (r *Registry) Events() map[string]*Schema {
+	return r.schemas(r.events)
+}
+
+// Commands returns schemas of all registered command contracts
+func (r *Registry) Commands() map[string]*Schema {
+	return r.schemas(r.commands)
+}
+
+func (r *Registry) register(entries map[string]entry, name string, v interface{}) error {
+	if name == "" {
+		return apperrors.New("invalid contract name")
+	}
+
+	schema := Reflect(name, v)
+	validator, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+	if err != nil {
+		return apperrors.Wrap(fmt.Errorf("failed to compile schema for %s: %w", name, err))
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if _, ok := entries[name]; ok {
+		return apperrors.New(fmt.Sprintf("contract %s was already registered", name))
+	}
+	entries[name] = entry{schema: schema, validator: validator}
+
+	return nil
+}
+
+func (r *Registry) validate(entries map[string]entry, name string, payload []byte) error {
+	r.mtx.RLock()
+	e, ok := entries[name]
+	r.mtx.RUnlock()
+
+	if !ok {
+		return apperrors.Wrap(fmt.Errorf("%w: contract %s was not registered", apperrors.ErrInvalid, name))
+	}
+
+	result, err := e.validator.Validate(gojsonschema.NewBytesLoader(payload))
+	if err != nil {
+		return apperrors.Wrap(fmt.Errorf("%w: %s: %s", apperrors.ErrInvalid, name, err))
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	validationErr := apperrors.NewValidationError()
+	for _, resultErr := range result.Errors() {
+		validationErr.Add(fieldName(resultErr), resultErr.Description())
+	}
+	sort.SliceStable(validationErr.Fields, func(i, j int) bool {
+		return validationErr.Fields[i].Field < validationErr.Fields[j].Field
+	})
+
+	return apperrors.Wrap(fmt.Errorf("%s: %w", name, validationErr))
+}
+
+// fieldName reports missing required properties under their own name instead of their parent
+func fieldName(resultErr gojsonschema.ResultError) string {
+	field := resultErr.Field()
+	property, ok := resultErr.Details()["property"].(string)
+	if resultErr.Type() != "required" || !ok {
+		return field
+	}
+	if field == gojsonschema.STRING_CONTEXT_ROOT {
+		return property
+	}
+
+	return field + "." + property
+}
+
+func (r *Registry) schemas(entries map[string]entry) map[string]*Schema {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	schemas := make(map[string]*Schema, len(entries))
+	for name, e := range entries {
+		schemas[name] = e.schema
+	}
+
+	return schemas
+}
+
+// RegisterEvent registers event schema in default registry
+func RegisterEvent(eventType string, v interface{}) error {
+	return defaultRegistry.RegisterEvent(eventType, v)
+}
+
+// RegisterCommand registers command schema in default registry
+func RegisterCommand(contract string, v interface{}) error {
+	return defaultRegistry.RegisterCommand(contract, v)
+}
+
+// ValidateEvent validates event payload against default registry
+func ValidateEvent(event *domain.Event) error {
+	return defaultRegistry.ValidateEvent(event)
+}
+
+// ValidateCommand validates command payload against default registry
+func ValidateCommand(contract string, payload []byte) error {
+	return defaultRegistry.ValidateCommand(contract, payload)
+}
+
+// Events returns event schemas of default registry
+func Events() map[string]*Schema {
+	return defaultRegistry.Events()
+}
+
+// Commands returns command schemas of default registry
+func Commands() map[string]*Schema {
+	return defaultRegistry.Commands()
+}
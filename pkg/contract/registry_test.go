@@ -0,0 +1,93 @@
+package contract
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+type commandMock struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Tags     []string  `json:"tags,omitempty"`
+	Redirect *string   `json:"redirect,omitempty"`
+}
+
+type eventMock struct {
+	ID    uuid.UUID `json:"id"`
+	Count int       `json:"count"`
+}
+
+func (e eventMock) GetType() string {
+	return "event"
+}
+
+func TestRegistry_ValidateCommand(t *testing.T) {
+	r := NewRegistry()
+	if err := r.RegisterCommand("command", commandMock{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RegisterCommand("command", commandMock{}); err == nil {
+		t.Error("expected error when registering contract twice")
+	}
+
+	tests := []struct {
+		name     string
+		contract string
+		payload  string
+		wantErr  bool
+	}{
+		{"valid", "command", `{"id":"` + uuid.New().String() + `","name":"test"}`, false},
+		{"valid with optional", "command", `{"id":"` + uuid.New().String() + `","name":"test","tags":["a"],"redirect":null}`, false},
+		{"missing required", "command", `{"id":"` + uuid.New().String() + `"}`, true},
+		{"invalid format", "command", `{"id":"not-uuid","name":"test"}`, true},
+		{"invalid type", "command", `{"id":"` + uuid.New().String() + `","name":1}`, true},
+		{"empty payload", "command", ``, true},
+		{"malformed payload", "command", `{`, true},
+		{"unknown contract", "unknown", `{}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.ValidateCommand(tt.contract, []byte(tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, apperrors.ErrInvalid) {
+				t.Errorf("ValidateCommand() error = %v, want %v", err, apperrors.ErrInvalid)
+			}
+		})
+	}
+}
+
+func TestRegistry_ValidateEvent(t *testing.T) {
+	r := NewRegistry()
+	if err := r.RegisterEvent((eventMock{}).GetType(), eventMock{}); err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := domain.NewEventFromRawEvent(uuid.New(), "stream", 0, &eventMock{ID: uuid.New(), Count: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ValidateEvent(event); err != nil {
+		t.Errorf("ValidateEvent() error = %v", err)
+	}
+
+	event.Payload = map[string]interface{}{"id": uuid.New().String()}
+	err = r.ValidateEvent(event)
+	var validationErr *apperrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("ValidateEvent() error = %v, want %T", err, validationErr)
+	}
+	if len(validationErr.Fields) != 1 || validationErr.Fields[0].Field != "count" {
+		t.Errorf("ValidateEvent() fields = %v, want count", validationErr.Fields)
+	}
+
+	if _, ok := r.Events()[(eventMock{}).GetType()]; !ok {
+		t.Error("expected event schema to be listed")
+	}
+}
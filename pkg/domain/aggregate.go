@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/metadata"
+)
+
+// Aggregate is an event sourced aggregate, implemented by types embedding AggregateRoot
+type Aggregate interface {
+	// Root returns embedded aggregate root
+	Root() *AggregateRoot
+	// Apply alters aggregate state, it is called for recorded events as well as when loading history
+	Apply(e RawEvent) error
+}
+
+// AggregateRoot holds identity, version and uncommitted changes of event sourced aggregate
+type AggregateRoot struct {
+	id         uuid.UUID
+	streamName string
+	version    int
+	changes    []*Event
+}
+
+// NewAggregateRoot creates aggregate root of given stream
+func NewAggregateRoot(streamName string) AggregateRoot {
+	return AggregateRoot{streamName: streamName}
+}
+
+// Root returns aggregate root
+func (a *AggregateRoot) Root() *AggregateRoot {
+	return a
+}
+
+// SetID sets aggregate root id, it should be called when applying event creating aggregate
+func (a *AggregateRoot) SetID(id uuid.UUID) {
+	a.id = id
+}
+
+// ID returns aggregate root id
+func (a AggregateRoot) ID() uuid.UUID {
+	return a.id
+}
+
+// StreamName returns name of aggregate event stream
+func (a AggregateRoot) StreamName() string {
+	return a.streamName
+}
+
+// Version returns current aggregate root version
+func (a AggregateRoot) Version() int {
+	return a.version
+}
+
+// Changes returns all new recorded events
+func (a AggregateRoot) Changes() []*Event {
+	return a.changes
+}
+
+// Record applies event to aggregate and appends it to changes,
+// event metadata is populated with identity and request metadata from context,
+// identity is recorded without token so bearer tokens are never persisted with events
+func Record(ctx context.Context, aggregate Aggregate, e RawEvent) error {
+	if err := aggregate.Apply(e); err != nil {
+		return err
+	}
+
+	root := aggregate.Root()
+	if root.streamName == "" {
+		return fmt.Errorf("aggregate root of %T has no stream name", aggregate)
+	}
+
+	event, err := NewEventFromRawEvent(root.id, root.streamName, root.version, e)
+	if err != nil {
+		return err
+	}
+
+	var meta EventMetadata
+	if i, ok := identity.FromContext(ctx); ok {
+		meta.Identity = i.WithoutToken()
+	}
+	if m, ok := metadata.FromContext(ctx); ok {
+		meta.IPAddress = m.IPAddress
+		meta.UserAgent = m.UserAgent
+		meta.Referer = m.Referer
+	}
+	if !meta.IsEmpty() {
+		event.WithMetadata(&meta)
+	}
+
+	root.changes = append(root.changes, event)
+	root.version++
+
+	return nil
+}
+
+// FromHistory loads current aggregate state by applying all events in order
+func FromHistory(aggregate Aggregate, events []*Event) error {
+	root := aggregate.Root()
+
+	for _, event := range events {
+		e, ok := event.Payload.(RawEvent)
+		if !ok {
+			return fmt.Errorf("invalid payload %T of event %s", event.Payload, event.Type)
+		}
+
+		if err := aggregate.Apply(e); err != nil {
+			return err
+		}
+
+		root.version++
+	}
+
+	return nil
+}
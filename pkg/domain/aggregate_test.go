@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+type aggregateMock struct {
+	AggregateRoot
+
+	pages int
+}
+
+func (a *aggregateMock) Apply(e RawEvent) error {
+	switch e := e.(type) {
+	case *rawEventMock:
+		if a.ID() == uuid.Nil {
+			a.SetID(uuid.New())
+		}
+		a.pages += e.Page
+	}
+
+	return nil
+}
+
+func TestRecord(t *testing.T) {
+	ctx := identity.ContextWithIdentity(context.Background(), &identity.Identity{Token: "token", UserID: uuid.New()})
+
+	a := &aggregateMock{AggregateRoot: NewAggregateRoot("mock")}
+	for i := 1; i <= 2; i++ {
+		if err := Record(ctx, a, &rawEventMock{Page: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if a.Version() != 2 || a.pages != 3 || len(a.Changes()) != 2 {
+		t.Fatalf("unexpected aggregate state %+v", a)
+	}
+	for i, event := range a.Changes() {
+		if event.StreamID != a.ID() || event.StreamName != "mock" || event.StreamVersion != i {
+			t.Errorf("unexpected event %+v", event)
+		}
+		if event.Metadata == nil || event.Metadata.Identity == nil {
+			t.Errorf("event metadata identity was not set")
+		} else if event.Metadata.Identity.Token != "" {
+			t.Errorf("event metadata identity token was recorded")
+		}
+	}
+
+	loaded := &aggregateMock{AggregateRoot: NewAggregateRoot("mock")}
+	if err := FromHistory(loaded, a.Changes()); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Version() != 2 || loaded.pages != 3 || len(loaded.Changes()) != 0 {
+		t.Errorf("unexpected loaded state %+v", loaded)
+	}
+}
+
+func TestRecordWithoutStreamName(t *testing.T) {
+	if err := Record(context.Background(), &aggregateMock{}, &rawEventMock{}); err == nil {
+		t.Error("expected error for aggregate root without stream name")
+	}
+}
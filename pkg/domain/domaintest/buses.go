@@ -0,0 +1,111 @@
+package domaintest
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+)
+
+// eventBus calls handlers synchronously recording published events
+type eventBus struct {
+	mtx       sync.Mutex
+	handlers  map[string][]eventbus.EventHandler
+	published []*domain.Event
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{handlers: make(map[string][]eventbus.EventHandler)}
+}
+
+func (b *eventBus) Publish(ctx context.Context, event *domain.Event) error {
+	b.mtx.Lock()
+	b.published = append(b.published, event)
+	handlers := append([]eventbus.EventHandler(nil), b.handlers[event.Type]...)
+	b.mtx.Unlock()
+
+	for _, fn := range handlers {
+		if err := fn(ctx, event); err != nil {
+			return apperrors.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+func (b *eventBus) PublishAndAcknowledge(ctx context.Context, event *domain.Event) error {
+	return b.Publish(ctx, event)
+}
+
+func (b *eventBus) Subscribe(ctx context.Context, eventType string, fn eventbus.EventHandler) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.handlers[eventType] = append(b.handlers[eventType], fn)
+
+	return nil
+}
+
+func (b *eventBus) Unsubscribe(ctx context.Context, eventType string, fn eventbus.EventHandler) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	handlers := b.handlers[eventType]
+	for i, h := range handlers {
+		if reflect.ValueOf(h).Pointer() == reflect.ValueOf(fn).Pointer() {
+			b.handlers[eventType] = append(handlers[:i], handlers[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// flush returns events published since last call
+func (b *eventBus) flush() []*domain.Event {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	published := b.published
+	b.published = nil
+
+	return published
+}
+
+// commandBus records published commands, commands are not handled
+type commandBus struct {
+	mtx       sync.Mutex
+	published []domain.Command
+}
+
+func (b *commandBus) Publish(ctx context.Context, command domain.Command) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.published = append(b.published, command)
+
+	return nil
+}
+
+func (b *commandBus) Subscribe(ctx context.Context, commandName string, fn commandbus.CommandHandler) error {
+	return nil
+}
+
+func (b *commandBus) Unsubscribe(ctx context.Context, commandName string) error {
+	return nil
+}
+
+// flush returns commands published since last call
+func (b *commandBus) flush() []domain.Command {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	published := b.published
+	b.published = nil
+
+	return published
+}
@@ -0,0 +1,4 @@
+/*
+Package domaintest provides Given/When/Then scenarios for testing command handlers with in-memory event store
+*/
+package domaintest
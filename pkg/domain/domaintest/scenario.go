@@ -0,0 +1,224 @@
+package domaintest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+	"github.com/vardius/go-api-boilerplate/pkg/eventstore"
+	memoryeventstore "github.com/vardius/go-api-boilerplate/pkg/eventstore/memory"
+)
+
+// Scenario wires in-memory event store and buses,
+// event handlers subscribed with Project keep read models up to date
+type Scenario struct {
+	t          testing.TB
+	eventStore eventstore.EventStore
+	eventBus   *eventBus
+	commandBus *commandBus
+}
+
+// New creates scenario
+func New(t testing.TB) *Scenario {
+	return &Scenario{
+		t:          t,
+		eventStore: memoryeventstore.New(),
+		eventBus:   newEventBus(),
+		commandBus: &commandBus{},
+	}
+}
+
+// EventStore returns event store given events are stored in,
+// repositories passed to command handler should use it
+func (s *Scenario) EventStore() eventstore.EventStore {
+	return s.eventStore
+}
+
+// EventBus returns event bus calling projections synchronously,
+// repositories passed to command handler should use it
+func (s *Scenario) EventBus() eventbus.EventBus {
+	return s.eventBus
+}
+
+// CommandBus returns command bus recording published commands without handling them
+func (s *Scenario) CommandBus() commandbus.CommandBus {
+	return s.commandBus
+}
+
+// Project subscribes read model event handler,
+// it is called for given events without executioncontext.LIVE flag and for emitted events
+func (s *Scenario) Project(eventType string, fn eventbus.EventHandler) *Scenario {
+	s.t.Helper()
+
+	if err := s.eventBus.Subscribe(context.Background(), eventType, fn); err != nil {
+		s.t.Fatal(err)
+	}
+
+	return s
+}
+
+// Given stores events as history of aggregate stream and projects them to read models
+func (s *Scenario) Given(streamID uuid.UUID, streamName string, events ...domain.RawEvent) *Scenario {
+	s.t.Helper()
+
+	ctx := context.Background()
+
+	history, err := s.eventStore.GetStream(ctx, streamID, streamName)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	for i, e := range events {
+		event, err := domain.NewEventFromRawEvent(streamID, streamName, len(history)+i, e)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		if err := s.eventStore.Store(ctx, []*domain.Event{event}); err != nil {
+			s.t.Fatal(err)
+		}
+		if err := s.eventBus.Publish(ctx, event); err != nil {
+			s.t.Fatalf("projecting given %s: %v", event.Type, err)
+		}
+	}
+
+	s.eventBus.flush()
+	s.commandBus.flush()
+
+	return s
+}
+
+// When handles command with handler recording emitted events and published commands
+func (s *Scenario) When(ctx context.Context, handler commandbus.CommandHandler, command domain.Command) *Result {
+	s.eventBus.flush()
+	s.commandBus.flush()
+
+	err := handler(ctx, command)
+
+	return &Result{
+		t:        s.t,
+		err:      err,
+		events:   s.eventBus.flush(),
+		commands: s.commandBus.flush(),
+	}
+}
+
+// Result of handled command
+type Result struct {
+	t        testing.TB
+	err      error
+	events   []*domain.Event
+	commands []domain.Command
+}
+
+// Err returns error returned by command handler
+func (r *Result) Err() error {
+	return r.err
+}
+
+// Events returns emitted events
+func (r *Result) Events() []*domain.Event {
+	return r.events
+}
+
+// Then asserts command was handled emitting given events in order
+func (r *Result) Then(events ...domain.RawEvent) *Result {
+	r.t.Helper()
+
+	if r.err != nil {
+		r.t.Fatalf("unexpected error: %v", r.err)
+	}
+
+	if len(r.events) != len(events) {
+		r.t.Fatalf("emitted %d events %s, want %d %s", len(r.events), payloads(r.events), len(events), dump(events))
+	}
+
+	for i, want := range events {
+		got := r.events[i]
+		if got.Type != want.GetType() || !reflect.DeepEqual(got.Payload, want) {
+			r.t.Errorf("event %d = %s %s, want %s %s", i, got.Type, dump(got.Payload), want.GetType(), dump(want))
+		}
+	}
+
+	return r
+}
+
+// ThenError asserts command handler failed with error matching target
+func (r *Result) ThenError(target error) *Result {
+	r.t.Helper()
+
+	if !errors.Is(r.err, target) {
+		r.t.Fatalf("error = %v, want %v", r.err, target)
+	}
+	if len(r.events) > 0 {
+		r.t.Errorf("failed command emitted events %s", payloads(r.events))
+	}
+
+	return r
+}
+
+// ThenCommands asserts event handlers published given commands in order
+func (r *Result) ThenCommands(commands ...domain.Command) *Result {
+	r.t.Helper()
+
+	if len(r.commands) != len(commands) || (len(commands) > 0 && !reflect.DeepEqual(r.commands, commands)) {
+		r.t.Errorf("published commands %s, want %s", dump(r.commands), dump(commands))
+	}
+
+	return r
+}
+
+// ThenReadModel asserts state of read models, assertion should return error describing mismatch
+func (r *Result) ThenReadModel(assert func(ctx context.Context) error) *Result {
+	r.t.Helper()
+
+	if err := assert(context.Background()); err != nil {
+		r.t.Errorf("read model: %v", err)
+	}
+
+	return r
+}
+
+// Equal returns error if got does not deeply equal want
+func Equal(name string, got, want interface{}) error {
+	if !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("%s = %s, want %s", name, dump(got), dump(want))
+	}
+
+	return nil
+}
+
+// NotFound returns error unless err is apperrors.ErrNotFound
+func NotFound(err error) error {
+	if !errors.Is(err, apperrors.ErrNotFound) {
+		return fmt.Errorf("error = %v, want %v", err, apperrors.ErrNotFound)
+	}
+
+	return nil
+}
+
+func payloads(events []*domain.Event) string {
+	p := make([]interface{}, 0, len(events))
+	for _, e := range events {
+		p = append(p, e.Payload)
+	}
+
+	return dump(p)
+}
+
+func dump(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+
+	return string(b)
+}
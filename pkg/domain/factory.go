@@ -46,3 +46,35 @@ func NewRawEvent(eventType string) (interface{}, error) {
 	}
 	return nil, fmt.Errorf("event for type %s was not registered", eventType)
 }
+
+var commandFactories = make(map[string]func(payload []byte) (Command, error))
+var commandFactoriesMtx sync.RWMutex
+
+// RegisterCommandFactory registers function decoding command of given name from json payload,
+// allows commands to be persisted and dispatched later
+func RegisterCommandFactory(commandName string, factory func(payload []byte) (Command, error)) error {
+	if commandName == "" {
+		return fmt.Errorf("invalid command name")
+	}
+
+	commandFactoriesMtx.Lock()
+	defer commandFactoriesMtx.Unlock()
+	if _, ok := commandFactories[commandName]; ok {
+		return fmt.Errorf("command %s was already registered", commandName)
+	}
+	commandFactories[commandName] = factory
+
+	return nil
+}
+
+// NewCommand decodes command of given name from json payload
+func NewCommand(commandName string, payload []byte) (Command, error) {
+	commandFactoriesMtx.RLock()
+	factory, ok := commandFactories[commandName]
+	commandFactoriesMtx.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("command %s was not registered", commandName)
+	}
+
+	return factory(payload)
+}
@@ -25,3 +25,19 @@ func TestWrap(t *testing.T) {
 		t.Error("Error is not Internal")
 	}
 }
+
+func TestValidationError(t *testing.T) {
+	validationErr := NewValidationError()
+	validationErr.Add("email", "is required")
+
+	err := Wrap(validationErr)
+
+	if !errors.Is(err, ErrInvalid) {
+		t.Error("Error is not Invalid")
+	}
+
+	var target *ValidationError
+	if !errors.As(err, &target) || len(target.Fields) != 1 || target.Fields[0].Field != "email" {
+		t.Errorf("Error does not carry field errors: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes why field value is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError holds per field validation messages,
+// wraps ErrInvalid so it is handled as any other validation failure
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+// NewValidationError returns new validation error with given field errors
+func NewValidationError(fields ...FieldError) *ValidationError {
+	return &ValidationError{Fields: fields}
+}
+
+// Add appends field error
+func (e *ValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// IsEmpty returns true if there are no field errors
+func (e *ValidationError) IsEmpty() bool {
+	return len(e.Fields) == 0
+}
+
+// Error returns the string representation of the error message.
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, fmt.Sprintf("%s: %s", f.Field, f.Message))
+	}
+
+	return fmt.Sprintf("%s: %s", ErrInvalid, strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalid
+}
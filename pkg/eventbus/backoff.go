@@ -0,0 +1,30 @@
+package eventbus
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultBackoff is used by distributed event buses when resubscribing
+var DefaultBackoff = Backoff{
+	Min:    100 * time.Millisecond,
+	Max:    30 * time.Second,
+	Factor: 2,
+}
+
+// Backoff computes exponentially growing delays between reconnect attempts
+type Backoff struct {
+	Min    time.Duration
+	Max    time.Duration
+	Factor float64
+}
+
+// Duration returns delay for given attempt, attempts are counted from 0
+func (b Backoff) Duration(attempt int) time.Duration {
+	d := float64(b.Min) * math.Pow(b.Factor, float64(attempt))
+	if d > float64(b.Max) || math.IsInf(d, 0) {
+		return b.Max
+	}
+
+	return time.Duration(d)
+}
@@ -0,0 +1,29 @@
+package eventbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff_Duration(t *testing.T) {
+	b := Backoff{Min: 100 * time.Millisecond, Max: time.Second, Factor: 2}
+
+	tests := []struct {
+		name    string
+		attempt int
+		want    time.Duration
+	}{
+		{"first attempt", 0, 100 * time.Millisecond},
+		{"second attempt", 1, 200 * time.Millisecond},
+		{"third attempt", 2, 400 * time.Millisecond},
+		{"capped", 4, time.Second},
+		{"overflow", 10000, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.Duration(tt.attempt); got != tt.want {
+				t.Errorf("Duration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
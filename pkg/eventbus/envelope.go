@@ -0,0 +1,97 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/executioncontext"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/metadata"
+)
+
+// Envelope wraps event with request scoped values, allows distributed event buses
+// to pass the same context to the handlers as memory event bus does
+type Envelope struct {
+	Event           *domain.Event         `json:"event"`
+	RequestMetadata *metadata.Metadata    `json:"request_metadata,omitempty"`
+	Identity        *identity.Identity    `json:"identity,omitempty"`
+	Flags           executioncontext.Flag `json:"flags,omitempty"`
+}
+
+// NewEnvelope wraps event with execution flags, metadata and identity carried by context
+func NewEnvelope(ctx context.Context, event *domain.Event) *Envelope {
+	e := &Envelope{
+		Event: event,
+		Flags: executioncontext.FromContext(ctx),
+	}
+
+	if m, ok := metadata.FromContext(ctx); ok {
+		e.RequestMetadata = m
+	}
+	if i, ok := identity.FromContext(ctx); ok {
+		e.Identity = i
+	}
+
+	return e
+}
+
+// Context returns copy of parent carrying envelope values
+func (e *Envelope) Context(parent context.Context) context.Context {
+	ctx := executioncontext.WithFlag(parent, e.Flags)
+	if e.RequestMetadata != nil {
+		ctx = metadata.ContextWithMetadata(ctx, e.RequestMetadata)
+	}
+	if e.Identity != nil {
+		ctx = identity.ContextWithIdentity(ctx, e.Identity)
+	}
+
+	return ctx
+}
+
+// UnmarshalJSON decodes envelope, event payload is decoded with registered event factory
+func (e *Envelope) UnmarshalJSON(data []byte) error {
+	type envelope Envelope
+
+	var raw struct {
+		envelope
+		Event *struct {
+			*domain.Event
+			Payload json.RawMessage `json:"payload,omitempty"`
+		} `json:"event"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	*e = Envelope(raw.envelope)
+
+	if raw.RequestMetadata != nil {
+		raw.RequestMetadata.Now = time.Now()
+	}
+
+	if raw.Event == nil {
+		return nil
+	}
+
+	e.Event = raw.Event.Event
+	if e.Event == nil {
+		e.Event = &domain.Event{}
+	}
+
+	if len(raw.Event.Payload) > 0 && string(raw.Event.Payload) != "null" {
+		payload, err := domain.NewRawEvent(e.Event.Type)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+		if err := json.Unmarshal(raw.Event.Payload, payload); err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		e.Event.Payload = payload
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	"github.com/vardius/go-api-boilerplate/pkg/executioncontext"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/metadata"
+)
+
+type envelopeEventMock struct {
+	Name string `json:"name"`
+}
+
+func (e envelopeEventMock) GetType() string {
+	return "envelope-event"
+}
+
+func TestEnvelope(t *testing.T) {
+	if err := domain.RegisterEventFactory((envelopeEventMock{}).GetType(), func() interface{} { return &envelopeEventMock{} }); err != nil {
+		t.Fatal(err)
+	}
+	defer domain.UnregisterEventData((envelopeEventMock{}).GetType())
+
+	event, err := domain.NewEventFromRawEvent(uuid.New(), "stream", 0, envelopeEventMock{Name: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := metadata.New()
+	i := &identity.Identity{UserID: uuid.New(), Permissions: identity.NewPermissions(identity.PermissionUserRead)}
+
+	ctx := executioncontext.WithFlag(context.Background(), executioncontext.LIVE)
+	ctx = metadata.ContextWithMetadata(ctx, m)
+	ctx = identity.ContextWithIdentity(ctx, i)
+
+	payload, err := json.Marshal(NewEnvelope(ctx, event))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := envelope.Event.Payload.(*envelopeEventMock)
+	if !ok {
+		t.Fatalf("payload type = %T, want %T", envelope.Event.Payload, &envelopeEventMock{})
+	}
+	if got.Name != "test" || envelope.Event.ID != event.ID {
+		t.Errorf("event = %+v, want %+v", envelope.Event, event)
+	}
+
+	handlerCtx := envelope.Context(context.Background())
+
+	if !executioncontext.Has(handlerCtx, executioncontext.LIVE) {
+		t.Error("expected LIVE flag")
+	}
+	if gotMetadata, ok := metadata.FromContext(handlerCtx); !ok || gotMetadata.TraceID != m.TraceID || gotMetadata.CorrelationID != m.CorrelationID {
+		t.Errorf("metadata = %+v, want %+v", gotMetadata, m)
+	}
+	if gotIdentity, ok := identity.FromContext(handlerCtx); !ok || gotIdentity.UserID != i.UserID || gotIdentity.Permissions.String() != i.Permissions.String() {
+		t.Errorf("identity = %+v, want %+v", gotIdentity, i)
+	}
+}
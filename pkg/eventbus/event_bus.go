@@ -19,3 +19,9 @@ type EventBus interface {
 	// PublishAndAcknowledge blocks and returns grouped error after all handlers are executed
 	PublishAndAcknowledge(parentCtx context.Context, event *domain.Event) error
 }
+
+// HealthChecker is implemented by event buses keeping remote subscriptions
+type HealthChecker interface {
+	// HealthCheck returns error if any of the subscriptions is not connected
+	HealthCheck(ctx context.Context) error
+}
@@ -9,10 +9,7 @@ import (
 	"github.com/vardius/go-api-boilerplate/pkg/domain"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
-	"github.com/vardius/go-api-boilerplate/pkg/executioncontext"
-	"github.com/vardius/go-api-boilerplate/pkg/identity"
 	"github.com/vardius/go-api-boilerplate/pkg/logger"
-	"github.com/vardius/go-api-boilerplate/pkg/metadata"
 	messagebus "github.com/vardius/message-bus"
 )
 
@@ -43,14 +40,7 @@ func (b *eventBus) Publish(parentCtx context.Context, event *domain.Event) error
 
 	out := make(chan error, len(handlers))
 
-	flags := executioncontext.FromContext(parentCtx)
-	ctx := executioncontext.WithFlag(context.Background(), flags)
-	if m, ok := metadata.FromContext(parentCtx); ok {
-		ctx = metadata.ContextWithMetadata(ctx, m)
-	}
-	if i, ok := identity.FromContext(parentCtx); ok {
-		ctx = identity.ContextWithIdentity(ctx, i)
-	}
+	ctx := eventbus.NewEnvelope(parentCtx, event).Context(context.Background())
 
 	go func() {
 		logger.Debug(parentCtx, fmt.Sprintf("[EventBus] Publish: %s %+v", event.Type, event))
@@ -71,8 +61,7 @@ func (b *eventBus) PublishAndAcknowledge(parentCtx context.Context, event *domai
 
 	out := make(chan error, len(handlers))
 
-	flags := executioncontext.FromContext(parentCtx)
-	ctx := executioncontext.WithFlag(context.Background(), flags)
+	ctx := eventbus.NewEnvelope(parentCtx, event).Context(context.Background())
 
 	logger.Debug(parentCtx, fmt.Sprintf("[EventBus] PublishAndAcknowledge: %s %+v", event.Type, event))
 	b.messageBus.Publish(event.Type, ctx, event, out)
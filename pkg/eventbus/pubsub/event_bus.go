@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,22 +14,24 @@ import (
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
 	"github.com/vardius/go-api-boilerplate/pkg/logger"
-	"github.com/vardius/go-api-boilerplate/pkg/metadata"
 	pubsubproto "github.com/vardius/pubsub/v2/proto"
 )
 
 // New creates pubsub event bus
 func New(handlerTimeout time.Duration, pubsub pubsubproto.PubSubClient) eventbus.EventBus {
 	return &eventBus{
-		handlerTimeout:      handlerTimeout,
-		pubsub:              pubsub,
-		unsubscribeChannels: make(map[reflect.Value]chan struct{}),
+		handlerTimeout: handlerTimeout,
+		backoff:        eventbus.DefaultBackoff,
+		pubsub:         pubsub,
+		subscriptions:  make(map[reflect.Value]*subscription),
 	}
 }
 
-type dto struct {
-	Event           *domain.Event      `json:"event"`
-	RequestMetadata *metadata.Metadata `json:"request_metadata,omitempty"`
+type subscription struct {
+	eventType string
+	cancel    context.CancelFunc
+	connected bool
+	lastErr   error
 }
 
 // EventBus allow to publish/subscribe to events, allow to push/pull events
@@ -35,61 +39,68 @@ type dto struct {
 // use Publish/Subscribe if you want every handler to be notified of the event
 type eventBus struct {
 	handlerTimeout time.Duration
+	backoff        eventbus.Backoff
 	pubsub         pubsubproto.PubSubClient
 
-	mtx                 sync.RWMutex
-	unsubscribeChannels map[reflect.Value]chan struct{}
+	mtx           sync.RWMutex
+	subscriptions map[reflect.Value]*subscription
 }
 
 // Subscribe registers handler to be notified of every event published
-func (b *eventBus) Subscribe(ctx context.Context, eventType string, fn eventbus.EventHandler) error {
-	stream, err := b.pubsub.Subscribe(ctx, &pubsubproto.SubscribeRequest{
-		Topic: eventType,
-	})
-	if err != nil {
-		return apperrors.Wrap(err)
-	}
-
-	logger.Info(stream.Context(), fmt.Sprintf("[EventBus] Subscribe: %s", eventType))
+// blocks until context is canceled or handler is unsubscribed,
+// lost streams are reestablished with exponential backoff
+func (b *eventBus) Subscribe(parentCtx context.Context, eventType string, fn eventbus.EventHandler) error {
+	ctx, cancel := context.WithCancel(parentCtx)
+	defer cancel()
 
 	rv := reflect.ValueOf(fn)
-	unsubscribeCh := make(chan struct{}, 1)
+	sub := &subscription{eventType: eventType, cancel: cancel}
 
 	b.mtx.Lock()
-	b.unsubscribeChannels[rv] = unsubscribeCh
+	b.subscriptions[rv] = sub
 	b.mtx.Unlock()
 
-	ctxDoneCh := ctx.Done()
+	defer func() {
+		b.mtx.Lock()
+		if b.subscriptions[rv] == sub {
+			delete(b.subscriptions, rv)
+		}
+		b.mtx.Unlock()
+	}()
+
+	logger.Info(ctx, fmt.Sprintf("[EventBus] Subscribe: %s", eventType))
+
+	var attempt int
 	for {
+		err := b.subscribe(ctx, sub, fn, func() { attempt = 0 })
+
+		b.mtx.Lock()
+		sub.connected = false
+		sub.lastErr = err
+		b.mtx.Unlock()
+
+		if parentCtx.Err() != nil {
+			return parentCtx.Err()
+		}
+		if ctx.Err() != nil {
+			return nil // unsubscribed
+		}
+
+		delay := b.backoff.Duration(attempt)
+		attempt++
+
+		logger.Warning(ctx, fmt.Sprintf("[EventBus] Subscription %s lost, reconnecting in %s: %v", eventType, delay, err))
+
 		select {
-		case <-ctxDoneCh:
-			return ctx.Err()
-		case <-unsubscribeCh:
-			return nil
-		default:
-			resp, err := stream.Recv()
-			if err != nil {
-				return apperrors.Wrap(err)
-			}
-
-			if err := b.dispatchEvent(resp.GetPayload(), fn); err != nil {
-				return apperrors.Wrap(err)
-			}
+		case <-ctx.Done():
+		case <-time.After(delay):
 		}
 	}
 }
 
 // Publish sends event to every client subscribed
 func (b *eventBus) Publish(ctx context.Context, event *domain.Event) error {
-	o := dto{
-		Event: event,
-	}
-
-	if m, ok := metadata.FromContext(ctx); ok {
-		o.RequestMetadata = m
-	}
-
-	payload, err := json.Marshal(o)
+	payload, err := json.Marshal(eventbus.NewEnvelope(ctx, event))
 	if err != nil {
 		return apperrors.Wrap(err)
 	}
@@ -110,33 +121,80 @@ func (b *eventBus) PublishAndAcknowledge(ctx context.Context, event *domain.Even
 	panic("not implemented")
 }
 
-// Unsubscribe will unsubscribe after next event handler because stream.Recv() is blocking
-// this method was implemented only to satisfy interface
+// Unsubscribe cancels subscription stream of the handler
 func (b *eventBus) Unsubscribe(ctx context.Context, eventType string, fn eventbus.EventHandler) error {
 	rv := reflect.ValueOf(fn)
 	b.mtx.RLock()
-	if ch, ok := b.unsubscribeChannels[rv]; ok {
-		ch <- struct{}{}
+	if sub, ok := b.subscriptions[rv]; ok {
+		sub.cancel()
 	}
 	b.mtx.RUnlock()
 	logger.Info(ctx, fmt.Sprintf("[EventBus] Unsubscribe: %s", eventType))
 	return nil
 }
 
-func (b *eventBus) dispatchEvent(payload []byte, fn eventbus.EventHandler) error {
-	ctx, cancel := context.WithTimeout(context.Background(), b.handlerTimeout)
-	defer cancel()
+// HealthCheck returns error if any of the subscriptions is not connected
+func (b *eventBus) HealthCheck(ctx context.Context) error {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
 
-	var o dto
-	if err := json.Unmarshal(payload, &o); err != nil {
+	var unhealthy []string
+	for _, sub := range b.subscriptions {
+		if !sub.connected {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%v)", sub.eventType, sub.lastErr))
+		}
+	}
+
+	if len(unhealthy) > 0 {
+		sort.Strings(unhealthy)
+		return apperrors.New(fmt.Sprintf("event bus subscriptions not connected: %s", strings.Join(unhealthy, ", ")))
+	}
+
+	return nil
+}
+
+// subscribe opens stream and dispatches received events until stream fails,
+// handler errors are logged and do not close the stream
+func (b *eventBus) subscribe(ctx context.Context, sub *subscription, fn eventbus.EventHandler, onConnected func()) error {
+	stream, err := b.pubsub.Subscribe(ctx, &pubsubproto.SubscribeRequest{
+		Topic: sub.eventType,
+	})
+	if err != nil {
 		return apperrors.Wrap(err)
 	}
 
-	if o.RequestMetadata != nil {
-		ctx = metadata.ContextWithMetadata(ctx, o.RequestMetadata)
+	b.mtx.Lock()
+	sub.connected = true
+	sub.lastErr = nil
+	b.mtx.Unlock()
+
+	onConnected()
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+
+		if err := b.dispatchEvent(resp.GetPayload(), fn); err != nil {
+			logger.Error(ctx, fmt.Sprintf("[EventBus] Handler error: %s %v", sub.eventType, err))
+		}
+	}
+}
+
+func (b *eventBus) dispatchEvent(payload []byte, fn eventbus.EventHandler) error {
+	var envelope eventbus.Envelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return apperrors.Wrap(err)
 	}
+	if envelope.Event == nil {
+		return apperrors.New("envelope does not contain event")
+	}
+
+	ctx, cancel := context.WithTimeout(envelope.Context(context.Background()), b.handlerTimeout)
+	defer cancel()
 
-	logger.Debug(ctx, fmt.Sprintf("[EventBus] Dispatch Event: %s %s", o.Event.Type, o.Event.Payload))
+	logger.Debug(ctx, fmt.Sprintf("[EventBus] Dispatch Event: %s %+v", envelope.Event.Type, envelope.Event.Payload))
 
-	return fn(ctx, o.Event)
+	return fn(ctx, envelope.Event)
 }
@@ -0,0 +1,131 @@
+package pushpull
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	pushpullproto "github.com/vardius/pushpull/proto"
+	"google.golang.org/grpc"
+
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+)
+
+type streamMessage struct {
+	payload []byte
+	err     error
+}
+
+type streamMock struct {
+	grpc.ClientStream
+	ctx      context.Context
+	messages chan streamMessage
+}
+
+func (s *streamMock) Recv() (*pushpullproto.PullResponse, error) {
+	select {
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	case m := <-s.messages:
+		if m.err != nil {
+			return nil, m.err
+		}
+		return &pushpullproto.PullResponse{Payload: m.payload}, nil
+	}
+}
+
+type clientMock struct {
+	messages      chan streamMessage
+	subscriptions chan struct{}
+}
+
+func (c *clientMock) Push(ctx context.Context, in *pushpullproto.PushRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	c.messages <- streamMessage{payload: in.GetPayload()}
+	return new(empty.Empty), nil
+}
+
+func (c *clientMock) Pull(ctx context.Context, in *pushpullproto.PullRequest, opts ...grpc.CallOption) (pushpullproto.PushPull_PullClient, error) {
+	c.subscriptions <- struct{}{}
+	return &streamMock{ctx: ctx, messages: c.messages}, nil
+}
+
+func TestSubscribeReconnects(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	client := &clientMock{
+		messages:      make(chan streamMessage, 10),
+		subscriptions: make(chan struct{}, 10),
+	}
+	bus := New(time.Second, client)
+	bus.(*eventBus).backoff = eventbus.Backoff{Min: time.Millisecond, Max: time.Millisecond, Factor: 1}
+
+	handled := make(chan string, 10)
+	handler := func(ctx context.Context, event *domain.Event) error {
+		handled <- event.StreamName
+		if event.StreamName == "failing" {
+			return errors.New("handler error")
+		}
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- bus.Subscribe(ctx, "event", handler)
+	}()
+
+	<-client.subscriptions
+
+	for _, name := range []string{"failing", "first"} {
+		payload, err := json.Marshal(eventbus.Envelope{Event: &domain.Event{Type: "event", StreamName: name}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.messages <- streamMessage{payload: payload}
+	}
+	client.messages <- streamMessage{err: errors.New("connection lost")}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected subscription to be reestablished")
+	case <-client.subscriptions:
+	}
+
+	payload, err := json.Marshal(eventbus.Envelope{Event: &domain.Event{Type: "event", StreamName: "second"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.messages <- streamMessage{payload: payload}
+
+	for _, want := range []string{"failing", "first", "second"} {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("expected %s event to be handled", want)
+		case got := <-handled:
+			if got != want {
+				t.Errorf("handled %s, want %s", got, want)
+			}
+		}
+	}
+
+	if err := bus.(eventbus.HealthChecker).HealthCheck(ctx); err != nil {
+		t.Errorf("HealthCheck() error = %v", err)
+	}
+
+	if err := bus.Unsubscribe(ctx, "event", handler); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected subscription to stop after unsubscribe")
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Subscribe() error = %v", err)
+		}
+	}
+}
@@ -15,4 +15,6 @@ type EventStore interface {
 	FindAll(ctx context.Context) ([]*domain.Event, error)
 	GetStream(ctx context.Context, streamID uuid.UUID, streamName string) ([]*domain.Event, error)
 	GetStreamEventsByType(ctx context.Context, streamID uuid.UUID, streamName, eventType string) ([]*domain.Event, error)
+	// DeleteStream erases all events of stream, it is used to remove personal data kept in event history
+	DeleteStream(ctx context.Context, streamID uuid.UUID, streamName string) error
 }
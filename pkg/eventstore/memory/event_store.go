@@ -85,6 +85,17 @@ func (s *eventStore) GetStreamEventsByType(ctx context.Context, streamID uuid.UU
 	return e, nil
 }
 
+func (s *eventStore) DeleteStream(ctx context.Context, streamID uuid.UUID, streamName string) error {
+	s.Lock()
+	defer s.Unlock()
+	for id, val := range s.events {
+		if val.StreamName == streamName && val.StreamID == streamID {
+			delete(s.events, id)
+		}
+	}
+	return nil
+}
+
 // New creates in memory event store
 func New() baseeventstore.EventStore {
 	return &eventStore{
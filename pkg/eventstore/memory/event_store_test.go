@@ -72,3 +72,35 @@ func TestEventStore(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestEventStoreDeleteStream(t *testing.T) {
+	streamID := uuid.New()
+	otherStreamID := uuid.New()
+	streamName := "test"
+
+	e1, err := domain.NewEventFromRawEvent(streamID, streamName, 1, rawEventMock{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e2, err := domain.NewEventFromRawEvent(otherStreamID, streamName, 1, rawEventMock{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	store := New()
+
+	if err := store.Store(ctx, []*domain.Event{e1, e2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteStream(ctx, streamID, streamName); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, err := store.GetStream(ctx, streamID, streamName); err != nil || len(s) != 0 {
+		t.Errorf("GetStream() = %v, %v, want deleted stream", s, err)
+	}
+	if s, err := store.GetStream(ctx, otherStreamID, streamName); err != nil || len(s) != 1 {
+		t.Errorf("GetStream() = %v, %v, want other stream to be left intact", s, err)
+	}
+}
@@ -208,3 +208,16 @@ func (s *eventStore) GetStreamEventsByType(ctx context.Context, streamID uuid.UU
 
 	return result, nil
 }
+
+func (s *eventStore) DeleteStream(ctx context.Context, streamID uuid.UUID, streamName string) error {
+	filter := bson.M{
+		"stream_id":   streamID.String(),
+		"stream_name": streamName,
+	}
+
+	if _, err := s.collection.DeleteMany(ctx, filter); err != nil {
+		return apperrors.Wrap(fmt.Errorf("failed to delete events: %w", err))
+	}
+
+	return nil
+}
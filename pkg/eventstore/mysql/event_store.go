@@ -261,6 +261,15 @@ func (s *eventStore) GetStreamEventsByType(ctx context.Context, streamID uuid.UU
 	return events, nil
 }
 
+func (s *eventStore) DeleteStream(ctx context.Context, streamID uuid.UUID, streamName string) error {
+	query := "DELETE FROM " + s.tableName + " WHERE stream_id=? AND stream_name=?"
+	if _, err := s.db.ExecContext(ctx, query, streamID.String(), streamName); err != nil {
+		return apperrors.Wrap(fmt.Errorf("%w: %s (%s, %s)", err, query, streamID.String(), streamName))
+	}
+
+	return nil
+}
+
 func getRawEvent(eventType string, data json.RawMessage) (domain.RawEvent, error) {
 	rawEvent, err := domain.NewRawEvent(eventType)
 	if err != nil {
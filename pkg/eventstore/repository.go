@@ -0,0 +1,68 @@
+package eventstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/contract"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+)
+
+// Repository is an event sourced repository of aggregates
+type Repository struct {
+	eventStore EventStore
+	eventBus   eventbus.EventBus
+}
+
+// NewRepository creates new event sourced repository
+func NewRepository(store EventStore, bus eventbus.EventBus) *Repository {
+	return &Repository{
+		eventStore: store,
+		eventBus:   bus,
+	}
+}
+
+// Save aggregate changes to event store and publish each event with an event bus
+func (r *Repository) Save(ctx context.Context, aggregate domain.Aggregate) error {
+	changes := aggregate.Root().Changes()
+
+	for _, event := range changes {
+		if err := contract.ValidateEvent(event); err != nil {
+			return apperrors.Wrap(err)
+		}
+	}
+
+	if err := r.eventStore.Store(ctx, changes); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	for _, event := range changes {
+		if err := r.eventBus.Publish(ctx, event); err != nil {
+			return apperrors.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// Get loads aggregate state from its event stream,
+// returns apperrors.ErrNotFound if stream has no events
+func (r *Repository) Get(ctx context.Context, id uuid.UUID, aggregate domain.Aggregate) error {
+	events, err := r.eventStore.GetStream(ctx, id, aggregate.Root().StreamName())
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	if len(events) == 0 {
+		return apperrors.Wrap(apperrors.ErrNotFound)
+	}
+
+	if err := domain.FromHistory(aggregate, events); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/logger"
+)
+
+// Dispatcher publishes commands in the background tracking their execution
+type Dispatcher struct {
+	commandBus commandbus.CommandBus
+	store      Store
+	ttl        time.Duration
+}
+
+// NewDispatcher creates dispatcher, executions are kept for ttl
+func NewDispatcher(commandBus commandbus.CommandBus, store Store, ttl time.Duration) *Dispatcher {
+	return &Dispatcher{
+		commandBus: commandBus,
+		store:      store,
+		ttl:        ttl,
+	}
+}
+
+// Dispatch stores pending execution and publishes command in the background,
+// command is handled with context values of ctx but is not cancelled with it
+func (d *Dispatcher) Dispatch(ctx context.Context, command domain.Command) (*Execution, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, apperrors.Wrap(fmt.Errorf("%w: Could not generate new id: %s", apperrors.ErrInternal, err))
+	}
+
+	now := time.Now()
+	e := &Execution{
+		ID:        id,
+		Command:   command.GetName(),
+		Status:    StatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(d.ttl),
+	}
+	if i, ok := identity.FromContext(ctx); ok {
+		e.UserID = i.UserID
+	}
+
+	if err := d.store.Save(ctx, e); err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+
+	pending := *e
+	go d.execute(detachedContext{ctx}, pending, command)
+
+	return e, nil
+}
+
+func (d *Dispatcher) execute(ctx context.Context, e Execution, command domain.Command) {
+	e.Status = StatusSucceeded
+	if err := d.commandBus.Publish(ctx, command); err != nil {
+		e.Status = StatusFailed
+		e.Error = err.Error()
+	}
+	e.UpdatedAt = time.Now()
+
+	if err := d.store.Save(ctx, &e); err != nil {
+		logger.Error(ctx, fmt.Sprintf("[Execution] Save %s %s failed: %v", e.Command, e.ID, err))
+	}
+}
+
+// detachedContext keeps values of parent context but is never cancelled
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
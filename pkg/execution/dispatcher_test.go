@@ -0,0 +1,96 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus/memory"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+)
+
+type commandMock struct{}
+
+func (c commandMock) GetName() string {
+	return "command"
+}
+
+type storeMock struct {
+	mtx        sync.Mutex
+	executions map[uuid.UUID]Execution
+}
+
+func (s *storeMock) Save(ctx context.Context, e *Execution) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.executions[e.ID] = *e
+	return nil
+}
+
+func (s *storeMock) Get(ctx context.Context, id uuid.UUID) (*Execution, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	e, ok := s.executions[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return &e, nil
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	store := &storeMock{executions: make(map[uuid.UUID]Execution)}
+	bus := memory.New(runtime.NumCPU())
+	dispatcher := NewDispatcher(bus, store, time.Minute)
+
+	release := make(chan struct{})
+	errHandler := errors.New("handler failed")
+	userID := uuid.New()
+
+	if err := bus.Subscribe(context.Background(), "command", func(ctx context.Context, _ domain.Command) error {
+		<-release
+		if i, ok := identity.FromContext(ctx); !ok || i.UserID != userID {
+			t.Error("expected identity to be passed to handler")
+		}
+		return errHandler
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(identity.ContextWithIdentity(context.Background(), &identity.Identity{UserID: userID}))
+	e, err := dispatcher.Dispatch(ctx, commandMock{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// handling should not be cancelled with request context
+	cancel()
+
+	if e.Status != StatusPending || e.UserID != userID {
+		t.Errorf("Dispatch() = %+v, want pending execution of user %s", e, userID)
+	}
+
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		stored, err := store.Get(context.Background(), e.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.Status != StatusPending {
+			if stored.Status != StatusFailed || stored.Error == "" {
+				t.Errorf("execution = %+v, want failed with error", stored)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Error("execution did not complete")
+}
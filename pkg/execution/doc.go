@@ -0,0 +1,4 @@
+/*
+Package execution provides asynchronous command execution tracking
+*/
+package execution
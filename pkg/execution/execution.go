@@ -0,0 +1,38 @@
+package execution
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status of command execution
+type Status string
+
+// Command execution statuses
+const (
+	StatusPending   Status = "pending"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Execution holds state of command dispatched asynchronously
+type Execution struct {
+	ID        uuid.UUID `json:"id"`
+	Command   string    `json:"command"`
+	Status    Status    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	UserID    uuid.UUID `json:"-"` // identity that dispatched command, uuid.Nil if anonymous
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"-"`
+}
+
+// Store persists command executions
+type Store interface {
+	// Save creates or updates execution
+	Save(ctx context.Context, e *Execution) error
+	// Get returns execution by id, returns apperrors.ErrNotFound if it does not exist or has expired
+	Get(ctx context.Context, id uuid.UUID) (*Execution, error)
+}
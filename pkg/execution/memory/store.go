@@ -0,0 +1,53 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	baseexecution "github.com/vardius/go-api-boilerplate/pkg/execution"
+)
+
+type store struct {
+	mtx        sync.RWMutex
+	executions map[uuid.UUID]baseexecution.Execution
+}
+
+// New creates in memory command execution store
+func New() baseexecution.Store {
+	return &store{
+		executions: make(map[uuid.UUID]baseexecution.Execution),
+	}
+}
+
+func (s *store) Save(ctx context.Context, e *baseexecution.Execution) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := time.Now()
+	for id, existing := range s.executions {
+		if existing.ExpiresAt.Before(now) {
+			delete(s.executions, id)
+		}
+	}
+
+	s.executions[e.ID] = *e
+
+	return nil
+}
+
+func (s *store) Get(ctx context.Context, id uuid.UUID) (*baseexecution.Execution, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	e, ok := s.executions[id]
+	if !ok || e.ExpiresAt.Before(time.Now()) {
+		return nil, apperrors.Wrap(fmt.Errorf("%w: execution %s", apperrors.ErrNotFound, id))
+	}
+
+	return &e, nil
+}
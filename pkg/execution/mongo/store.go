@@ -0,0 +1,102 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	baseexecution "github.com/vardius/go-api-boilerplate/pkg/execution"
+)
+
+type execution struct {
+	ID        string    `bson:"execution_id"`
+	Command   string    `bson:"command"`
+	Status    string    `bson:"status"`
+	Error     string    `bson:"error,omitempty"`
+	UserID    string    `bson:"user_id"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+type store struct {
+	collection *mongo.Collection
+}
+
+// New creates mongo command execution store
+func New(ctx context.Context, collectionName string, mongoDB *mongo.Database) (baseexecution.Store, error) {
+	if collectionName == "" {
+		collectionName = "command_executions"
+	}
+
+	collection := mongoDB.Collection(collectionName)
+
+	if _, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "execution_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(1),
+		},
+	}); err != nil {
+		return nil, apperrors.Wrap(fmt.Errorf("failed to create indexes: %w", err))
+	}
+
+	return &store{collection: collection}, nil
+}
+
+func (s *store) Save(ctx context.Context, e *baseexecution.Execution) error {
+	doc := execution{
+		ID:        e.ID.String(),
+		Command:   e.Command,
+		Status:    string(e.Status),
+		Error:     e.Error,
+		UserID:    e.UserID.String(),
+		CreatedAt: e.CreatedAt.UTC(),
+		UpdatedAt: e.UpdatedAt.UTC(),
+		ExpiresAt: e.ExpiresAt.UTC(),
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.collection.ReplaceOne(ctx, bson.M{"execution_id": doc.ID}, doc, opts); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *store) Get(ctx context.Context, id uuid.UUID) (*baseexecution.Execution, error) {
+	filter := bson.M{
+		"execution_id": id.String(),
+		"expires_at":   bson.M{"$gt": time.Now().UTC()},
+	}
+
+	var result execution
+	if err := s.collection.FindOne(ctx, filter).Decode(&result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperrors.Wrap(fmt.Errorf("%w: execution %s", apperrors.ErrNotFound, id))
+		}
+
+		return nil, apperrors.Wrap(err)
+	}
+
+	return &baseexecution.Execution{
+		ID:        uuid.MustParse(result.ID),
+		Command:   result.Command,
+		Status:    baseexecution.Status(result.Status),
+		Error:     result.Error,
+		UserID:    uuid.MustParse(result.UserID),
+		CreatedAt: result.CreatedAt,
+		UpdatedAt: result.UpdatedAt,
+		ExpiresAt: result.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package execution
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	baseexecution "github.com/vardius/go-api-boilerplate/pkg/execution"
+)
+
+const createTableSQLFormat = `
+CREATE TABLE IF NOT EXISTS %s
+(
+    id         CHAR(36)     NOT NULL,
+    command    VARCHAR(255) NOT NULL,
+    status     VARCHAR(16)  NOT NULL,
+    error      TEXT,
+    user_id    CHAR(36)     NOT NULL,
+    created_at DATETIME     NOT NULL,
+    updated_at DATETIME     NOT NULL,
+    expires_at DATETIME     NOT NULL,
+    PRIMARY KEY (id),
+    INDEX i_expires_at (expires_at)
+)
+    ENGINE = InnoDB
+    DEFAULT CHARSET = utf8
+    COLLATE = utf8_bin;
+`
+
+type store struct {
+	tableName string
+	db        *sql.DB
+}
+
+// New creates mysql command execution store
+func New(ctx context.Context, tableName string, db *sql.DB) (baseexecution.Store, error) {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(createTableSQLFormat, tableName)); err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+
+	return &store{tableName: tableName, db: db}, nil
+}
+
+func (s *store) Save(ctx context.Context, e *baseexecution.Execution) error {
+	if _, err := s.db.ExecContext(ctx,
+		"REPLACE INTO "+s.tableName+" (id, command, status, error, user_id, created_at, updated_at, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		e.ID.String(), e.Command, string(e.Status), e.Error, e.UserID.String(), e.CreatedAt.UTC(), e.UpdatedAt.UTC(), e.ExpiresAt.UTC(),
+	); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *store) Get(ctx context.Context, id uuid.UUID) (*baseexecution.Execution, error) {
+	row := s.db.QueryRowContext(ctx,
+		"SELECT id, command, status, error, user_id, created_at, updated_at, expires_at FROM "+s.tableName+" WHERE id=? AND expires_at>? LIMIT 1",
+		id.String(), time.Now().UTC(),
+	)
+
+	var (
+		e        baseexecution.Execution
+		eID      string
+		status   string
+		errorMsg sql.NullString
+		userID   string
+	)
+	err := row.Scan(&eID, &e.Command, &status, &errorMsg, &userID, &e.CreatedAt, &e.UpdatedAt, &e.ExpiresAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, apperrors.Wrap(fmt.Errorf("%w: execution %s", apperrors.ErrNotFound, id))
+	case err != nil:
+		return nil, apperrors.Wrap(err)
+	}
+
+	e.ID = uuid.MustParse(eID)
+	e.UserID = uuid.MustParse(userID)
+	e.Status = baseexecution.Status(status)
+	e.Error = errorMsg.String
+
+	return &e, nil
+}
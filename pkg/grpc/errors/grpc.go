@@ -1,11 +1,15 @@
 package errors
 
 import (
+	"context"
 	"errors"
-	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"fmt"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
 )
 
 func NewGRPCError(err error) error {
@@ -24,9 +28,93 @@ func NewGRPCError(err error) error {
 		code = codes.DeadlineExceeded
 	case errors.Is(err, apperrors.ErrTemporaryDisabled):
 		code = codes.Unavailable
+	case errors.Is(err, apperrors.ErrConflict):
+		code = codes.Aborted
+	case errors.Is(err, apperrors.ErrUnprocessable):
+		code = codes.FailedPrecondition
+	case errors.Is(err, apperrors.ErrTooManyRequests):
+		code = codes.ResourceExhausted
 	case errors.Is(err, apperrors.ErrInternal):
 		code = codes.Internal
 	}
 
-	return status.Error(code, err.Error())
+	return WithBadRequest(status.New(code, err.Error()), err).Err()
+}
+
+// FromGRPCError maps status code of err to application error,
+// field violations of InvalidArgument status are restored as validation error
+func FromGRPCError(err error) error {
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		if validationErr := ValidationErrorFromStatus(st); validationErr != nil {
+			return fmt.Errorf("%s: %w", st.Message(), validationErr)
+		}
+		return fmt.Errorf("%w: %s", apperrors.ErrInvalid, err)
+	case codes.Unauthenticated:
+		return fmt.Errorf("%w: %s", apperrors.ErrUnauthorized, err)
+	case codes.PermissionDenied:
+		return fmt.Errorf("%w: %s", apperrors.ErrForbidden, err)
+	case codes.NotFound:
+		return fmt.Errorf("%w: %s", apperrors.ErrNotFound, err)
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %s", apperrors.ErrTimeout, err)
+	case codes.Unavailable:
+		return fmt.Errorf("%w: %s", apperrors.ErrTemporaryDisabled, err)
+	case codes.Aborted:
+		return fmt.Errorf("%w: %s", apperrors.ErrConflict, err)
+	case codes.FailedPrecondition:
+		return fmt.Errorf("%w: %s", apperrors.ErrUnprocessable, err)
+	case codes.ResourceExhausted:
+		return fmt.Errorf("%w: %s", apperrors.ErrTooManyRequests, err)
+	case codes.Canceled:
+		return fmt.Errorf("%w: %s", context.Canceled, err)
+	default:
+		return fmt.Errorf("%w: %s", apperrors.ErrInternal, err)
+	}
+}
+
+// WithBadRequest attaches field errors of validation error to status as BadRequest details,
+// returns status unchanged if err is not a validation error
+func WithBadRequest(st *status.Status, err error) *status.Status {
+	var validationErr *apperrors.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.IsEmpty() {
+		return st
+	}
+
+	br := &errdetails.BadRequest{}
+	for _, f := range validationErr.Fields {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Message,
+		})
+	}
+
+	withDetails, detailsErr := st.WithDetails(br)
+	if detailsErr != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+// ValidationErrorFromStatus rebuilds validation error from status BadRequest details,
+// returns nil if status does not carry any field violations
+func ValidationErrorFromStatus(st *status.Status) *apperrors.ValidationError {
+	validationErr := apperrors.NewValidationError()
+	for _, detail := range st.Details() {
+		br, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, violation := range br.GetFieldViolations() {
+			validationErr.Add(violation.GetField(), violation.GetDescription())
+		}
+	}
+
+	if validationErr.IsEmpty() {
+		return nil
+	}
+
+	return validationErr
 }
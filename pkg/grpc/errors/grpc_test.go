@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+)
+
+func TestNewGRPCError(t *testing.T) {
+	err := NewGRPCError(apperrors.Wrap(fmt.Errorf("command: %w", apperrors.NewValidationError(
+		apperrors.FieldError{Field: "email", Message: "is required"},
+	))))
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Errorf("NewGRPCError() code = %s, want %s", st.Code(), codes.InvalidArgument)
+	}
+
+	validationErr := ValidationErrorFromStatus(st)
+	if validationErr == nil {
+		t.Fatal("expected BadRequest details")
+	}
+	if len(validationErr.Fields) != 1 || validationErr.Fields[0].Field != "email" {
+		t.Errorf("ValidationErrorFromStatus() fields = %v", validationErr.Fields)
+	}
+	if !errors.Is(validationErr, apperrors.ErrInvalid) {
+		t.Errorf("ValidationErrorFromStatus() error = %v, want %v", validationErr, apperrors.ErrInvalid)
+	}
+
+	if ValidationErrorFromStatus(status.Convert(NewGRPCError(apperrors.ErrNotFound))) != nil {
+		t.Error("expected no BadRequest details")
+	}
+}
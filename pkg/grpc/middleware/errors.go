@@ -3,8 +3,8 @@ package middleware
 import (
 	"context"
 	"errors"
-	"fmt"
 	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	grpcerrors "github.com/vardius/go-api-boilerplate/pkg/grpc/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -57,7 +57,7 @@ func TransformStreamOutgoingError() grpc.StreamServerInterceptor {
 func TransformUnaryIncomingError() grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 		if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
-			return apperrors.Wrap(toAppError(err))
+			return apperrors.Wrap(grpcerrors.FromGRPCError(err))
 		}
 
 		return nil
@@ -73,45 +73,19 @@ func TransformStreamIncomingError() grpc.StreamClientInterceptor {
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
 		stream, err := streamer(ctx, desc, cc, method, opts...)
 		if err != nil {
-			return stream, apperrors.Wrap(toAppError(err))
+			return stream, apperrors.Wrap(grpcerrors.FromGRPCError(err))
 		}
 
 		return stream, err
 	}
 }
 
-func toAppError(err error) error {
-	statusCode := status.Convert(err)
-	switch {
-	case statusCode.Code() == codes.InvalidArgument:
-		err = fmt.Errorf("%w: %s", apperrors.ErrInvalid, err)
-	case statusCode.Code() == codes.Unauthenticated:
-		err = fmt.Errorf("%w: %s", apperrors.ErrUnauthorized, err)
-	case statusCode.Code() == codes.PermissionDenied:
-		err = fmt.Errorf("%w: %s", apperrors.ErrForbidden, err)
-	case statusCode.Code() == codes.NotFound:
-		err = fmt.Errorf("%w: %s", apperrors.ErrNotFound, err)
-	case statusCode.Code() == codes.DeadlineExceeded:
-		err = fmt.Errorf("%w: %s", apperrors.ErrTimeout, err)
-	case statusCode.Code() == codes.Unavailable:
-		err = fmt.Errorf("%w: %s", apperrors.ErrTemporaryDisabled, err)
-	case statusCode.Code() == codes.Internal:
-		err = fmt.Errorf("%w: %s", apperrors.ErrInternal, err)
-	case statusCode.Code() == codes.Canceled:
-		err = fmt.Errorf("%w: %s", context.Canceled, err)
-	default:
-		err = fmt.Errorf("%w: %s", apperrors.ErrInternal, err)
-	}
-
-	return err
-}
-
 func fromAppError(err error) error {
 	statusCode := status.Convert(err)
 	if statusCode.Code() == codes.Unknown {
 		switch {
 		case errors.Is(err, apperrors.ErrInvalid):
-			return status.Error(codes.InvalidArgument, err.Error())
+			return grpcerrors.WithBadRequest(status.New(codes.InvalidArgument, err.Error()), err).Err()
 		case errors.Is(err, apperrors.ErrUnauthorized):
 			return status.Error(codes.Unauthenticated, err.Error())
 		case errors.Is(err, apperrors.ErrForbidden):
@@ -122,6 +96,12 @@ func fromAppError(err error) error {
 			return status.Error(codes.DeadlineExceeded, err.Error())
 		case errors.Is(err, apperrors.ErrTemporaryDisabled):
 			return status.Error(codes.Unavailable, err.Error())
+		case errors.Is(err, apperrors.ErrConflict):
+			return status.Error(codes.Aborted, err.Error())
+		case errors.Is(err, apperrors.ErrUnprocessable):
+			return status.Error(codes.FailedPrecondition, err.Error())
+		case errors.Is(err, apperrors.ErrTooManyRequests):
+			return status.Error(codes.ResourceExhausted, err.Error())
 		case errors.Is(err, apperrors.ErrInternal):
 			return status.Error(codes.Internal, err.Error())
 		case errors.Is(err, context.DeadlineExceeded):
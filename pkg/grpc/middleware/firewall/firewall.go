@@ -122,8 +122,8 @@ func GrantAccessForStreamRequest(permission identity.Permission) grpc.StreamServ
 		if !ok {
 			return apperrors.Wrap(fmt.Errorf("%w: missing identity", apperrors.ErrUnauthorized))
 		}
-		if !i.Permission.Has(permission) {
-			return apperrors.Wrap(fmt.Errorf("%w: (%d) missing permission: %d", apperrors.ErrForbidden, i.Permission, permission))
+		if !i.Permissions.Has(permission) {
+			return apperrors.Wrap(fmt.Errorf("%w: (%s) missing permission: %s", apperrors.ErrForbidden, i.Permissions, permission))
 		}
 
 		return handler(srv, ss)
@@ -145,8 +145,54 @@ func GrantAccessForUnaryRequest(permission identity.Permission) grpc.UnaryServer
 		if !ok {
 			return nil, apperrors.Wrap(fmt.Errorf("%w: missing identity", apperrors.ErrUnauthorized))
 		}
-		if !i.Permission.Has(permission) {
-			return nil, apperrors.Wrap(fmt.Errorf("%w: (%d) missing permission: %d", apperrors.ErrForbidden, i.Permission, permission))
+		if !i.Permissions.Has(permission) {
+			return nil, apperrors.Wrap(fmt.Errorf("%w: (%s) missing permission: %s", apperrors.ErrForbidden, i.Permissions, permission))
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// GrantAccessForRoleStreamRequest returns error if Identity not set within context or user does not have any of required roles
+//
+// 	https://godoc.org/google.golang.org/grpc#StreamInterceptor
+//
+// opts := []grpc.ServerOption{
+// 	grpc.StreamInterceptor(GrantAccessForRoleStreamRequest(identity.RoleAdmin)),
+// }
+// s := grpc.NewServer(opts...)
+// pb.RegisterGreeterServer(s, &server{})
+func GrantAccessForRoleStreamRequest(role identity.Role) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		i, ok := identity.FromContext(ss.Context())
+		if !ok {
+			return apperrors.Wrap(fmt.Errorf("%w: missing identity", apperrors.ErrUnauthorized))
+		}
+		if !i.Role.Has(role) {
+			return apperrors.Wrap(fmt.Errorf("%w: (%s) missing role: %s", apperrors.ErrForbidden, i.Role, role))
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// GrantAccessForRoleUnaryRequest returns error if Identity not set within context or user does not have any of required roles
+//
+// 	https://godoc.org/google.golang.org/grpc#UnaryInterceptor
+//
+// opts := []grpc.ServerOption{
+// 	grpc.UnaryInterceptor(GrantAccessForRoleUnaryRequest(identity.RoleAdmin)),
+// }
+// s := grpc.NewServer(opts...)
+// pb.RegisterGreeterServer(s, &server{})
+func GrantAccessForRoleUnaryRequest(role identity.Role) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		i, ok := identity.FromContext(ctx)
+		if !ok {
+			return nil, apperrors.Wrap(fmt.Errorf("%w: missing identity", apperrors.ErrUnauthorized))
+		}
+		if !i.Role.Has(role) {
+			return nil, apperrors.Wrap(fmt.Errorf("%w: (%s) missing role: %s", apperrors.ErrForbidden, i.Role, role))
 		}
 
 		return handler(ctx, req)
@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	grpcerrors "github.com/vardius/go-api-boilerplate/pkg/grpc/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/idempotency"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/logger"
+)
+
+// IdempotencyKeyMetadata is a request metadata key holding client generated idempotency key
+const IdempotencyKeyMetadata = "idempotency-key"
+
+// IdempotentUnaryRequest replays response of the first request sent with the same idempotency-key metadata
+// returns FailedPrecondition if key was used for different request
+// or Aborted if the first request is still being handled.
+// Errors with DeadlineExceeded, Unavailable, Internal or Unknown codes are not stored so request can be retried
+//
+// 	https://godoc.org/google.golang.org/grpc#UnaryInterceptor
+//
+// opts := []grpc.ServerOption{
+// 	grpc.UnaryInterceptor(IdempotentUnaryRequest(store, 24*time.Hour)),
+// }
+// s := grpc.NewServer(opts...)
+// pb.RegisterGreeterServer(s, &server{})
+func IdempotentUnaryRequest(store idempotency.Store, ttl time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return handler(ctx, req)
+		}
+		values := md.Get(IdempotencyKeyMetadata)
+		if len(values) == 0 || values[0] == "" {
+			return handler(ctx, req)
+		}
+		key := values[0]
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		body, err := proto.Marshal(msg)
+		if err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+
+		// keys are scoped to identity so clients can not replay each other's responses
+		if i, ok := identity.FromContext(ctx); ok {
+			key = fmt.Sprintf("%s:%s", i.UserID, key)
+		}
+
+		now := time.Now()
+		record := &idempotency.Record{
+			Key:         key,
+			Fingerprint: idempotency.Fingerprint([]byte(info.FullMethod), body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		existing, err := store.Lock(ctx, record)
+		if err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != record.Fingerprint:
+				return nil, apperrors.Wrap(fmt.Errorf("%w: idempotency key was already used for a different request", apperrors.ErrUnprocessable))
+			case !existing.Completed:
+				return nil, apperrors.Wrap(fmt.Errorf("%w: request with this idempotency key is still being processed", apperrors.ErrConflict))
+			default:
+				return replay(existing)
+			}
+		}
+
+		// outcome is stored even if client disconnected, that is when it is going to retry
+		storeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		resp, handlerErr := handler(ctx, req)
+
+		if err := completeRecord(record, resp, handlerErr); err != nil {
+			logger.Error(ctx, fmt.Sprintf("[gRPC|Server] Idempotency record failed: %v", err))
+		}
+
+		if record.Completed {
+			if err := store.Save(storeCtx, record); err != nil {
+				logger.Error(ctx, fmt.Sprintf("[gRPC|Server] Idempotency key save failed: %v", err))
+			}
+		} else if err := store.Delete(storeCtx, record.Key); err != nil {
+			logger.Error(ctx, fmt.Sprintf("[gRPC|Server] Idempotency key release failed: %v", err))
+		}
+
+		return resp, handlerErr
+	}
+}
+
+// completeRecord stores handler outcome within record,
+// leaves record incomplete if outcome should not be replayed
+func completeRecord(record *idempotency.Record, resp interface{}, handlerErr error) error {
+	if handlerErr != nil {
+		st := status.Convert(grpcerrors.NewGRPCError(handlerErr))
+		if s, ok := status.FromError(handlerErr); ok {
+			st = s
+		}
+
+		switch st.Code() {
+		case codes.DeadlineExceeded, codes.Unavailable, codes.Internal, codes.Unknown, codes.Canceled:
+			return nil
+		}
+
+		response, err := proto.Marshal(st.Proto())
+		if err != nil {
+			return err
+		}
+
+		record.Completed = true
+		record.StatusCode = int(st.Code())
+		record.Response = response
+
+		return nil
+	}
+
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return fmt.Errorf("response %T is not a proto message", resp)
+	}
+	packed, err := ptypes.MarshalAny(msg)
+	if err != nil {
+		return err
+	}
+	response, err := proto.Marshal(packed)
+	if err != nil {
+		return err
+	}
+
+	record.Completed = true
+	record.StatusCode = int(codes.OK)
+	record.Response = response
+
+	return nil
+}
+
+// replay returns stored response or error
+func replay(record *idempotency.Record) (interface{}, error) {
+	if codes.Code(record.StatusCode) != codes.OK {
+		var st spb.Status
+		if err := proto.Unmarshal(record.Response, &st); err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+
+		return nil, status.ErrorProto(&st)
+	}
+
+	var packed any.Any
+	if err := proto.Unmarshal(record.Response, &packed); err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+
+	var msg ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(&packed, &msg); err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+
+	return msg.Message, nil
+}
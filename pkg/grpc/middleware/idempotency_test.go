@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	memoryidempotency "github.com/vardius/go-api-boilerplate/pkg/idempotency/memory"
+)
+
+func TestIdempotentUnaryRequest(t *testing.T) {
+	var calls int
+	interceptor := IdempotentUnaryRequest(memoryidempotency.New(), time.Minute)
+	info := &grpc.UnaryServerInfo{FullMethod: "/service/Dispatch"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return &empty.Empty{}, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(IdempotencyKeyMetadata, "key"))
+
+	if _, err := interceptor(ctx, &wrappers.StringValue{Value: "a"}, info, handler); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := interceptor(ctx, &wrappers.StringValue{Value: "a"}, info, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := resp.(*empty.Empty); !ok {
+		t.Errorf("replayed response = %T, want %T", resp, &empty.Empty{})
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+
+	if _, err := interceptor(ctx, &wrappers.StringValue{Value: "b"}, info, handler); !errors.Is(err, apperrors.ErrUnprocessable) {
+		t.Errorf("conflicting request error = %v, want %v", err, apperrors.ErrUnprocessable)
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(IdempotencyKeyMetadata, "invalid"))
+	failing := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return nil, apperrors.Wrap(apperrors.ErrInvalid)
+	}
+	if _, err := interceptor(ctx, &wrappers.StringValue{Value: "a"}, info, failing); !errors.Is(err, apperrors.ErrInvalid) {
+		t.Fatalf("error = %v, want %v", err, apperrors.ErrInvalid)
+	}
+	if _, err := interceptor(ctx, &wrappers.StringValue{Value: "a"}, info, failing); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("replayed error = %v, want %s", err, codes.InvalidArgument)
+	}
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+}
@@ -10,9 +10,10 @@ import (
 )
 
 type HttpError struct {
-	Code      int    `json:"code"`
-	Message   string `json:"message"`
-	RequestID string `json:"request_id,omitempty"`
+	Code      int                    `json:"code"`
+	Message   string                 `json:"message"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Fields    []apperrors.FieldError `json:"fields,omitempty"`
 }
 
 func NewHttpError(ctx context.Context, err error) *HttpError {
@@ -31,6 +32,12 @@ func NewHttpError(ctx context.Context, err error) *HttpError {
 		code = http.StatusRequestTimeout
 	case errors.Is(err, apperrors.ErrTemporaryDisabled):
 		code = http.StatusServiceUnavailable
+	case errors.Is(err, apperrors.ErrConflict):
+		code = http.StatusConflict
+	case errors.Is(err, apperrors.ErrUnprocessable):
+		code = http.StatusUnprocessableEntity
+	case errors.Is(err, apperrors.ErrTooManyRequests):
+		code = http.StatusTooManyRequests
 	case errors.Is(err, apperrors.ErrInternal):
 		code = http.StatusInternalServerError
 	}
@@ -40,6 +47,11 @@ func NewHttpError(ctx context.Context, err error) *HttpError {
 		Message: http.StatusText(code),
 	}
 
+	var validationErr *apperrors.ValidationError
+	if errors.As(err, &validationErr) {
+		httpError.Fields = validationErr.Fields
+	}
+
 	if m, ok := mtd.FromContext(ctx); ok {
 		httpError.RequestID = m.TraceID
 		m.Err = err
@@ -20,8 +20,31 @@ func GrantAccessFor(permission identity.Permission) func(next http.Handler) http
 				json.MustJSONError(r.Context(), w, apperrors.Wrap(fmt.Errorf("%w: request is missing identity", apperrors.ErrUnauthorized)))
 				return
 			}
-			if !i.Permission.Has(permission) {
-				json.MustJSONError(r.Context(), w, apperrors.Wrap(fmt.Errorf("%w: (%d) missing permission %d", apperrors.ErrForbidden, i.Permission, permission)))
+			if !i.Permissions.Has(permission) {
+				json.MustJSONError(r.Context(), w, apperrors.Wrap(fmt.Errorf("%w: (%s) missing permission %s", apperrors.ErrForbidden, i.Permissions, permission)))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// GrantAccessForRole returns Status Unauthorized if
+// Identity is not set within request's context
+// or user does not have any of required roles
+func GrantAccessForRole(role identity.Role) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			i, ok := identity.FromContext(r.Context())
+			if !ok {
+				json.MustJSONError(r.Context(), w, apperrors.Wrap(fmt.Errorf("%w: request is missing identity", apperrors.ErrUnauthorized)))
+				return
+			}
+			if !i.Role.Has(role) {
+				json.MustJSONError(r.Context(), w, apperrors.Wrap(fmt.Errorf("%w: (%s) missing role %s", apperrors.ErrForbidden, i.Role, role)))
 				return
 			}
 
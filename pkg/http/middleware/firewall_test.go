@@ -23,8 +23,8 @@ func TestDoNotGrantAccessFor(t *testing.T) {
 	}
 
 	i := identity.Identity{
-		UserID:     uuid.New(),
-		Permission: identity.PermissionUserWrite,
+		UserID:      uuid.New(),
+		Permissions: identity.NewPermissions(identity.PermissionUserWrite),
 	}
 	ctx := identity.ContextWithIdentity(req.Context(), &i)
 
@@ -45,8 +45,8 @@ func TestGrantAccessFor(t *testing.T) {
 	}
 
 	i := identity.Identity{
-		UserID:     uuid.New(),
-		Permission: identity.PermissionUserRead,
+		UserID:      uuid.New(),
+		Permissions: identity.NewPermissions(identity.PermissionUserRead),
 	}
 	ctx := identity.ContextWithIdentity(req.Context(), &i)
 
@@ -56,3 +56,36 @@ func TestGrantAccessFor(t *testing.T) {
 		t.Error("Should get access to handler")
 	}
 }
+
+func TestGrantAccessForRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   identity.Role
+		served bool
+	}{
+		{"missing role", identity.RoleUser, false},
+		{"required role", identity.RoleUser.Add(identity.RoleAdmin), true},
+		{"any of required roles", identity.RoleSuperAdmin, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			served := false
+			handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+				served = true
+			})
+			h := GrantAccessForRole(identity.RoleAdmin | identity.RoleSuperAdmin)(handler)
+
+			req, err := http.NewRequest("GET", "/x", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := identity.ContextWithIdentity(req.Context(), &identity.Identity{UserID: uuid.New(), Role: tt.role})
+
+			h.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+
+			if served != tt.served {
+				t.Errorf("served = %v, want %v", served, tt.served)
+			}
+		})
+	}
+}
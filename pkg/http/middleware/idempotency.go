@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/http/response/json"
+	"github.com/vardius/go-api-boilerplate/pkg/idempotency"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/logger"
+)
+
+const (
+	// IdempotencyKeyHeader is a request header holding client generated idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from idempotency key store
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// idempotentReplayHeaders are response headers stored along with response and replayed,
+// Location allows retried asynchronous request to poll its execution
+var idempotentReplayHeaders = []string{"Content-Type", "Location", "Retry-After"}
+
+// idempotencyRecorder captures response so it can be replayed
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rw *idempotencyRecorder) WriteHeader(statusCode int) {
+	if rw.wroteHeader {
+		return
+	}
+
+	rw.statusCode = statusCode
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rw *idempotencyRecorder) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.body.Write(b)
+
+	return rw.ResponseWriter.Write(b)
+}
+
+func (rw *idempotencyRecorder) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Idempotent replays response of the first request sent with the same Idempotency-Key header
+// returns Status Unprocessable Entity if key was used for different request
+// or Status Conflict if the first request is still being handled.
+// Responses with status 408 or 5xx are not stored so request can be retried
+func Idempotent(store idempotency.Store, ttl time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var body []byte
+			if r.Body != nil {
+				var err error
+				body, err = ioutil.ReadAll(r.Body)
+				if err != nil {
+					json.MustJSONError(r.Context(), w, apperrors.Wrap(err))
+					return
+				}
+				r.Body.Close()
+				r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			}
+
+			// keys are scoped to identity so clients can not replay each other's responses
+			if i, ok := identity.FromContext(r.Context()); ok {
+				key = fmt.Sprintf("%s:%s", i.UserID, key)
+			}
+
+			now := time.Now()
+			record := &idempotency.Record{
+				Key:         key,
+				Fingerprint: idempotency.Fingerprint([]byte(r.Method), []byte(r.URL.Path), body),
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(ttl),
+			}
+
+			existing, err := store.Lock(r.Context(), record)
+			if err != nil {
+				json.MustJSONError(r.Context(), w, apperrors.Wrap(err))
+				return
+			}
+			if existing != nil {
+				switch {
+				case existing.Fingerprint != record.Fingerprint:
+					json.MustJSONError(r.Context(), w, apperrors.Wrap(fmt.Errorf("%w: idempotency key was already used for a different request", apperrors.ErrUnprocessable)))
+				case !existing.Completed:
+					json.MustJSONError(r.Context(), w, apperrors.Wrap(fmt.Errorf("%w: request with this idempotency key is still being processed", apperrors.ErrConflict)))
+				default:
+					w.Header().Set("Content-Type", "application/json")
+					for key, values := range existing.Header {
+						w.Header()[key] = values
+					}
+					w.Header().Set(IdempotentReplayedHeader, "true")
+					w.WriteHeader(existing.StatusCode)
+					if _, err := w.Write(existing.Response); err != nil {
+						logger.Error(r.Context(), fmt.Sprintf("[HTTP] Idempotent replay failed: %v", err))
+					}
+				}
+				return
+			}
+
+			// outcome is stored even if client disconnected, that is when it is going to retry
+			storeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			rw := &idempotencyRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+
+			defer func() {
+				if rec := recover(); rec != nil {
+					if err := store.Delete(storeCtx, record.Key); err != nil {
+						logger.Error(r.Context(), fmt.Sprintf("[HTTP] Idempotency key release failed: %v", err))
+					}
+					panic(rec)
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+
+			if rw.statusCode == http.StatusRequestTimeout || rw.statusCode >= http.StatusInternalServerError {
+				if err := store.Delete(storeCtx, record.Key); err != nil {
+					logger.Error(r.Context(), fmt.Sprintf("[HTTP] Idempotency key release failed: %v", err))
+				}
+				return
+			}
+
+			record.Completed = true
+			record.StatusCode = rw.statusCode
+			record.Header = make(http.Header)
+			for _, key := range idempotentReplayHeaders {
+				if values := rw.Header().Values(key); len(values) > 0 {
+					record.Header[key] = values
+				}
+			}
+			record.Response = rw.body.Bytes()
+
+			if err := store.Save(storeCtx, record); err != nil {
+				logger.Error(r.Context(), fmt.Sprintf("[HTTP] Idempotency key save failed: %v", err))
+			}
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -66,6 +66,9 @@ func WithMetadata() gorouter.MiddlewareFunc {
 				if ip, err := request.IpAddress(r); err == nil {
 					mtd.IPAddress = ip
 				}
+				if correlationID := r.Header.Get("X-Correlation-ID"); correlationID != "" {
+					mtd.CorrelationID = correlationID
+				}
 			}
 
 			ctx := md.ContextWithMetadata(r.Context(), mtd)
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/vardius/go-api-boilerplate/pkg/container"
+	memoryidempotency "github.com/vardius/go-api-boilerplate/pkg/idempotency/memory"
 	md "github.com/vardius/go-api-boilerplate/pkg/metadata"
 	"github.com/vardius/gocontainer"
 )
@@ -210,3 +211,47 @@ func TestWithContainer(t *testing.T) {
 
 	h.ServeHTTP(w, req)
 }
+
+func TestIdempotent(t *testing.T) {
+	var calls int
+	m := Idempotent(memoryidempotency.New(), time.Minute)
+	handler := m(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Location", "/executions/1")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"calls":`, calls, `}`)
+	}))
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/dispatch", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(IdempotencyKeyHeader, "key")
+
+		handler.ServeHTTP(w, req)
+
+		return w
+	}
+
+	first := serve(`{"email":"test@example.com"}`)
+	replay := serve(`{"email":"test@example.com"}`)
+
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+	if replay.Code != http.StatusCreated || replay.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", replay.Code, replay.Body, first.Code, first.Body)
+	}
+	if replay.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Error("replay is missing replayed header")
+	}
+	if replay.Header().Get("Location") != "/executions/1" {
+		t.Errorf("replay Location = %q, want %q", replay.Header().Get("Location"), "/executions/1")
+	}
+
+	if conflict := serve(`{"email":"other@example.com"}`); conflict.Code != http.StatusUnprocessableEntity {
+		t.Errorf("conflicting payload status = %d, want %d", conflict.Code, http.StatusUnprocessableEntity)
+	}
+}
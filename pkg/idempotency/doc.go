@@ -0,0 +1,4 @@
+/*
+Package idempotency provides idempotency key store interfaces
+*/
+package idempotency
@@ -0,0 +1,60 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	baseidempotency "github.com/vardius/go-api-boilerplate/pkg/idempotency"
+)
+
+type store struct {
+	mtx     sync.Mutex
+	records map[string]baseidempotency.Record
+}
+
+// New creates in memory idempotency key store
+func New() baseidempotency.Store {
+	return &store{
+		records: make(map[string]baseidempotency.Record),
+	}
+}
+
+func (s *store) Lock(ctx context.Context, record *baseidempotency.Record) (*baseidempotency.Record, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := time.Now()
+	if existing, ok := s.records[record.Key]; ok && existing.ExpiresAt.After(now) {
+		return &existing, nil
+	}
+
+	// expired keys are purged the same way sql and mongo stores drop them, otherwise store would grow without bound
+	for key, existing := range s.records {
+		if !existing.ExpiresAt.After(now) {
+			delete(s.records, key)
+		}
+	}
+
+	s.records[record.Key] = *record
+
+	return nil, nil
+}
+
+func (s *store) Save(ctx context.Context, record *baseidempotency.Record) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.records[record.Key] = *record
+
+	return nil
+}
+
+func (s *store) Delete(ctx context.Context, key string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delete(s.records, key)
+
+	return nil
+}
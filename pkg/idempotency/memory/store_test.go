@@ -0,0 +1,65 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	baseidempotency "github.com/vardius/go-api-boilerplate/pkg/idempotency"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	record := &baseidempotency.Record{Key: "key", Fingerprint: "a", ExpiresAt: time.Now().Add(time.Minute)}
+	if existing, err := s.Lock(ctx, record); err != nil || existing != nil {
+		t.Fatalf("Lock() = %v, %v", existing, err)
+	}
+
+	record.Completed = true
+	record.StatusCode = 201
+	if err := s.Save(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+
+	existing, err := s.Lock(ctx, &baseidempotency.Record{Key: "key", Fingerprint: "b", ExpiresAt: time.Now().Add(time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if existing == nil || !existing.Completed || existing.Fingerprint != "a" || existing.StatusCode != 201 {
+		t.Errorf("Lock() = %v, want stored record", existing)
+	}
+
+	if err := s.Delete(ctx, "key"); err != nil {
+		t.Fatal(err)
+	}
+	if existing, err := s.Lock(ctx, &baseidempotency.Record{Key: "key", ExpiresAt: time.Now().Add(time.Minute)}); err != nil || existing != nil {
+		t.Errorf("Lock() = %v, %v, want released key", existing, err)
+	}
+
+	if err := s.Save(ctx, &baseidempotency.Record{Key: "expired", ExpiresAt: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	if existing, err := s.Lock(ctx, &baseidempotency.Record{Key: "expired", ExpiresAt: time.Now().Add(time.Minute)}); err != nil || existing != nil {
+		t.Errorf("Lock() = %v, %v, want expired key to be reused", existing, err)
+	}
+}
+
+func TestStorePurgesExpiredKeys(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	for _, key := range []string{"a", "b"} {
+		if err := s.Save(ctx, &baseidempotency.Record{Key: key, ExpiresAt: time.Now().Add(-time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if existing, err := s.Lock(ctx, &baseidempotency.Record{Key: "c", ExpiresAt: time.Now().Add(time.Minute)}); err != nil || existing != nil {
+		t.Fatalf("Lock() = %v, %v", existing, err)
+	}
+
+	if got := len(s.(*store).records); got != 1 {
+		t.Errorf("store holds %d records, want 1", got)
+	}
+}
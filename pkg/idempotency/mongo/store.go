@@ -0,0 +1,139 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	baseidempotency "github.com/vardius/go-api-boilerplate/pkg/idempotency"
+)
+
+// lockAttempts limits retries when key is released while being locked
+const lockAttempts = 3
+
+type record struct {
+	Key         string              `bson:"idempotency_key"`
+	Fingerprint string              `bson:"fingerprint"`
+	Completed   bool                `bson:"completed"`
+	StatusCode  int                 `bson:"status_code"`
+	Header      map[string][]string `bson:"header,omitempty"`
+	Response    []byte              `bson:"response,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at"`
+	ExpiresAt   time.Time           `bson:"expires_at"`
+}
+
+type store struct {
+	collection *mongo.Collection
+}
+
+// New creates mongo idempotency key store
+func New(ctx context.Context, collectionName string, mongoDB *mongo.Database) (baseidempotency.Store, error) {
+	if collectionName == "" {
+		collectionName = "idempotency_keys"
+	}
+
+	collection := mongoDB.Collection(collectionName)
+
+	if _, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "idempotency_key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(1),
+		},
+	}); err != nil {
+		return nil, apperrors.Wrap(fmt.Errorf("failed to create indexes: %w", err))
+	}
+
+	return &store{collection: collection}, nil
+}
+
+func (s *store) Lock(ctx context.Context, r *baseidempotency.Record) (*baseidempotency.Record, error) {
+	doc := fromRecord(r)
+
+	for i := 0; i < lockAttempts; i++ {
+		_, err := s.collection.InsertOne(ctx, doc)
+		if err == nil {
+			return nil, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, apperrors.Wrap(err)
+		}
+
+		// take over expired key, ttl index removes expired documents with delay
+		result, err := s.collection.ReplaceOne(ctx, bson.M{
+			"idempotency_key": r.Key,
+			"expires_at":      bson.M{"$lt": time.Now().UTC()},
+		}, doc)
+		if err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+		if result.ModifiedCount == 1 {
+			return nil, nil
+		}
+
+		var existing record
+		if err := s.collection.FindOne(ctx, bson.M{"idempotency_key": r.Key}).Decode(&existing); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue
+			}
+
+			return nil, apperrors.Wrap(err)
+		}
+
+		return existing.toRecord(), nil
+	}
+
+	return nil, apperrors.Wrap(fmt.Errorf("%w: could not lock idempotency key %s", apperrors.ErrInternal, r.Key))
+}
+
+func (s *store) Save(ctx context.Context, r *baseidempotency.Record) error {
+	opts := options.Replace().SetUpsert(true)
+	if _, err := s.collection.ReplaceOne(ctx, bson.M{"idempotency_key": r.Key}, fromRecord(r), opts); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *store) Delete(ctx context.Context, key string) error {
+	if _, err := s.collection.DeleteOne(ctx, bson.M{"idempotency_key": key}); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func fromRecord(r *baseidempotency.Record) record {
+	return record{
+		Key:         r.Key,
+		Fingerprint: r.Fingerprint,
+		Completed:   r.Completed,
+		StatusCode:  r.StatusCode,
+		Header:      r.Header,
+		Response:    r.Response,
+		CreatedAt:   r.CreatedAt.UTC(),
+		ExpiresAt:   r.ExpiresAt.UTC(),
+	}
+}
+
+func (r record) toRecord() *baseidempotency.Record {
+	return &baseidempotency.Record{
+		Key:         r.Key,
+		Fingerprint: r.Fingerprint,
+		Completed:   r.Completed,
+		StatusCode:  r.StatusCode,
+		Header:      r.Header,
+		Response:    r.Response,
+		CreatedAt:   r.CreatedAt,
+		ExpiresAt:   r.ExpiresAt,
+	}
+}
@@ -0,0 +1,160 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	baseidempotency "github.com/vardius/go-api-boilerplate/pkg/idempotency"
+)
+
+const createTableSQLFormat = `
+CREATE TABLE IF NOT EXISTS %s
+(
+    idempotency_key VARCHAR(255) NOT NULL,
+    fingerprint     CHAR(64)     NOT NULL,
+    completed       TINYINT(1)   NOT NULL DEFAULT 0,
+    status_code     INT          NOT NULL DEFAULT 0,
+    header          BLOB,
+    response        BLOB,
+    created_at      DATETIME     NOT NULL,
+    expires_at      DATETIME     NOT NULL,
+    PRIMARY KEY (idempotency_key),
+    INDEX i_expires_at (expires_at)
+)
+    ENGINE = InnoDB
+    DEFAULT CHARSET = utf8
+    COLLATE = utf8_bin;
+`
+
+// lockAttempts limits retries when key is released while being locked
+const lockAttempts = 3
+
+type store struct {
+	tableName string
+	db        *sql.DB
+}
+
+// New creates mysql idempotency key store
+func New(ctx context.Context, tableName string, db *sql.DB) (baseidempotency.Store, error) {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(createTableSQLFormat, tableName)); err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+
+	return &store{tableName: tableName, db: db}, nil
+}
+
+func (s *store) Lock(ctx context.Context, record *baseidempotency.Record) (*baseidempotency.Record, error) {
+	header, err := encodeHeader(record.Header)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+
+	for i := 0; i < lockAttempts; i++ {
+		result, err := s.db.ExecContext(ctx,
+			"INSERT IGNORE INTO "+s.tableName+" (idempotency_key, fingerprint, completed, status_code, header, response, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			record.Key, record.Fingerprint, record.Completed, record.StatusCode, header, record.Response, record.CreatedAt.UTC(), record.ExpiresAt.UTC(),
+		)
+		if err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return nil, apperrors.Wrap(err)
+		} else if affected == 1 {
+			return nil, nil
+		}
+
+		// take over expired key
+		result, err = s.db.ExecContext(ctx,
+			"UPDATE "+s.tableName+" SET fingerprint=?, completed=?, status_code=?, header=?, response=?, created_at=?, expires_at=? WHERE idempotency_key=? AND expires_at<?",
+			record.Fingerprint, record.Completed, record.StatusCode, header, record.Response, record.CreatedAt.UTC(), record.ExpiresAt.UTC(), record.Key, time.Now().UTC(),
+		)
+		if err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return nil, apperrors.Wrap(err)
+		} else if affected == 1 {
+			return nil, nil
+		}
+
+		existing, err := s.get(ctx, record.Key)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+
+		return existing, nil
+	}
+
+	return nil, apperrors.Wrap(fmt.Errorf("%w: could not lock idempotency key %s", apperrors.ErrInternal, record.Key))
+}
+
+func (s *store) Save(ctx context.Context, record *baseidempotency.Record) error {
+	header, err := encodeHeader(record.Header)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		"REPLACE INTO "+s.tableName+" (idempotency_key, fingerprint, completed, status_code, header, response, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		record.Key, record.Fingerprint, record.Completed, record.StatusCode, header, record.Response, record.CreatedAt.UTC(), record.ExpiresAt.UTC(),
+	); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *store) Delete(ctx context.Context, key string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM "+s.tableName+" WHERE idempotency_key=?", key); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *store) get(ctx context.Context, key string) (*baseidempotency.Record, error) {
+	row := s.db.QueryRowContext(ctx,
+		"SELECT idempotency_key, fingerprint, completed, status_code, header, response, created_at, expires_at FROM "+s.tableName+" WHERE idempotency_key=? LIMIT 1",
+		key,
+	)
+
+	var record baseidempotency.Record
+	var header []byte
+	if err := row.Scan(
+		&record.Key,
+		&record.Fingerprint,
+		&record.Completed,
+		&record.StatusCode,
+		&header,
+		&record.Response,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if len(header) > 0 {
+		if err := json.Unmarshal(header, &record.Header); err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+	}
+
+	return &record, nil
+}
+
+func encodeHeader(header http.Header) ([]byte, error) {
+	if len(header) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(header)
+}
@@ -0,0 +1,43 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// Record holds outcome of request handled with idempotency key
+type Record struct {
+	Key         string      `json:"key"`
+	Fingerprint string      `json:"fingerprint"`
+	Completed   bool        `json:"completed"`
+	StatusCode  int         `json:"status_code"`
+	Header      http.Header `json:"header,omitempty"`
+	Response    []byte      `json:"response,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	ExpiresAt   time.Time   `json:"expires_at"`
+}
+
+// Store allows to reserve idempotency keys and store requests outcome
+type Store interface {
+	// Lock reserves record key until record expires,
+	// returns previously stored record if key is already in use
+	Lock(ctx context.Context, record *Record) (*Record, error)
+	// Save stores completed record
+	Save(ctx context.Context, record *Record) error
+	// Delete releases key so request can be retried
+	Delete(ctx context.Context, key string) error
+}
+
+// Fingerprint returns hash identifying request
+func Fingerprint(parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
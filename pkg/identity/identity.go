@@ -9,30 +9,23 @@ import (
 
 // Identity data to be encode in auth token
 type Identity struct {
-	Token        string     `json:"token"`
-	Permission   Permission `json:"permission"`
-	UserID       uuid.UUID  `json:"user_id"`
-	ClientID     uuid.UUID  `json:"client_id,omitempty"`
-	ClientDomain string     `json:"client_domain,omitempty"`
+	Token        string      `json:"token"`
+	Permissions  Permissions `json:"permission"`
+	Role         Role        `json:"role,omitempty"`
+	UserID       uuid.UUID   `json:"user_id"`
+	ClientID     uuid.UUID   `json:"client_id,omitempty"`
+	ClientDomain string      `json:"client_domain,omitempty"`
 }
 
-// Flag type
-type Permission uint8
-
-// Add permission
-func (p Permission) Add(flag Permission) Permission { return p | flag }
-
-// Remove permission
-func (p Permission) Remove(flag Permission) Permission { return p &^ flag }
+// Service returns identity of the service itself, used to dispatch internal commands such as scheduled ones,
+// it carries no token so it can not be used to call other services on behalf of any user
+func Service() *Identity {
+	return &Identity{Role: RoleService}
+}
 
-// Has permission
-func (p Permission) Has(flag Permission) bool { return p&flag != 0 }
+// WithoutToken returns copy of identity safe to persist
+func (i Identity) WithoutToken() *Identity {
+	i.Token = ""
 
-// Execution context flags
-const (
-	PermissionUserRead Permission = 1 << iota
-	PermissionUserWrite
-	PermissionClientWrite
-	PermissionClientRead
-	PermissionTokenRead
-)
+	return &i
+}
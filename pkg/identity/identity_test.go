@@ -12,8 +12,8 @@ func TestNew(t *testing.T) {
 	token := "token"
 
 	identity := &Identity{
-		Token:      token,
-		Permission: PermissionUserRead,
+		Token:       token,
+		Permissions: NewPermissions(PermissionUserRead),
 
 		UserID:   userID,
 		ClientID: clientID,
@@ -28,7 +28,22 @@ func TestNew(t *testing.T) {
 	if identity.Token != token {
 		t.Errorf("Identity Token does not match, given: %s | expected %s", identity.Token, token)
 	}
-	if !identity.Permission.Has(PermissionUserRead) {
-		t.Errorf("Identity permissions do not match, given: %d | expected %d", identity.Permission, PermissionUserRead)
+	if !identity.Permissions.Has(PermissionUserRead) {
+		t.Errorf("Identity permissions do not match, given: %s | expected %s", identity.Permissions, PermissionUserRead)
+	}
+}
+
+func TestIdentity_WithoutToken(t *testing.T) {
+	i := Identity{Token: "token", UserID: uuid.New(), Role: RoleUser}
+
+	got := i.WithoutToken()
+	if got.Token != "" {
+		t.Error("expected token to be removed")
+	}
+	if got.UserID != i.UserID || got.Role != i.Role {
+		t.Errorf("WithoutToken() = %v, want copy of %v", got, i)
+	}
+	if i.Token != "token" {
+		t.Error("expected original identity to be left intact")
 	}
 }
@@ -0,0 +1,177 @@
+package identity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Permission namespaced permission name in format namespace:action, e.g. user:read
+type Permission string
+
+// Permissions used by services
+const (
+	PermissionUserRead    Permission = "user:read"
+	PermissionUserWrite   Permission = "user:write"
+	PermissionClientWrite Permission = "client:write"
+	PermissionClientRead  Permission = "client:read"
+	PermissionTokenRead   Permission = "token:read"
+)
+
+// legacyPermissionFlags permissions indexed by bit they used to be encoded with,
+// tokens issued before permissions were namespaced carry them as 8-bit mask
+var legacyPermissionFlags = []Permission{
+	PermissionUserRead,
+	PermissionUserWrite,
+	PermissionClientWrite,
+	PermissionClientRead,
+	PermissionTokenRead,
+}
+
+// legacyPermissionNames names permissions were configured with before they were namespaced
+var legacyPermissionNames = map[string]Permission{
+	"user_read":    PermissionUserRead,
+	"user_write":   PermissionUserWrite,
+	"client_write": PermissionClientWrite,
+	"client_read":  PermissionClientRead,
+	"token_read":   PermissionTokenRead,
+}
+
+// Namespace returns part of permission before colon
+func (p Permission) Namespace() string {
+	if i := strings.IndexByte(string(p), ':'); i >= 0 {
+		return string(p[:i])
+	}
+
+	return ""
+}
+
+// ParsePermission parses permission name, legacy names (e.g. user_read) are accepted
+func ParsePermission(name string) (Permission, error) {
+	if p, ok := legacyPermissionNames[name]; ok {
+		return p, nil
+	}
+
+	parts := strings.Split(name, ":")
+	if len(parts) != 2 || !isPermissionPart(parts[0]) || !isPermissionPart(parts[1]) {
+		return "", fmt.Errorf("invalid permission %q, expected namespace:action", name)
+	}
+
+	return Permission(name), nil
+}
+
+func isPermissionPart(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' && r != '-' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Permissions sorted set of permissions, methods never modify receiver
+type Permissions []Permission
+
+// NewPermissions returns set of given permissions
+func NewPermissions(permissions ...Permission) Permissions {
+	return Permissions(nil).Add(permissions...)
+}
+
+// Add permissions
+func (p Permissions) Add(permissions ...Permission) Permissions {
+	set := make(Permissions, 0, len(p)+len(permissions))
+	set = append(set, p...)
+	for _, permission := range permissions {
+		if !set.Has(permission) {
+			set = append(set, permission)
+		}
+	}
+	sort.Slice(set, func(i, j int) bool { return set[i] < set[j] })
+
+	return set
+}
+
+// Remove permissions
+func (p Permissions) Remove(permissions ...Permission) Permissions {
+	removed := NewPermissions(permissions...)
+	set := make(Permissions, 0, len(p))
+	for _, permission := range p {
+		if !removed.Has(permission) {
+			set = append(set, permission)
+		}
+	}
+
+	return set
+}
+
+// Has permission
+func (p Permissions) Has(permission Permission) bool {
+	for _, granted := range p {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns permissions separated with comma
+func (p Permissions) String() string {
+	names := make([]string, len(p))
+	for i, permission := range p {
+		names[i] = string(permission)
+	}
+
+	return strings.Join(names, ",")
+}
+
+// UnmarshalJSON decodes list of permission names,
+// legacy 8-bit mask is accepted so tokens issued before permissions were namespaced remain valid
+func (p *Permissions) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	if bytes.Equal(data, []byte("null")) {
+		*p = nil
+		return nil
+	}
+
+	if len(data) > 0 && data[0] >= '0' && data[0] <= '9' {
+		var mask uint8
+		if err := json.Unmarshal(data, &mask); err != nil {
+			return err
+		}
+
+		var set Permissions
+		for bit, permission := range legacyPermissionFlags {
+			if mask&(1<<bit) != 0 {
+				set = set.Add(permission)
+			}
+		}
+		*p = set
+
+		return nil
+	}
+
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+
+	var set Permissions
+	for _, name := range names {
+		permission, err := ParsePermission(name)
+		if err != nil {
+			return err
+		}
+		set = set.Add(permission)
+	}
+	*p = set
+
+	return nil
+}
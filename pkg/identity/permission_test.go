@@ -0,0 +1,77 @@
+package identity
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPermissions(t *testing.T) {
+	p := NewPermissions(PermissionUserWrite, PermissionUserRead, PermissionUserWrite)
+	if got, want := p.String(), "user:read,user:write"; got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+
+	added := p.Add(PermissionClientRead)
+	if !added.Has(PermissionClientRead) || p.Has(PermissionClientRead) {
+		t.Errorf("Add() = %s, receiver %s", added, p)
+	}
+
+	removed := added.Remove(PermissionUserRead)
+	if removed.Has(PermissionUserRead) || !added.Has(PermissionUserRead) {
+		t.Errorf("Remove() = %s, receiver %s", removed, added)
+	}
+
+	if got := PermissionClientWrite.Namespace(); got != "client" {
+		t.Errorf("Namespace() = %s, want client", got)
+	}
+}
+
+func TestParsePermission(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Permission
+		wantErr bool
+	}{
+		{"user:read", PermissionUserRead, false},
+		{"invoice:approve", Permission("invoice:approve"), false},
+		{"client_write", PermissionClientWrite, false},
+		{"user", "", true},
+		{"user:", "", true},
+		{"User:Read", "", true},
+		{"user:read:all", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePermission(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePermission() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePermission() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentityPermissionsJSON(t *testing.T) {
+	data, err := json.Marshal(Identity{Permissions: NewPermissions(PermissionUserRead, PermissionClientWrite)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var i Identity
+	if err := json.Unmarshal(data, &i); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := i.Permissions.String(), "client:write,user:read"; got != want {
+		t.Errorf("Permissions = %s, want %s", got, want)
+	}
+
+	// claims of tokens issued with 8-bit permission mask
+	if err := json.Unmarshal([]byte(`{"permission":19}`), &i); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := i.Permissions.String(), "token:read,user:read,user:write"; got != want {
+		t.Errorf("legacy Permissions = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+package identity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Role flags, user can have many roles
+type Role uint8
+
+// Add role
+func (r Role) Add(flag Role) Role { return r | flag }
+
+// Remove role
+func (r Role) Remove(flag Role) Role { return r &^ flag }
+
+// Has any of roles
+func (r Role) Has(flag Role) bool { return r&flag != 0 }
+
+// Roles
+const (
+	RoleUser Role = 1 << iota
+	RoleAdmin
+	RoleSuperAdmin
+	// RoleService is carried only by identity of the service itself, it is never granted to users
+	RoleService
+)
+
+var roleNames = []struct {
+	role Role
+	name string
+}{
+	{RoleUser, "USER"},
+	{RoleAdmin, "ADMIN"},
+	{RoleSuperAdmin, "SUPER_ADMIN"},
+	{RoleService, "SERVICE"},
+}
+
+// String returns names of roles separated with |
+func (r Role) String() string {
+	var names []string
+	for _, n := range roleNames {
+		if r.Has(n.role) {
+			names = append(names, n.name)
+		}
+	}
+
+	return strings.Join(names, "|")
+}
+
+// MarshalText encodes roles as their names
+func (r Role) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText decodes roles from names separated with |
+func (r *Role) UnmarshalText(text []byte) error {
+	role, err := ParseRole(string(text))
+	if err != nil {
+		return err
+	}
+
+	*r = role
+
+	return nil
+}
+
+// ParseRole parses role names separated with |
+func ParseRole(s string) (Role, error) {
+	var r Role
+	if s == "" {
+		return r, nil
+	}
+
+	for _, name := range strings.Split(s, "|") {
+		role, ok := roleByName(strings.TrimSpace(name))
+		if !ok {
+			return 0, fmt.Errorf("unknown role %q", name)
+		}
+		r = r.Add(role)
+	}
+
+	return r, nil
+}
+
+func roleByName(name string) (Role, bool) {
+	for _, n := range roleNames {
+		if n.name == name {
+			return n.role, true
+		}
+	}
+
+	return 0, false
+}
+
+// RolePermissions maps roles to permissions granted to them
+type RolePermissions map[Role]Permissions
+
+// Permissions returns permissions granted to any of roles
+func (rp RolePermissions) Permissions(roles Role) Permissions {
+	var p Permissions
+	for role, permissions := range rp {
+		if roles.Has(role) {
+			p = p.Add(permissions...)
+		}
+	}
+
+	return p
+}
+
+// UnmarshalText decodes mapping in format ROLE:namespace:action,namespace:action|ROLE:namespace:action
+func (rp *RolePermissions) UnmarshalText(text []byte) error {
+	m := make(RolePermissions)
+
+	for _, entry := range strings.Split(string(text), "|") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid role permissions %q, expected ROLE:namespace:action,namespace:action", entry)
+		}
+
+		role, ok := roleByName(strings.TrimSpace(parts[0]))
+		if !ok {
+			return fmt.Errorf("unknown role %q", parts[0])
+		}
+
+		for _, name := range strings.Split(parts[1], ",") {
+			permission, err := ParsePermission(strings.TrimSpace(name))
+			if err != nil {
+				return err
+			}
+			m[role] = m[role].Add(permission)
+		}
+	}
+
+	*rp = m
+
+	return nil
+}
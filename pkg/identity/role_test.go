@@ -0,0 +1,74 @@
+package identity
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRole_String(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Role
+		want string
+	}{
+		{"none", 0, ""},
+		{"USER", RoleUser, "USER"},
+		{"SUPER_ADMIN", RoleSuperAdmin, "SUPER_ADMIN"},
+		{"SERVICE", RoleService, "SERVICE"},
+		{"many", RoleUser | RoleAdmin, "USER|ADMIN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+
+			got, err := ParseRole(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.r {
+				t.Errorf("ParseRole() = %v, want %v", got, tt.r)
+			}
+		})
+	}
+
+	if _, err := ParseRole("USER|ROOT"); err == nil {
+		t.Error("ParseRole() expected error for unknown role")
+	}
+}
+
+func TestIdentityRoleJSON(t *testing.T) {
+	data, err := json.Marshal(Identity{Role: RoleUser | RoleAdmin})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var i Identity
+	if err := json.Unmarshal(data, &i); err != nil {
+		t.Fatal(err)
+	}
+	if i.Role != RoleUser|RoleAdmin {
+		t.Errorf("Role = %v, want %v", i.Role, RoleUser|RoleAdmin)
+	}
+}
+
+func TestRolePermissions(t *testing.T) {
+	var rp RolePermissions
+	if err := rp.UnmarshalText([]byte("ADMIN:user:read,token_read|SUPER_ADMIN:client:write")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := rp.Permissions(RoleUser); len(got) != 0 {
+		t.Errorf("Permissions(USER) = %s, want none", got)
+	}
+	if got, want := rp.Permissions(RoleAdmin|RoleSuperAdmin), NewPermissions(PermissionUserRead, PermissionTokenRead, PermissionClientWrite); got.String() != want.String() {
+		t.Errorf("Permissions(ADMIN|SUPER_ADMIN) = %s, want %s", got, want)
+	}
+
+	for _, text := range []string{"ADMIN", "ROOT:user:read", "ADMIN:root", "ADMIN:user:read:all"} {
+		if err := rp.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) expected error", text)
+		}
+	}
+}
@@ -16,20 +16,24 @@ const metadataKey key = 1
 
 // Metadata represent state for each request.
 type Metadata struct {
-	Now        time.Time `json:"-"`
-	TraceID    string    `json:"trace_id,omitempty"`
-	IPAddress  net.IP    `json:"ip_address,omitempty"`
-	StatusCode int       `json:"http_status,omitempty"`
-	UserAgent  string    `json:"http_user_agent,omitempty"`
-	RemoteAddr string    `json:"http_remote_addr,omitempty"`
-	Referer    string    `json:"http_referer,omitempty"`
-	Err        error     `json:"-"`
+	Now           time.Time `json:"-"`
+	TraceID       string    `json:"trace_id,omitempty"`
+	CorrelationID string    `json:"correlation_id,omitempty"` // shared by all messages caused by the same request
+	IPAddress     net.IP    `json:"ip_address,omitempty"`
+	StatusCode    int       `json:"http_status,omitempty"`
+	UserAgent     string    `json:"http_user_agent,omitempty"`
+	RemoteAddr    string    `json:"http_remote_addr,omitempty"`
+	Referer       string    `json:"http_referer,omitempty"`
+	Err           error     `json:"-"`
 }
 
 func New() *Metadata {
+	traceID := uuid.New().String()
+
 	return &Metadata{
-		TraceID: uuid.New().String(),
-		Now:     time.Now(),
+		TraceID:       traceID,
+		CorrelationID: traceID,
+		Now:           time.Now(),
 	}
 }
 
@@ -0,0 +1,54 @@
+package saga
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+)
+
+var (
+	TimeoutName = (Timeout{}).GetName()
+	RetryName   = (Retry{}).GetName()
+)
+
+func init() {
+	if err := domain.RegisterCommandFactory(TimeoutName, func(payload []byte) (domain.Command, error) {
+		var c Timeout
+		err := json.Unmarshal(payload, &c)
+		return c, err
+	}); err != nil {
+		panic(err)
+	}
+	if err := domain.RegisterCommandFactory(RetryName, func(payload []byte) (domain.Command, error) {
+		var c Retry
+		err := json.Unmarshal(payload, &c)
+		return c, err
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// Timeout command compensates saga instance which did not complete in time
+type Timeout struct {
+	Saga          string `json:"saga" validate:"required"`
+	CorrelationID string `json:"correlation_id" validate:"required"`
+}
+
+// GetName returns command name
+func (c Timeout) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
+
+// Retry command redelivers event to saga instance after handler failed with retryable error
+type Retry struct {
+	Saga          string             `json:"saga" validate:"required"`
+	CorrelationID string             `json:"correlation_id" validate:"required"`
+	Event         *eventbus.Envelope `json:"event" validate:"required"`
+}
+
+// GetName returns command name
+func (c Retry) GetName() string {
+	return fmt.Sprintf("%T", c)
+}
@@ -0,0 +1,4 @@
+/*
+Package saga provides process managers coordinating long running workflows across services
+*/
+package saga
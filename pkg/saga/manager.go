@@ -0,0 +1,276 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/eventbus"
+	"github.com/vardius/go-api-boilerplate/pkg/executioncontext"
+	"github.com/vardius/go-api-boilerplate/pkg/logger"
+)
+
+// retryBackoff computes delay of event redelivery
+var retryBackoff = eventbus.Backoff{
+	Min:    time.Second,
+	Max:    5 * time.Minute,
+	Factor: 2,
+}
+
+// Manager dispatches events to registered sagas persisting state of their instances.
+// Timeouts and redeliveries are scheduled as commands handled by manager,
+// command scheduler has to dispatch them through the same command bus manager subscribes to.
+// Instance state is saved with optimistic locking, event handled concurrently for the same instance
+// fails with apperrors.ErrConflict
+type Manager struct {
+	commandBus commandbus.CommandBus
+	eventBus   eventbus.EventBus
+	scheduler  commandbus.Scheduler
+	store      Store
+
+	mtx         sync.RWMutex
+	definitions map[string]Definition
+}
+
+// New creates saga manager
+func New(commandBus commandbus.CommandBus, eventBus eventbus.EventBus, scheduler commandbus.Scheduler, store Store) *Manager {
+	return &Manager{
+		commandBus:  commandBus,
+		eventBus:    eventBus,
+		scheduler:   scheduler,
+		store:       store,
+		definitions: make(map[string]Definition),
+	}
+}
+
+// Register subscribes saga handlers to event bus,
+// only events published with executioncontext.LIVE flag are handled
+func (m *Manager) Register(ctx context.Context, d Definition) error {
+	if d.Name == "" || d.Correlate == nil || len(d.Handlers) == 0 {
+		return apperrors.New("invalid saga definition")
+	}
+
+	m.mtx.Lock()
+	if _, ok := m.definitions[d.Name]; ok {
+		m.mtx.Unlock()
+		return apperrors.New(fmt.Sprintf("saga %s was already registered", d.Name))
+	}
+	if len(m.definitions) == 0 {
+		if err := m.subscribeCommands(ctx); err != nil {
+			m.mtx.Unlock()
+			return apperrors.Wrap(err)
+		}
+	}
+	m.definitions[d.Name] = d
+	m.mtx.Unlock()
+
+	for eventType := range d.Handlers {
+		if err := m.eventBus.Subscribe(ctx, eventType, m.onEvent(d.Name)); err != nil {
+			return apperrors.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) subscribeCommands(ctx context.Context) error {
+	if err := m.commandBus.Subscribe(ctx, TimeoutName, m.onTimeout); err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := m.commandBus.Subscribe(ctx, RetryName, m.onRetry); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (m *Manager) onEvent(name string) eventbus.EventHandler {
+	return func(ctx context.Context, event *domain.Event) error {
+		// replayed events must not start processes again
+		if !executioncontext.Has(ctx, executioncontext.LIVE) {
+			return nil
+		}
+
+		return m.handle(ctx, name, event)
+	}
+}
+
+func (m *Manager) onTimeout(ctx context.Context, command domain.Command) error {
+	c, ok := command.(Timeout)
+	if !ok {
+		return apperrors.New("invalid command")
+	}
+
+	state, err := m.store.Get(ctx, c.Saga, c.CorrelationID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil
+		}
+		return apperrors.Wrap(err)
+	}
+	if state.Status != StatusRunning {
+		return nil
+	}
+
+	d, ok := m.definition(c.Saga)
+	if !ok {
+		return apperrors.Wrap(fmt.Errorf("%w: saga %s", apperrors.ErrNotFound, c.Saga))
+	}
+
+	logger.Warning(ctx, fmt.Sprintf("[Saga] %s %s timed out, compensating", c.Saga, c.CorrelationID))
+
+	state.TimeoutID = uuid.Nil
+	m.compensate(ctx, d, state)
+
+	return m.save(ctx, state)
+}
+
+func (m *Manager) onRetry(ctx context.Context, command domain.Command) error {
+	c, ok := command.(Retry)
+	if !ok || c.Event.Event == nil {
+		return apperrors.New("invalid command")
+	}
+
+	return m.handle(c.Event.Context(ctx), c.Saga, c.Event.Event)
+}
+
+func (m *Manager) handle(ctx context.Context, name string, event *domain.Event) error {
+	d, ok := m.definition(name)
+	if !ok {
+		return apperrors.Wrap(fmt.Errorf("%w: saga %s", apperrors.ErrNotFound, name))
+	}
+	handler, ok := d.Handlers[event.Type]
+	if !ok {
+		return nil
+	}
+	correlationID := d.Correlate(event)
+	if correlationID == "" {
+		return nil
+	}
+
+	state, err := m.store.Get(ctx, name, correlationID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		state, err = m.start(ctx, d, correlationID)
+	}
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+	if state.Status != StatusRunning {
+		return nil
+	}
+
+	instance := &Instance{state: state, commandBus: m.commandBus}
+
+	err = handler(ctx, instance, event)
+	switch {
+	case err == nil:
+		state.Attempts = 0
+		if instance.complete {
+			state.Status = StatusCompleted
+			m.cancelTimeout(ctx, state)
+		}
+	case commandbus.IsRetryable(err) && state.Attempts+1 < d.maxAttempts():
+		delay := retryBackoff.Duration(state.Attempts)
+		state.Attempts++
+
+		logger.Warning(ctx, fmt.Sprintf("[Saga] %s %s failed handling %s, retrying in %s: %v", name, correlationID, event.Type, delay, err))
+
+		if _, err := m.scheduler.Schedule(ctx, Retry{
+			Saga:          name,
+			CorrelationID: correlationID,
+			Event:         eventbus.NewEnvelope(ctx, event),
+		}, time.Now().Add(delay)); err != nil {
+			return apperrors.Wrap(err)
+		}
+	default:
+		logger.Error(ctx, fmt.Sprintf("[Saga] %s %s failed handling %s, compensating: %v", name, correlationID, event.Type, err))
+
+		m.compensate(ctx, d, state)
+	}
+
+	return m.save(ctx, state)
+}
+
+// start creates state of new instance scheduling its timeout
+func (m *Manager) start(ctx context.Context, d Definition, correlationID string) (*State, error) {
+	now := time.Now()
+	state := &State{
+		Name:          d.Name,
+		CorrelationID: correlationID,
+		Status:        StatusRunning,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if d.Timeout > 0 {
+		id, err := m.scheduler.Schedule(ctx, Timeout{Saga: d.Name, CorrelationID: correlationID}, now.Add(d.Timeout))
+		if err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+		state.TimeoutID = id
+	}
+
+	return state, nil
+}
+
+// compensate calls compensations of completed steps in reverse order,
+// instance fails if any of compensations fails, remaining steps are not compensated
+func (m *Manager) compensate(ctx context.Context, d Definition, state *State) {
+	m.cancelTimeout(ctx, state)
+
+	instance := &Instance{state: state, commandBus: m.commandBus}
+	for i := len(state.Steps) - 1; i >= 0; i-- {
+		step := state.Steps[i]
+
+		compensation, ok := d.Compensations[step.Name]
+		if !ok {
+			continue
+		}
+
+		if err := compensation(step.context(ctx), instance); err != nil {
+			logger.Error(ctx, fmt.Sprintf("[Saga] %s %s compensation of %s failed: %v", state.Name, state.CorrelationID, step.Name, err))
+			state.Status = StatusFailed
+			return
+		}
+	}
+
+	state.Status = StatusCompensated
+}
+
+func (m *Manager) cancelTimeout(ctx context.Context, state *State) {
+	if state.TimeoutID == uuid.Nil {
+		return
+	}
+
+	if err := m.scheduler.Cancel(ctx, state.TimeoutID); err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		logger.Error(ctx, fmt.Sprintf("[Saga] %s %s cancel timeout failed: %v", state.Name, state.CorrelationID, err))
+	}
+
+	state.TimeoutID = uuid.Nil
+}
+
+func (m *Manager) save(ctx context.Context, state *State) error {
+	state.UpdatedAt = time.Now()
+
+	if err := m.store.Save(ctx, state); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (m *Manager) definition(name string) (Definition, bool) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	d, ok := m.definitions[name]
+
+	return d, ok
+}
@@ -0,0 +1,311 @@
+package saga_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	commandbusmemory "github.com/vardius/go-api-boilerplate/pkg/commandbus/memory"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	eventbusmemory "github.com/vardius/go-api-boilerplate/pkg/eventbus/memory"
+	"github.com/vardius/go-api-boilerplate/pkg/executioncontext"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/saga"
+	sagamemory "github.com/vardius/go-api-boilerplate/pkg/saga/memory"
+)
+
+type processStarted struct {
+	ID string `json:"id"`
+}
+
+func (e processStarted) GetType() string {
+	return "saga_test.processStarted"
+}
+
+func init() {
+	if err := domain.RegisterEventFactory(processStarted{}.GetType(), func() interface{} { return &processStarted{} }); err != nil {
+		panic(err)
+	}
+}
+
+// scheduler keeps scheduled commands in memory until they are fired by test
+type scheduler struct {
+	mtx      sync.Mutex
+	commands map[uuid.UUID]domain.Command
+}
+
+func (s *scheduler) Schedule(ctx context.Context, command domain.Command, dueAt time.Time) (uuid.UUID, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	id := uuid.New()
+	s.commands[id] = command
+
+	return id, nil
+}
+
+func (s *scheduler) Cancel(ctx context.Context, id uuid.UUID) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.commands[id]; !ok {
+		return apperrors.Wrap(apperrors.ErrNotFound)
+	}
+	delete(s.commands, id)
+
+	return nil
+}
+
+// fire dispatches scheduled commands of given name decoding them as persistent scheduler does
+func (s *scheduler) fire(ctx context.Context, t *testing.T, bus commandbus.CommandBus, commandName string) int {
+	s.mtx.Lock()
+	var due []domain.Command
+	for id, c := range s.commands {
+		if c.GetName() == commandName {
+			due = append(due, c)
+			delete(s.commands, id)
+		}
+	}
+	s.mtx.Unlock()
+
+	for _, c := range due {
+		payload, err := json.Marshal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		command, err := domain.NewCommand(c.GetName(), payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := bus.Publish(ctx, command); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return len(due)
+}
+
+func (s *scheduler) count(commandName string) int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var n int
+	for _, c := range s.commands {
+		if c.GetName() == commandName {
+			n++
+		}
+	}
+
+	return n
+}
+
+type testCase struct {
+	ctx      context.Context
+	manager  *saga.Manager
+	store    saga.Store
+	bus      commandbus.CommandBus
+	eventBus interface {
+		PublishAndAcknowledge(ctx context.Context, event *domain.Event) error
+	}
+	scheduler *scheduler
+}
+
+func setUp(t *testing.T, d saga.Definition) *testCase {
+	ctx := context.Background()
+	tc := &testCase{
+		ctx:       ctx,
+		store:     sagamemory.New(),
+		bus:       commandbusmemory.New(runtime.NumCPU()),
+		scheduler: &scheduler{commands: make(map[uuid.UUID]domain.Command)},
+	}
+	eventBus := eventbusmemory.New(runtime.NumCPU())
+	tc.eventBus = eventBus
+	tc.manager = saga.New(tc.bus, eventBus, tc.scheduler, tc.store)
+
+	if err := tc.manager.Register(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+
+	return tc
+}
+
+func (tc *testCase) publish(t *testing.T, ctx context.Context, id string) {
+	event, err := domain.NewEventFromRawEvent(uuid.New(), "process", 0, &processStarted{ID: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tc.eventBus.PublishAndAcknowledge(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (tc *testCase) state(t *testing.T, id string) *saga.State {
+	state, err := tc.store.Get(tc.ctx, "process", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
+func definition(handler saga.Handler, compensations map[string]saga.Compensation) saga.Definition {
+	return saga.Definition{
+		Name: "process",
+		Correlate: func(event *domain.Event) string {
+			return event.Payload.(*processStarted).ID
+		},
+		Handlers: map[string]saga.Handler{
+			processStarted{}.GetType(): handler,
+		},
+		Compensations: compensations,
+		Timeout:       time.Minute,
+		MaxAttempts:   3,
+	}
+}
+
+func TestManagerCompletes(t *testing.T) {
+	var calls int
+	tc := setUp(t, definition(func(ctx context.Context, instance *saga.Instance, event *domain.Event) error {
+		calls++
+		if err := instance.SetData(map[string]string{"id": event.Payload.(*processStarted).ID}); err != nil {
+			return err
+		}
+		instance.Complete()
+		return nil
+	}, nil))
+
+	live := executioncontext.WithFlag(tc.ctx, executioncontext.LIVE)
+	tc.publish(t, live, "1")
+	tc.publish(t, live, "1")
+	tc.publish(t, tc.ctx, "2")
+
+	state := tc.state(t, "1")
+	if state.Status != saga.StatusCompleted || string(state.Data) != `{"id":"1"}` {
+		t.Errorf("state = %+v", state)
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, completed instance and replayed events should be ignored", calls)
+	}
+	if n := tc.scheduler.count(saga.TimeoutName); n != 0 {
+		t.Errorf("%d timeouts scheduled, expected timeout to be cancelled", n)
+	}
+	if _, err := tc.store.Get(tc.ctx, "process", "2"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, apperrors.ErrNotFound)
+	}
+}
+
+func TestManagerRetriesSkippingCompletedSteps(t *testing.T) {
+	var first, second int
+	tc := setUp(t, definition(func(ctx context.Context, instance *saga.Instance, event *domain.Event) error {
+		if err := instance.Step(ctx, "first", func(ctx context.Context) error {
+			first++
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := instance.Step(ctx, "second", func(ctx context.Context) error {
+			second++
+			if second == 1 {
+				return fmt.Errorf("%w: unavailable", apperrors.ErrTemporaryDisabled)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		instance.Complete()
+		return nil
+	}, nil))
+
+	tc.publish(t, executioncontext.WithFlag(tc.ctx, executioncontext.LIVE), "1")
+
+	if state := tc.state(t, "1"); state.Status != saga.StatusRunning || state.Attempts != 1 || len(state.Steps) != 1 {
+		t.Fatalf("state = %+v", state)
+	}
+
+	if n := tc.scheduler.fire(tc.ctx, t, tc.bus, saga.RetryName); n != 1 {
+		t.Fatalf("%d retries scheduled, want 1", n)
+	}
+
+	if state := tc.state(t, "1"); state.Status != saga.StatusCompleted || state.Attempts != 0 {
+		t.Errorf("state = %+v", state)
+	}
+	if first != 1 || second != 2 {
+		t.Errorf("steps called first=%d second=%d times", first, second)
+	}
+}
+
+func TestManagerCompensates(t *testing.T) {
+	userID := uuid.New()
+
+	var compensated []string
+	compensation := func(step string) saga.Compensation {
+		return func(ctx context.Context, instance *saga.Instance) error {
+			if i, ok := identity.FromContext(ctx); !ok || i.UserID != userID {
+				return fmt.Errorf("missing identity of step %s", step)
+			} else if i.Token != "" {
+				return fmt.Errorf("token of step %s was stored", step)
+			}
+			compensated = append(compensated, step)
+			return nil
+		}
+	}
+
+	tc := setUp(t, definition(func(ctx context.Context, instance *saga.Instance, event *domain.Event) error {
+		for _, step := range []string{"first", "second"} {
+			if err := instance.Step(ctx, step, func(ctx context.Context) error { return nil }); err != nil {
+				return err
+			}
+		}
+		return instance.Step(ctx, "third", func(ctx context.Context) error {
+			return fmt.Errorf("%w: rejected", apperrors.ErrUnprocessable)
+		})
+	}, map[string]saga.Compensation{
+		"first":  compensation("first"),
+		"second": compensation("second"),
+		"third":  compensation("third"),
+	}))
+
+	ctx := identity.ContextWithIdentity(executioncontext.WithFlag(tc.ctx, executioncontext.LIVE), &identity.Identity{Token: "token", UserID: userID})
+	tc.publish(t, ctx, "1")
+
+	if state := tc.state(t, "1"); state.Status != saga.StatusCompensated {
+		t.Errorf("state = %+v", state)
+	}
+	if fmt.Sprint(compensated) != "[second first]" {
+		t.Errorf("compensated = %v, want completed steps in reverse order", compensated)
+	}
+	if n := tc.scheduler.count(saga.TimeoutName); n != 0 {
+		t.Errorf("%d timeouts scheduled, expected timeout to be cancelled", n)
+	}
+}
+
+func TestManagerTimeout(t *testing.T) {
+	var compensated bool
+	tc := setUp(t, definition(func(ctx context.Context, instance *saga.Instance, event *domain.Event) error {
+		return instance.Step(ctx, "first", func(ctx context.Context) error { return nil })
+	}, map[string]saga.Compensation{
+		"first": func(ctx context.Context, instance *saga.Instance) error {
+			compensated = true
+			return nil
+		},
+	}))
+
+	tc.publish(t, executioncontext.WithFlag(tc.ctx, executioncontext.LIVE), "1")
+
+	if n := tc.scheduler.fire(tc.ctx, t, tc.bus, saga.TimeoutName); n != 1 {
+		t.Fatalf("%d timeouts scheduled, want 1", n)
+	}
+
+	if state := tc.state(t, "1"); state.Status != saga.StatusCompensated || !compensated {
+		t.Errorf("state = %+v, compensated = %v", state, compensated)
+	}
+}
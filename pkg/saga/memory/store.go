@@ -0,0 +1,62 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	basesaga "github.com/vardius/go-api-boilerplate/pkg/saga"
+)
+
+type store struct {
+	mtx    sync.RWMutex
+	states map[string]basesaga.State
+}
+
+// New creates in memory saga state store,
+// it can not be shared between service replicas
+func New() basesaga.Store {
+	return &store{
+		states: make(map[string]basesaga.State),
+	}
+}
+
+func (s *store) Get(ctx context.Context, name, correlationID string) (*basesaga.State, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	state, ok := s.states[key(name, correlationID)]
+	if !ok {
+		return nil, apperrors.Wrap(fmt.Errorf("%w: saga %s %s", apperrors.ErrNotFound, name, correlationID))
+	}
+
+	return clone(state), nil
+}
+
+func (s *store) Save(ctx context.Context, state *basesaga.State) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	k := key(state.Name, state.CorrelationID)
+	if current, ok := s.states[k]; (ok || state.Version != 0) && current.Version != state.Version {
+		return apperrors.Wrap(fmt.Errorf("%w: saga %s %s was modified", apperrors.ErrConflict, state.Name, state.CorrelationID))
+	}
+
+	state.Version++
+	s.states[k] = *clone(*state)
+
+	return nil
+}
+
+func key(name, correlationID string) string {
+	return name + "/" + correlationID
+}
+
+// clone copies state so stored value is not modified by handlers
+func clone(state basesaga.State) *basesaga.State {
+	state.Data = append([]byte(nil), state.Data...)
+	state.Steps = append([]basesaga.Step(nil), state.Steps...)
+
+	return &state
+}
@@ -0,0 +1,56 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	basesaga "github.com/vardius/go-api-boilerplate/pkg/saga"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	if _, err := s.Get(ctx, "saga", "1"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Fatalf("Get() error = %v, want %v", err, apperrors.ErrNotFound)
+	}
+
+	state := &basesaga.State{Name: "saga", CorrelationID: "1", Status: basesaga.StatusRunning}
+	if err := s.Save(ctx, state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Version != 1 {
+		t.Errorf("Save() version = %d, want 1", state.Version)
+	}
+
+	if err := s.Save(ctx, &basesaga.State{Name: "saga", CorrelationID: "1"}); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("Save() new instance error = %v, want %v", err, apperrors.ErrConflict)
+	}
+
+	loaded, err := s.Get(ctx, "saga", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, err := s.Get(ctx, "saga", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded.Steps = append(loaded.Steps, basesaga.Step{Name: "step"})
+	if err := s.Save(ctx, loaded); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(ctx, stale); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("Save() stale error = %v, want %v", err, apperrors.ErrConflict)
+	}
+
+	loaded, err = s.Get(ctx, "saga", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Version != 2 || len(loaded.Steps) != 1 {
+		t.Errorf("Get() = %+v", loaded)
+	}
+}
@@ -0,0 +1,140 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	basesaga "github.com/vardius/go-api-boilerplate/pkg/saga"
+)
+
+type state struct {
+	Name          string    `bson:"name"`
+	CorrelationID string    `bson:"correlation_id"`
+	Status        string    `bson:"status"`
+	Data          []byte    `bson:"data,omitempty"`
+	Steps         []byte    `bson:"steps,omitempty"`
+	Attempts      int       `bson:"attempts"`
+	TimeoutID     string    `bson:"timeout_id"`
+	Version       int       `bson:"version"`
+	CreatedAt     time.Time `bson:"created_at"`
+	UpdatedAt     time.Time `bson:"updated_at"`
+}
+
+type store struct {
+	collection *mongo.Collection
+}
+
+// New creates mongo saga state store
+func New(ctx context.Context, collectionName string, mongoDB *mongo.Database) (basesaga.Store, error) {
+	if collectionName == "" {
+		collectionName = "sagas"
+	}
+
+	collection := mongoDB.Collection(collectionName)
+
+	if _, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}, {Key: "correlation_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return nil, apperrors.Wrap(fmt.Errorf("failed to create indexes: %w", err))
+	}
+
+	return &store{collection: collection}, nil
+}
+
+func (s *store) Get(ctx context.Context, name, correlationID string) (*basesaga.State, error) {
+	var doc state
+	if err := s.collection.FindOne(ctx, bson.M{"name": name, "correlation_id": correlationID}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, apperrors.Wrap(fmt.Errorf("%w: saga %s %s", apperrors.ErrNotFound, name, correlationID))
+		}
+		return nil, apperrors.Wrap(err)
+	}
+
+	return toState(doc)
+}
+
+func (s *store) Save(ctx context.Context, st *basesaga.State) error {
+	doc, err := fromState(st)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+	doc.Version++
+
+	if st.Version == 0 {
+		if _, err := s.collection.InsertOne(ctx, doc); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return apperrors.Wrap(fmt.Errorf("%w: saga %s %s was modified", apperrors.ErrConflict, st.Name, st.CorrelationID))
+			}
+			return apperrors.Wrap(err)
+		}
+	} else {
+		result, err := s.collection.ReplaceOne(ctx, bson.M{
+			"name":           st.Name,
+			"correlation_id": st.CorrelationID,
+			"version":        st.Version,
+		}, doc)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+		if result.MatchedCount == 0 {
+			return apperrors.Wrap(fmt.Errorf("%w: saga %s %s was modified", apperrors.ErrConflict, st.Name, st.CorrelationID))
+		}
+	}
+
+	st.Version = doc.Version
+
+	return nil
+}
+
+func fromState(st *basesaga.State) (state, error) {
+	doc := state{
+		Name:          st.Name,
+		CorrelationID: st.CorrelationID,
+		Status:        string(st.Status),
+		Data:          st.Data,
+		Attempts:      st.Attempts,
+		TimeoutID:     st.TimeoutID.String(),
+		Version:       st.Version,
+		CreatedAt:     st.CreatedAt.UTC(),
+		UpdatedAt:     st.UpdatedAt.UTC(),
+	}
+	if len(st.Steps) > 0 {
+		var err error
+		if doc.Steps, err = json.Marshal(st.Steps); err != nil {
+			return doc, err
+		}
+	}
+
+	return doc, nil
+}
+
+func toState(doc state) (*basesaga.State, error) {
+	st := &basesaga.State{
+		Name:          doc.Name,
+		CorrelationID: doc.CorrelationID,
+		Status:        basesaga.Status(doc.Status),
+		Data:          doc.Data,
+		Attempts:      doc.Attempts,
+		TimeoutID:     uuid.MustParse(doc.TimeoutID),
+		Version:       doc.Version,
+		CreatedAt:     doc.CreatedAt,
+		UpdatedAt:     doc.UpdatedAt,
+	}
+	if len(doc.Steps) > 0 {
+		if err := json.Unmarshal(doc.Steps, &st.Steps); err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+	}
+
+	return st, nil
+}
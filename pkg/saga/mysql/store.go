@@ -0,0 +1,129 @@
+package saga
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	basesaga "github.com/vardius/go-api-boilerplate/pkg/saga"
+)
+
+const createTableSQLFormat = `
+CREATE TABLE IF NOT EXISTS %s
+(
+    name           VARCHAR(255) NOT NULL,
+    correlation_id VARCHAR(255) NOT NULL,
+    status         VARCHAR(32)  NOT NULL,
+    data           BLOB,
+    steps          BLOB,
+    attempts       INT          NOT NULL DEFAULT 0,
+    timeout_id     CHAR(36)     NOT NULL,
+    version        INT          NOT NULL,
+    created_at     DATETIME     NOT NULL,
+    updated_at     DATETIME     NOT NULL,
+    PRIMARY KEY (name, correlation_id)
+)
+    ENGINE = InnoDB
+    DEFAULT CHARSET = utf8
+    COLLATE = utf8_bin;
+`
+
+type store struct {
+	tableName string
+	db        *sql.DB
+}
+
+// New creates mysql saga state store
+func New(ctx context.Context, tableName string, db *sql.DB) (basesaga.Store, error) {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(createTableSQLFormat, tableName)); err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+
+	return &store{tableName: tableName, db: db}, nil
+}
+
+func (s *store) Get(ctx context.Context, name, correlationID string) (*basesaga.State, error) {
+	row := s.db.QueryRowContext(ctx,
+		"SELECT status, data, steps, attempts, timeout_id, version, created_at, updated_at FROM "+s.tableName+" WHERE name=? AND correlation_id=?",
+		name, correlationID,
+	)
+
+	state := basesaga.State{Name: name, CorrelationID: correlationID}
+	var (
+		data, steps []byte
+		timeoutID   string
+	)
+	if err := row.Scan(&state.Status, &data, &steps, &state.Attempts, &timeoutID, &state.Version, &state.CreatedAt, &state.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.Wrap(fmt.Errorf("%w: saga %s %s", apperrors.ErrNotFound, name, correlationID))
+		}
+		return nil, apperrors.Wrap(err)
+	}
+
+	state.Data = data
+	state.TimeoutID = uuid.MustParse(timeoutID)
+	if len(steps) > 0 {
+		if err := json.Unmarshal(steps, &state.Steps); err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+	}
+
+	return &state, nil
+}
+
+func (s *store) Save(ctx context.Context, state *basesaga.State) error {
+	steps, err := json.Marshal(state.Steps)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	if state.Version == 0 {
+		result, err := s.db.ExecContext(ctx,
+			"INSERT IGNORE INTO "+s.tableName+" (name, correlation_id, status, data, steps, attempts, timeout_id, version, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?)",
+			state.Name, state.CorrelationID, state.Status, []byte(state.Data), steps, state.Attempts, state.TimeoutID.String(), state.CreatedAt.UTC(), state.UpdatedAt.UTC(),
+		)
+		if err != nil {
+			return apperrors.Wrap(err)
+		}
+		if err := checkAffected(result, state); err != nil {
+			return err
+		}
+
+		state.Version = 1
+
+		return nil
+	}
+
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE "+s.tableName+" SET status=?, data=?, steps=?, attempts=?, timeout_id=?, version=version+1, updated_at=? WHERE name=? AND correlation_id=? AND version=?",
+		state.Status, []byte(state.Data), steps, state.Attempts, state.TimeoutID.String(), state.UpdatedAt.UTC(), state.Name, state.CorrelationID, state.Version,
+	)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+	if err := checkAffected(result, state); err != nil {
+		return err
+	}
+
+	state.Version++
+
+	return nil
+}
+
+// checkAffected returns conflict error if instance was inserted or modified by someone else
+func checkAffected(result sql.Result, state *basesaga.State) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+	if affected == 0 {
+		return apperrors.Wrap(fmt.Errorf("%w: saga %s %s was modified", apperrors.ErrConflict, state.Name, state.CorrelationID))
+	}
+
+	return nil
+}
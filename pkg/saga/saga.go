@@ -0,0 +1,145 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/metadata"
+)
+
+const defaultMaxAttempts = 5
+
+// Handler reacts to event of saga instance,
+// returning retryable error redelivers event later, other errors compensate instance
+type Handler func(ctx context.Context, instance *Instance, event *domain.Event) error
+
+// Compensation undoes completed step of saga instance
+type Compensation func(ctx context.Context, instance *Instance) error
+
+// Definition describes process manager
+type Definition struct {
+	// Name identifies saga, instances state is persisted per name and correlation ID
+	Name string
+	// Correlate returns ID of instance event belongs to, events with empty correlation ID are ignored
+	Correlate func(event *domain.Event) string
+	// Handlers react to events by event type, first handled event starts new instance
+	Handlers map[string]Handler
+	// Compensations undo completed steps by step name, they are called in reverse order of steps
+	Compensations map[string]Compensation
+	// Timeout after which running instance is compensated, zero value disables timeout
+	Timeout time.Duration
+	// MaxAttempts of handling event failing with retryable error before instance is compensated
+	MaxAttempts int
+}
+
+func (d Definition) maxAttempts() int {
+	if d.MaxAttempts > 0 {
+		return d.MaxAttempts
+	}
+
+	return defaultMaxAttempts
+}
+
+// Instance is a saga instance passed to handlers and compensations
+type Instance struct {
+	state      *State
+	commandBus commandbus.CommandBus
+	complete   bool
+}
+
+// CorrelationID returns ID of instance
+func (i *Instance) CorrelationID() string {
+	return i.state.CorrelationID
+}
+
+// Data decodes instance data into v, v is left unchanged if instance has no data
+func (i *Instance) Data(v interface{}) error {
+	if len(i.state.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(i.state.Data, v); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// SetData replaces instance data, data is persisted once handler returns
+func (i *Instance) SetData(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	i.state.Data = data
+
+	return nil
+}
+
+// Step runs action unless step was completed by previous attempt,
+// completed step is compensated if instance fails later
+func (i *Instance) Step(ctx context.Context, name string, action func(ctx context.Context) error) error {
+	if i.Completed(name) {
+		return nil
+	}
+
+	if err := action(ctx); err != nil {
+		return apperrors.Wrap(fmt.Errorf("step %s failed: %w", name, err))
+	}
+
+	step := Step{Name: name}
+	if m, ok := metadata.FromContext(ctx); ok {
+		step.RequestMetadata = m
+	}
+	if id, ok := identity.FromContext(ctx); ok {
+		step.Identity = id.WithoutToken()
+	}
+
+	i.state.Steps = append(i.state.Steps, step)
+
+	return nil
+}
+
+// Dispatch runs step publishing command through command bus
+func (i *Instance) Dispatch(ctx context.Context, step string, command domain.Command) error {
+	return i.Step(ctx, step, func(ctx context.Context) error {
+		return i.commandBus.Publish(ctx, command)
+	})
+}
+
+// Completed reports if step was completed
+func (i *Instance) Completed(step string) bool {
+	for _, s := range i.state.Steps {
+		if s.Name == step {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Complete marks instance as completed once handler returns without error
+func (i *Instance) Complete() {
+	i.complete = true
+}
+
+// context returns copy of parent carrying values step was completed with
+func (s Step) context(parent context.Context) context.Context {
+	ctx := parent
+	if s.RequestMetadata != nil {
+		m := *s.RequestMetadata
+		m.Now = time.Now()
+		ctx = metadata.ContextWithMetadata(ctx, &m)
+	}
+	if s.Identity != nil {
+		ctx = identity.ContextWithIdentity(ctx, s.Identity)
+	}
+
+	return ctx
+}
@@ -0,0 +1,55 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/metadata"
+)
+
+// Status of saga instance
+type Status string
+
+// Saga instance statuses
+const (
+	StatusRunning     Status = "running"
+	StatusCompleted   Status = "completed"
+	StatusCompensated Status = "compensated"
+	StatusFailed      Status = "failed" // compensation failed
+)
+
+// State is a persisted state of saga instance identified by saga name and correlation ID
+type State struct {
+	Name          string          `json:"name"`
+	CorrelationID string          `json:"correlation_id"`
+	Status        Status          `json:"status"`
+	Data          json.RawMessage `json:"data,omitempty"`
+	Steps         []Step          `json:"steps,omitempty"`
+	Attempts      int             `json:"attempts"`
+	TimeoutID     uuid.UUID       `json:"timeout_id"`
+	Version       int             `json:"version"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// Step is a completed step of saga instance,
+// step is compensated with values of context it was completed with,
+// identity is stored without token so bearer tokens are never persisted with saga state
+type Step struct {
+	Name            string             `json:"name"`
+	RequestMetadata *metadata.Metadata `json:"request_metadata,omitempty"`
+	Identity        *identity.Identity `json:"identity,omitempty"`
+}
+
+// Store persists saga instances state
+type Store interface {
+	// Get returns state of saga instance, returns apperrors.ErrNotFound if instance does not exist
+	Get(ctx context.Context, name, correlationID string) (*State, error)
+	// Save persists state incrementing its version,
+	// returns apperrors.ErrConflict if state was modified since it was loaded
+	Save(ctx context.Context, state *State) error
+}
@@ -0,0 +1,4 @@
+/*
+Package scheduler provides persistent command scheduler dispatching due commands through command bus
+*/
+package scheduler
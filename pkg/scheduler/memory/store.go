@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	basescheduler "github.com/vardius/go-api-boilerplate/pkg/scheduler"
+)
+
+type scheduledCommand struct {
+	command     basescheduler.Command
+	claimID     string
+	lockedUntil time.Time
+}
+
+type store struct {
+	mtx      sync.Mutex
+	commands map[uuid.UUID]*scheduledCommand
+}
+
+// New creates in memory scheduled command store,
+// it can not be shared between service replicas
+func New() basescheduler.Store {
+	return &store{
+		commands: make(map[uuid.UUID]*scheduledCommand),
+	}
+}
+
+func (s *store) Add(ctx context.Context, c *basescheduler.Command) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.commands[c.ID] = &scheduledCommand{command: *c}
+
+	return nil
+}
+
+func (s *store) Remove(ctx context.Context, id uuid.UUID, claimID string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if c, ok := s.commands[id]; !ok || (claimID != "" && c.claimID != claimID) {
+		return apperrors.Wrap(fmt.Errorf("%w: scheduled command %s", apperrors.ErrNotFound, id))
+	}
+
+	delete(s.commands, id)
+
+	return nil
+}
+
+func (s *store) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*basescheduler.Command, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var due []*scheduledCommand
+	for _, c := range s.commands {
+		if !c.command.DueAt.After(now) && !c.lockedUntil.After(now) {
+			due = append(due, c)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].command.DueAt.Before(due[j].command.DueAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	commands := make([]*basescheduler.Command, 0, len(due))
+	claimID := uuid.New().String()
+	for _, c := range due {
+		c.claimID = claimID
+		c.lockedUntil = now.Add(lease)
+
+		command := c.command
+		command.ClaimID = claimID
+		commands = append(commands, &command)
+	}
+
+	return commands, nil
+}
+
+func (s *store) Release(ctx context.Context, id uuid.UUID, claimID string, dueAt time.Time) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	c, ok := s.commands[id]
+	if !ok || c.claimID != claimID {
+		return apperrors.Wrap(fmt.Errorf("%w: scheduled command %s", apperrors.ErrNotFound, id))
+	}
+
+	c.command.DueAt = dueAt
+	c.command.Attempts++
+	c.claimID = ""
+	c.lockedUntil = time.Time{}
+
+	return nil
+}
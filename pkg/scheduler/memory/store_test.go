@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	basescheduler "github.com/vardius/go-api-boilerplate/pkg/scheduler"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	now := time.Now()
+
+	first := &basescheduler.Command{ID: uuid.New(), Name: "command", DueAt: now.Add(-time.Minute)}
+	second := &basescheduler.Command{ID: uuid.New(), Name: "command", DueAt: now.Add(-time.Second)}
+	future := &basescheduler.Command{ID: uuid.New(), Name: "command", DueAt: now.Add(time.Minute)}
+	for _, c := range []*basescheduler.Command{future, second, first} {
+		if err := s.Add(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	claimed, err := s.Claim(ctx, now, 1, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != first.ID {
+		t.Fatalf("Claim() = %v, want the earliest due command", claimed)
+	}
+
+	claimed, err = s.Claim(ctx, now, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != second.ID {
+		t.Fatalf("Claim() = %v, want claimed commands to be skipped", claimed)
+	}
+
+	// lease of claimed commands expired
+	claimed, err = s.Claim(ctx, now.Add(2*time.Minute), 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 3 {
+		t.Fatalf("Claim() = %v, want all commands", claimed)
+	}
+
+	// replica holding expired lease can neither release nor remove command claimed again
+	if err := s.Release(ctx, first.ID, uuid.New().String(), now); err == nil {
+		t.Error("Release() expected error for command claimed again")
+	}
+	if err := s.Remove(ctx, first.ID, uuid.New().String()); err == nil {
+		t.Error("Remove() expected error for command claimed again")
+	}
+
+	if err := s.Release(ctx, first.ID, claimed[0].ClaimID, now); err != nil {
+		t.Fatal(err)
+	}
+	claimed, err = s.Claim(ctx, now.Add(150*time.Second), 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != first.ID || claimed[0].Attempts != 1 {
+		t.Fatalf("Claim() = %v, want released command with increased attempts", claimed)
+	}
+
+	if err := s.Remove(ctx, first.ID, claimed[0].ClaimID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Remove(ctx, first.ID, ""); err == nil {
+		t.Error("Remove() expected error for removed command")
+	}
+}
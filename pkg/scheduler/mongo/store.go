@@ -0,0 +1,185 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/metadata"
+	basescheduler "github.com/vardius/go-api-boilerplate/pkg/scheduler"
+)
+
+type command struct {
+	ID              string     `bson:"command_id"`
+	Name            string     `bson:"name"`
+	Payload         []byte     `bson:"payload"`
+	RequestMetadata []byte     `bson:"request_metadata,omitempty"`
+	Identity        []byte     `bson:"identity,omitempty"`
+	Attempts        int        `bson:"attempts"`
+	DueAt           time.Time  `bson:"due_at"`
+	CreatedAt       time.Time  `bson:"created_at"`
+	ClaimID         string     `bson:"claim_id,omitempty"`
+	LockedUntil     *time.Time `bson:"locked_until,omitempty"`
+}
+
+type store struct {
+	collection *mongo.Collection
+}
+
+// New creates mongo scheduled command store,
+// commands are claimed one by one with atomic updates so store can be shared between service replicas
+func New(ctx context.Context, collectionName string, mongoDB *mongo.Database) (basescheduler.Store, error) {
+	if collectionName == "" {
+		collectionName = "scheduled_commands"
+	}
+
+	collection := mongoDB.Collection(collectionName)
+
+	if _, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "command_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "due_at", Value: 1}},
+		},
+	}); err != nil {
+		return nil, apperrors.Wrap(fmt.Errorf("failed to create indexes: %w", err))
+	}
+
+	return &store{collection: collection}, nil
+}
+
+func (s *store) Add(ctx context.Context, c *basescheduler.Command) error {
+	doc := command{
+		ID:        c.ID.String(),
+		Name:      c.Name,
+		Payload:   c.Payload,
+		Attempts:  c.Attempts,
+		DueAt:     c.DueAt.UTC(),
+		CreatedAt: c.CreatedAt.UTC(),
+	}
+	if c.RequestMetadata != nil {
+		var err error
+		if doc.RequestMetadata, err = json.Marshal(c.RequestMetadata); err != nil {
+			return apperrors.Wrap(err)
+		}
+	}
+	if c.Identity != nil {
+		var err error
+		if doc.Identity, err = json.Marshal(c.Identity); err != nil {
+			return apperrors.Wrap(err)
+		}
+	}
+
+	if _, err := s.collection.InsertOne(ctx, doc); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *store) Remove(ctx context.Context, id uuid.UUID, claimID string) error {
+	filter := bson.M{"command_id": id.String()}
+	if claimID != "" {
+		filter["claim_id"] = claimID
+	}
+
+	result, err := s.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+	if result.DeletedCount == 0 {
+		return apperrors.Wrap(fmt.Errorf("%w: scheduled command %s", apperrors.ErrNotFound, id))
+	}
+
+	return nil
+}
+
+func (s *store) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*basescheduler.Command, error) {
+	filter := bson.M{
+		"due_at": bson.M{"$lte": now.UTC()},
+		"$or": bson.A{
+			bson.M{"locked_until": bson.M{"$exists": false}},
+			bson.M{"locked_until": bson.M{"$lte": now.UTC()}},
+		},
+	}
+	claimID := uuid.New().String()
+	update := bson.M{"$set": bson.M{"claim_id": claimID, "locked_until": now.Add(lease).UTC()}}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "due_at", Value: 1}}).SetReturnDocument(options.After)
+
+	var commands []*basescheduler.Command
+	for len(commands) < limit {
+		var result command
+		if err := s.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				break
+			}
+
+			return commands, apperrors.Wrap(err)
+		}
+
+		c, err := toCommand(result)
+		if err != nil {
+			return commands, apperrors.Wrap(err)
+		}
+
+		commands = append(commands, c)
+	}
+
+	return commands, nil
+}
+
+func (s *store) Release(ctx context.Context, id uuid.UUID, claimID string, dueAt time.Time) error {
+	result, err := s.collection.UpdateOne(ctx,
+		bson.M{"command_id": id.String(), "claim_id": claimID},
+		bson.M{
+			"$set":   bson.M{"due_at": dueAt.UTC()},
+			"$inc":   bson.M{"attempts": 1},
+			"$unset": bson.M{"claim_id": "", "locked_until": ""},
+		},
+	)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+	if result.MatchedCount == 0 {
+		return apperrors.Wrap(fmt.Errorf("%w: scheduled command %s", apperrors.ErrNotFound, id))
+	}
+
+	return nil
+}
+
+func toCommand(doc command) (*basescheduler.Command, error) {
+	c := &basescheduler.Command{
+		ID:        uuid.MustParse(doc.ID),
+		Name:      doc.Name,
+		Payload:   doc.Payload,
+		Attempts:  doc.Attempts,
+		DueAt:     doc.DueAt,
+		CreatedAt: doc.CreatedAt,
+		ClaimID:   doc.ClaimID,
+	}
+	if len(doc.RequestMetadata) > 0 {
+		c.RequestMetadata = &metadata.Metadata{}
+		if err := json.Unmarshal(doc.RequestMetadata, c.RequestMetadata); err != nil {
+			return nil, err
+		}
+	}
+	if len(doc.Identity) > 0 {
+		c.Identity = &identity.Identity{}
+		if err := json.Unmarshal(doc.Identity, c.Identity); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,180 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/metadata"
+	basescheduler "github.com/vardius/go-api-boilerplate/pkg/scheduler"
+)
+
+const createTableSQLFormat = `
+CREATE TABLE IF NOT EXISTS %s
+(
+    id               CHAR(36)     NOT NULL,
+    name             VARCHAR(255) NOT NULL,
+    payload          BLOB         NOT NULL,
+    request_metadata BLOB,
+    identity         BLOB,
+    attempts         INT          NOT NULL DEFAULT 0,
+    due_at           DATETIME(6)  NOT NULL,
+    created_at       DATETIME     NOT NULL,
+    claim_id         CHAR(36),
+    locked_until     DATETIME(6),
+    PRIMARY KEY (id),
+    INDEX i_due_at (due_at),
+    INDEX i_claim_id (claim_id)
+)
+    ENGINE = InnoDB
+    DEFAULT CHARSET = utf8
+    COLLATE = utf8_bin;
+`
+
+type store struct {
+	tableName string
+	db        *sql.DB
+}
+
+// New creates mysql scheduled command store,
+// commands are claimed with single update statement so store can be shared between service replicas
+func New(ctx context.Context, tableName string, db *sql.DB) (basescheduler.Store, error) {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(createTableSQLFormat, tableName)); err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+
+	return &store{tableName: tableName, db: db}, nil
+}
+
+func (s *store) Add(ctx context.Context, c *basescheduler.Command) error {
+	var requestMetadata, i []byte
+	if c.RequestMetadata != nil {
+		var err error
+		if requestMetadata, err = json.Marshal(c.RequestMetadata); err != nil {
+			return apperrors.Wrap(err)
+		}
+	}
+	if c.Identity != nil {
+		var err error
+		if i, err = json.Marshal(c.Identity); err != nil {
+			return apperrors.Wrap(err)
+		}
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		"INSERT INTO "+s.tableName+" (id, name, payload, request_metadata, identity, attempts, due_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		c.ID.String(), c.Name, c.Payload, requestMetadata, i, c.Attempts, c.DueAt.UTC(), c.CreatedAt.UTC(),
+	); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *store) Remove(ctx context.Context, id uuid.UUID, claimID string) error {
+	var (
+		result sql.Result
+		err    error
+	)
+	if claimID == "" {
+		result, err = s.db.ExecContext(ctx, "DELETE FROM "+s.tableName+" WHERE id=?", id.String())
+	} else {
+		result, err = s.db.ExecContext(ctx, "DELETE FROM "+s.tableName+" WHERE id=? AND claim_id=?", id.String(), claimID)
+	}
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return checkAffected(result, id)
+}
+
+func (s *store) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*basescheduler.Command, error) {
+	claimID := uuid.New().String()
+
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE "+s.tableName+" SET claim_id=?, locked_until=? WHERE due_at<=? AND (locked_until IS NULL OR locked_until<=?) ORDER BY due_at LIMIT ?",
+		claimID, now.Add(lease).UTC(), now.UTC(), now.UTC(), limit,
+	)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return nil, apperrors.Wrap(err)
+	} else if affected == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, name, payload, request_metadata, identity, attempts, due_at, created_at FROM "+s.tableName+" WHERE claim_id=? ORDER BY due_at",
+		claimID,
+	)
+	if err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+	defer rows.Close()
+
+	var commands []*basescheduler.Command
+	for rows.Next() {
+		var (
+			c               basescheduler.Command
+			id              string
+			requestMetadata []byte
+			i               []byte
+		)
+		if err := rows.Scan(&id, &c.Name, &c.Payload, &requestMetadata, &i, &c.Attempts, &c.DueAt, &c.CreatedAt); err != nil {
+			return nil, apperrors.Wrap(err)
+		}
+
+		c.ID = uuid.MustParse(id)
+		c.ClaimID = claimID
+		if len(requestMetadata) > 0 {
+			c.RequestMetadata = &metadata.Metadata{}
+			if err := json.Unmarshal(requestMetadata, c.RequestMetadata); err != nil {
+				return nil, apperrors.Wrap(err)
+			}
+		}
+		if len(i) > 0 {
+			c.Identity = &identity.Identity{}
+			if err := json.Unmarshal(i, c.Identity); err != nil {
+				return nil, apperrors.Wrap(err)
+			}
+		}
+
+		commands = append(commands, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.Wrap(err)
+	}
+
+	return commands, nil
+}
+
+func (s *store) Release(ctx context.Context, id uuid.UUID, claimID string, dueAt time.Time) error {
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE "+s.tableName+" SET due_at=?, attempts=attempts+1, claim_id=NULL, locked_until=NULL WHERE id=? AND claim_id=?",
+		dueAt.UTC(), id.String(), claimID,
+	)
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return checkAffected(result, id)
+}
+
+func checkAffected(result sql.Result, id uuid.UUID) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return apperrors.Wrap(err)
+	}
+	if affected == 0 {
+		return apperrors.Wrap(fmt.Errorf("%w: scheduled command %s", apperrors.ErrNotFound, id))
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/logger"
+	"github.com/vardius/go-api-boilerplate/pkg/metadata"
+)
+
+const (
+	batchSize     = 100
+	maxRetryDelay = time.Hour
+)
+
+// Scheduler persists commands in store and polls it dispatching due commands through command bus.
+// Commands are delivered at least once: dispatch failing with internal, timeout or unknown error is retried
+// with growing delay, other application errors are logged and command is removed.
+// Commands have to be registered with domain.RegisterCommandFactory so they can be decoded from store.
+type Scheduler struct {
+	commandBus commandbus.CommandBus
+	store      Store
+	interval   time.Duration
+	lease      time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// New creates scheduler polling store every interval,
+// lease is the time command is locked for dispatch and should exceed command handling time
+func New(commandBus commandbus.CommandBus, store Store, interval, lease time.Duration) *Scheduler {
+	return &Scheduler{
+		commandBus: commandBus,
+		store:      store,
+		interval:   interval,
+		lease:      lease,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Schedule persists command to be dispatched at dueAt,
+// command is dispatched under service identity with metadata carried by ctx,
+// identity carried by ctx is stored without token to record who scheduled the command
+func (s *Scheduler) Schedule(ctx context.Context, command domain.Command, dueAt time.Time) (uuid.UUID, error) {
+	payload, err := json.Marshal(command)
+	if err != nil {
+		return uuid.Nil, apperrors.Wrap(err)
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return uuid.Nil, apperrors.Wrap(fmt.Errorf("%w: Could not generate new id: %s", apperrors.ErrInternal, err))
+	}
+
+	c := &Command{
+		ID:        id,
+		Name:      command.GetName(),
+		Payload:   payload,
+		DueAt:     dueAt,
+		CreatedAt: time.Now(),
+	}
+	if m, ok := metadata.FromContext(ctx); ok {
+		c.RequestMetadata = m
+	}
+	if i, ok := identity.FromContext(ctx); ok {
+		c.Identity = i.WithoutToken()
+	}
+
+	if err := s.store.Add(ctx, c); err != nil {
+		return uuid.Nil, apperrors.Wrap(err)
+	}
+
+	return id, nil
+}
+
+// Cancel removes scheduled command,
+// command that is being dispatched at the moment might still be delivered
+func (s *Scheduler) Cancel(ctx context.Context, id uuid.UUID) error {
+	if err := s.store.Remove(ctx, id, ""); err != nil {
+		return apperrors.Wrap(err)
+	}
+
+	return nil
+}
+
+// Start polls store for due commands until scheduler is stopped or ctx is done
+func (s *Scheduler) Start(ctx context.Context) error {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return nil
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.poll(ctx)
+		}
+	}
+}
+
+// Stop stops polling, waits for commands being dispatched
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) poll(ctx context.Context) {
+	commands, err := s.store.Claim(ctx, time.Now(), batchSize, s.lease)
+	if err != nil {
+		logger.Error(ctx, fmt.Sprintf("[Scheduler] Claim failed: %v", err))
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, c := range commands {
+		wg.Add(1)
+		go func(c *Command) {
+			defer wg.Done()
+			s.dispatch(ctx, c)
+		}(c)
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) dispatch(ctx context.Context, c *Command) {
+	command, err := domain.NewCommand(c.Name, c.Payload)
+	if err != nil {
+		// retrying will not help if command can not be decoded
+		logger.Error(ctx, fmt.Sprintf("[Scheduler] Decode %s %s failed: %v", c.Name, c.ID, err))
+		s.remove(ctx, c)
+		return
+	}
+
+	// command has to be handled before lease expires, otherwise other replica might dispatch it again
+	dispatchCtx, cancel := context.WithTimeout(c.context(ctx), s.lease)
+	defer cancel()
+
+	err = s.commandBus.Publish(dispatchCtx, command)
+	switch {
+	case err == nil:
+		s.remove(ctx, c)
+	case commandbus.IsRetryable(err):
+		delay := retryDelay(s.interval, c.Attempts)
+		logger.Error(ctx, fmt.Sprintf("[Scheduler] Dispatch %s %s failed, retrying in %s: %v", c.Name, c.ID, delay, err))
+		if err := s.store.Release(ctx, c.ID, c.ClaimID, time.Now().Add(delay)); err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			logger.Error(ctx, fmt.Sprintf("[Scheduler] Release %s %s failed: %v", c.Name, c.ID, err))
+		}
+	default:
+		logger.Warning(ctx, fmt.Sprintf("[Scheduler] Dispatch %s %s rejected: %v", c.Name, c.ID, err))
+		s.remove(ctx, c)
+	}
+}
+
+func (s *Scheduler) remove(ctx context.Context, c *Command) {
+	if err := s.store.Remove(ctx, c.ID, c.ClaimID); err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		logger.Error(ctx, fmt.Sprintf("[Scheduler] Remove %s %s failed: %v", c.Name, c.ID, err))
+	}
+}
+
+// context returns copy of parent carrying metadata command was scheduled with and service identity,
+// scheduled command is not authorized by identity of user that scheduled it as its token is not stored
+func (c *Command) context(parent context.Context) context.Context {
+	ctx := identity.ContextWithIdentity(parent, identity.Service())
+	if c.RequestMetadata != nil {
+		m := *c.RequestMetadata
+		m.Now = time.Now()
+		ctx = metadata.ContextWithMetadata(ctx, &m)
+	}
+
+	return ctx
+}
+
+// retryDelay doubles interval with every attempt up to maxRetryDelay
+func retryDelay(interval time.Duration, attempts int) time.Duration {
+	delay := interval
+	for i := 0; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+
+	return delay
+}
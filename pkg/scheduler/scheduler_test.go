@@ -0,0 +1,147 @@
+package scheduler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/commandbus"
+	memorycommandbus "github.com/vardius/go-api-boilerplate/pkg/commandbus/memory"
+	"github.com/vardius/go-api-boilerplate/pkg/domain"
+	apperrors "github.com/vardius/go-api-boilerplate/pkg/errors"
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/scheduler"
+	memoryscheduler "github.com/vardius/go-api-boilerplate/pkg/scheduler/memory"
+)
+
+type commandMock struct {
+	Value string `json:"value"`
+}
+
+func (c commandMock) GetName() string {
+	return "scheduled-command"
+}
+
+func init() {
+	if err := domain.RegisterCommandFactory("scheduled-command", func(payload []byte) (domain.Command, error) {
+		var c commandMock
+		err := json.Unmarshal(payload, &c)
+		return c, err
+	}); err != nil {
+		panic(err)
+	}
+}
+
+var _ commandbus.Scheduler = (*scheduler.Scheduler)(nil)
+
+func TestScheduler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := memorycommandbus.New(runtime.NumCPU())
+	s := scheduler.New(bus, memoryscheduler.New(), 10*time.Millisecond, time.Second)
+
+	var mtx sync.Mutex
+	var handled []string
+	userID := uuid.New()
+	failed := false
+
+	if err := bus.Subscribe(ctx, "scheduled-command", func(ctx context.Context, command domain.Command) error {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		if i, ok := identity.FromContext(ctx); !ok || !i.Role.Has(identity.RoleService) || i.Token != "" {
+			t.Error("expected service identity to be passed to handler")
+		}
+
+		c := command.(commandMock)
+		// first attempt of retried command fails with internal error
+		if c.Value == "retried" && !failed {
+			failed = true
+			return apperrors.Wrap(errors.New("temporary failure"))
+		}
+
+		handled = append(handled, c.Value)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	scheduleCtx := identity.ContextWithIdentity(ctx, &identity.Identity{Token: "token", UserID: userID})
+	now := time.Now()
+
+	if _, err := s.Schedule(scheduleCtx, commandMock{Value: "later"}, now.Add(50*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Schedule(scheduleCtx, commandMock{Value: "retried"}, now); err != nil {
+		t.Fatal(err)
+	}
+	cancelledID, err := s.Schedule(scheduleCtx, commandMock{Value: "cancelled"}, now.Add(20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Cancel(ctx, cancelledID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Cancel(ctx, cancelledID); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Cancel() = %v, want ErrNotFound", err)
+	}
+
+	go s.Start(ctx)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mtx.Lock()
+		n := len(handled)
+		mtx.Unlock()
+		if n == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stopCtx, stopCancel := context.WithTimeout(ctx, time.Second)
+	defer stopCancel()
+	if err := s.Stop(stopCtx); err != nil {
+		t.Fatal(err)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if len(handled) != 2 || handled[0] != "retried" || handled[1] != "later" {
+		t.Errorf("handled = %v, want [retried later]", handled)
+	}
+}
+
+func TestSchedulerStoresIdentityWithoutToken(t *testing.T) {
+	ctx := context.Background()
+	store := memoryscheduler.New()
+	s := scheduler.New(memorycommandbus.New(runtime.NumCPU()), store, time.Second, time.Second)
+
+	userID := uuid.New()
+	scheduleCtx := identity.ContextWithIdentity(ctx, &identity.Identity{Token: "token", UserID: userID})
+	if _, err := s.Schedule(scheduleCtx, commandMock{Value: "later"}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	commands, err := store.Claim(ctx, time.Now().Add(time.Minute), 1, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commands) != 1 || commands[0].Identity == nil {
+		t.Fatalf("Claim() = %v, want command with identity", commands)
+	}
+	if commands[0].Identity.Token != "" {
+		t.Error("expected identity to be stored without token")
+	}
+	if commands[0].Identity.UserID != userID {
+		t.Errorf("Identity.UserID = %s, want %s", commands[0].Identity.UserID, userID)
+	}
+}
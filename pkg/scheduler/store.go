@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vardius/go-api-boilerplate/pkg/identity"
+	"github.com/vardius/go-api-boilerplate/pkg/metadata"
+)
+
+// Command is a command persisted to be dispatched at DueAt
+type Command struct {
+	ID              uuid.UUID          `json:"id"`
+	Name            string             `json:"name"`
+	Payload         []byte             `json:"payload"`
+	RequestMetadata *metadata.Metadata `json:"request_metadata,omitempty"`
+	Identity        *identity.Identity `json:"identity,omitempty"`
+	Attempts        int                `json:"attempts"`
+	DueAt           time.Time          `json:"due_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+	ClaimID         string             `json:"claim_id,omitempty"` // claim command is held by, set by Store.Claim
+}
+
+// Store persists scheduled commands, it has to be safe to share one store between service replicas
+type Store interface {
+	// Add persists command
+	Add(ctx context.Context, c *Command) error
+	// Remove deletes command, cancelled commands are removed with empty claimID,
+	// otherwise command is removed only while held by claimID, returns apperrors.ErrNotFound if it does not exist or was claimed again
+	Remove(ctx context.Context, id uuid.UUID, claimID string) error
+	// Claim locks up to limit commands due at now for lease duration so no other replica claims them,
+	// claimed commands that were neither removed nor released become due again once lease expires
+	Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*Command, error)
+	// Release unlocks command held by claimID increasing its attempts so it is claimed again at dueAt,
+	// returns apperrors.ErrNotFound if it does not exist or was claimed again by other replica once lease expired
+	Release(ctx context.Context, id uuid.UUID, claimID string, dueAt time.Time) error
+}